@@ -0,0 +1,268 @@
+package serpent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// AvalancheFlip selects which input bits an AvalancheTest flips.
+type AvalancheFlip int
+
+const (
+	// FlipPlaintext flips each of the 128 plaintext bits.
+	FlipPlaintext AvalancheFlip = iota
+	// FlipKey flips each of the 256 user key bits.
+	FlipKey
+)
+
+// AvalancheTest measures diffusion empirically. For random plaintexts and
+// keys it flips each input bit in turn, runs the rounds of Params with R
+// (or RBitslice) and records which bits of the state differ after every
+// round.
+//
+// States are compared in the bitslice domain, the output of R being taken
+// through FP, so both round functions give the same matrices. The last
+// round of Params ends with key mixing rather than LT, as in Encrypt.
+type AvalancheTest struct {
+	Params   Params
+	Flip     AvalancheFlip
+	Bitslice bool
+	// Samples is the number of random plaintext and key pairs; zero
+	// means 100.
+	Samples int
+	Seed    int64
+}
+
+// AvalancheMatrix holds the results after one round: Flips[i][j] counts
+// the samples in which flipping input bit i flipped state bit j.
+type AvalancheMatrix struct {
+	Round   int
+	Samples int
+	Flips   [][]int
+}
+
+// Method Run runs the test and returns one matrix per round.
+func (a AvalancheTest) Run() ([]*AvalancheMatrix, error) {
+	if err := a.Params.Validate(); err != nil {
+		return nil, err
+	}
+	samples := a.Samples
+	if samples == 0 {
+		samples = 100
+	}
+	inputs := 128
+	if a.Flip == FlipKey {
+		inputs = 256
+	}
+	v := newVariant(a.Params)
+	result := make([]*AvalancheMatrix, a.Params.Rounds)
+	for r := range result {
+		m := &AvalancheMatrix{Round: a.Params.StartRound + r,
+			Samples: samples, Flips: make([][]int, inputs)}
+		for i := range m.Flips {
+			m.Flips[i] = make([]int, 128)
+		}
+		result[r] = m
+	}
+
+	rng := rand.New(rand.NewSource(a.Seed))
+	for s := 0; s < samples; s++ {
+		plainText := BitstringFromBytes(randomBytes(rng, 16))
+		key := randomBytes(rng, 32)
+		K, KHat := avalancheSubkeys(v, key)
+		base := a.states(v, plainText, K, KHat)
+		for i := 0; i < inputs; i++ {
+			var states []Bitstring
+			if a.Flip == FlipKey {
+				key[i/8] ^= 1 << uint(i%8)
+				KFlipped, KHatFlipped := avalancheSubkeys(v, key)
+				key[i/8] ^= 1 << uint(i%8)
+				states = a.states(v, plainText, KFlipped, KHatFlipped)
+			} else {
+				states = a.states(v, flipBit(plainText, i), K, KHat)
+			}
+			for r, state := range states {
+				for j := 0; j < 128; j++ {
+					if state[j] != base[r][j] {
+						result[r].Flips[i][j]++
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// Function avalancheSubkeys expands the 32-byte 'key', bit i of the user
+// key being bit i%8 of byte i/8, into the subkeys of 'v' in both formats.
+func avalancheSubkeys(v *variant, key []byte) (K, KHat Bitslice) {
+	w := longKeyWords(key)
+	words := v.wordSubkeys(&w)
+	K, KHat = make(Bitslice, len(words)), make(Bitslice, len(words))
+	for i := range words {
+		K[i] = wordsToBitstring(&words[i])
+		KHat[i] = IP(K[i])
+	}
+	return K, KHat
+}
+
+// Method states returns the state after each round, in the bitslice
+// domain, using the subkeys 'K' and 'KHat'.
+func (a AvalancheTest) states(v *variant, plainText Bitstring, K,
+	KHat Bitslice) []Bitstring {
+	var states []Bitstring
+	if a.Bitslice {
+		B := plainText
+		for i := v.StartRound; i <= v.lastRound(); i++ {
+			B = v.rBitslice(i, B, K)
+			states = append(states, B)
+		}
+		return states
+	}
+	BHat := IP(plainText)
+	for i := v.StartRound; i <= v.lastRound(); i++ {
+		BHat = v.r(i, BHat, KHat)
+		states = append(states, FP(BHat))
+	}
+	return states
+}
+
+// Function randomBytes returns 'n' bytes drawn from 'r'.
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+
+// Function flipBit returns 's' with bit 'i' inverted.
+func flipBit(s Bitstring, i int) Bitstring {
+	b := []byte(s)
+	b[i] ^= '0' ^ '1'
+	return Bitstring(b)
+}
+
+// Method Probability returns the fraction of samples in which flipping
+// input bit i flipped state bit j. The strict avalanche criterion asks
+// for every probability to be 1/2.
+func (m *AvalancheMatrix) Probability(i, j int) float64 {
+	return float64(m.Flips[i][j]) / float64(m.Samples)
+}
+
+// Method Dependence returns the dependency matrix: entry [i][j] is set
+// when state bit j was seen to depend on input bit i.
+func (m *AvalancheMatrix) Dependence() [][]bool {
+	d := make([][]bool, len(m.Flips))
+	for i, row := range m.Flips {
+		d[i] = make([]bool, len(row))
+		for j, n := range row {
+			d[i][j] = n > 0
+		}
+	}
+	return d
+}
+
+// Method Completeness returns the fraction of the entries of the
+// dependency matrix that are set.
+func (m *AvalancheMatrix) Completeness() float64 {
+	n := 0
+	for _, row := range m.Flips {
+		for _, f := range row {
+			if f > 0 {
+				n++
+			}
+		}
+	}
+	return float64(n) / float64(len(m.Flips)*128)
+}
+
+// Method Avalanche returns the average fraction of state bits flipped by
+// flipping one input bit; it should approach 1/2.
+func (m *AvalancheMatrix) Avalanche() float64 {
+	total := 0
+	for _, row := range m.Flips {
+		for _, f := range row {
+			total += f
+		}
+	}
+	return float64(total) / float64(len(m.Flips)*128*m.Samples)
+}
+
+// Method SACDeviation returns the mean and the largest distance between
+// the probabilities and 1/2.
+func (m *AvalancheMatrix) SACDeviation() (mean, max float64) {
+	for i, row := range m.Flips {
+		for j := range row {
+			d := math.Abs(m.Probability(i, j) - 0.5)
+			mean += d
+			if d > max {
+				max = d
+			}
+		}
+	}
+	mean /= float64(len(m.Flips) * 128)
+	return
+}
+
+// Method WriteText writes a one line summary of the matrix.
+func (m *AvalancheMatrix) WriteText(w io.Writer) error {
+	mean, max := m.SACDeviation()
+	_, err := fmt.Fprintf(w, "round %2d: completeness %.4f  avalanche "+
+		"%.4f  SAC deviation mean %.4f max %.4f\n", m.Round,
+		m.Completeness(), m.Avalanche(), mean, max)
+	return err
+}
+
+// Heatmap selects what AvalancheMatrix.WriteSVG draws.
+type Heatmap int
+
+const (
+	// DependenceHeatmap draws dependent bits in black.
+	DependenceHeatmap Heatmap = iota
+	// SACHeatmap draws probabilities of 1/2 in white, lower ones in blue
+	// and higher ones in red.
+	SACHeatmap
+)
+
+// Method WriteSVG draws the matrix as an SVG heatmap, input bits down and
+// state bits across.
+func (m *AvalancheMatrix) WriteSVG(w io.Writer, kind Heatmap) error {
+	const cell = 4
+	bw := bufio.NewWriter(w)
+	width, height := 128*cell, len(m.Flips)*cell
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" "+
+		"width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width, height, width, height)
+	fmt.Fprintf(bw, "<title>round %d</title>\n", m.Round)
+	fmt.Fprintf(bw, "<rect width=\"%d\" height=\"%d\" fill=\"#fff\"/>\n",
+		width, height)
+	for i, row := range m.Flips {
+		for j, f := range row {
+			var color string
+			if kind == DependenceHeatmap {
+				if f == 0 {
+					continue
+				}
+				color = "#000"
+			} else {
+				color = sacColor(m.Probability(i, j))
+			}
+			fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" "+
+				"height=\"%d\" fill=\"%s\"/>\n", j*cell, i*cell, cell, cell,
+				color)
+		}
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+// Function sacColor maps a probability onto a blue, white and red scale.
+func sacColor(p float64) string {
+	d := int(math.Min(math.Abs(p-0.5)*2, 1) * 255)
+	if p < 0.5 {
+		return fmt.Sprintf("#%02x%02xff", 255-d, 255-d)
+	}
+	return fmt.Sprintf("#ff%02x%02x", 255-d, 255-d)
+}
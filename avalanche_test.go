@@ -0,0 +1,89 @@
+package serpent
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+// Function TestAvalanche checks the diffusion of the first rounds: one
+// round leaves most bits independent, three rounds reach every bit, and
+// the avalanche approaches 1/2.
+func TestAvalanche(t *testing.T) {
+	p := Serpent1
+	p.Rounds = 4
+	ms, err := AvalancheTest{Params: p, Samples: 8, Seed: 1}.Run()
+	if err != nil {
+		t.Fatalf("Run failed: %v\n", err)
+	}
+	if len(ms) != 4 {
+		t.Fatalf("%d matrices, want 4\n", len(ms))
+	}
+	if c := ms[0].Completeness(); c > 0.25 {
+		t.Errorf("one round completeness %v\n", c)
+	}
+	if c := ms[2].Completeness(); c < 0.99 {
+		t.Errorf("three round completeness %v\n", c)
+	}
+	if a := ms[3].Avalanche(); a < 0.45 || a > 0.55 {
+		t.Errorf("four round avalanche %v\n", a)
+	}
+	// Flipping bit 0 only reaches the bits of S-Box 0 before LT, so the
+	// state after one round depends on few bits.
+	n := 0
+	for _, dependent := range ms[0].Dependence()[0] {
+		if dependent {
+			n++
+		}
+	}
+	if n == 0 || n > 4*7 {
+		t.Errorf("bit 0 reaches %d bits after one round\n", n)
+	}
+}
+
+// Function TestAvalancheBitslice checks that R and RBitslice give the same
+// matrices, for plaintext and key flips.
+func TestAvalancheBitslice(t *testing.T) {
+	p := Serpent1
+	p.StartRound, p.Rounds = 3, 2
+	for _, flip := range []AvalancheFlip{FlipPlaintext, FlipKey} {
+		a := AvalancheTest{Params: p, Flip: flip, Samples: 2, Seed: 5}
+		normal, _ := a.Run()
+		a.Bitslice = true
+		bitslice, _ := a.Run()
+		for r := range normal {
+			for i := range normal[r].Flips {
+				for j := range normal[r].Flips[i] {
+					if normal[r].Flips[i][j] != bitslice[r].Flips[i][j] {
+						t.Fatalf("flip %d round %d: matrices differ at "+
+							"[%d][%d]\n", flip, r, i, j)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Function TestAvalancheSVG checks that the heatmaps are well-formed XML.
+func TestAvalancheSVG(t *testing.T) {
+	p := Serpent1
+	p.Rounds = 1
+	ms, _ := AvalancheTest{Params: p, Samples: 2}.Run()
+	for _, kind := range []Heatmap{DependenceHeatmap, SACHeatmap} {
+		var buf bytes.Buffer
+		if err := ms[0].WriteSVG(&buf, kind); err != nil {
+			t.Fatalf("WriteSVG failed: %v\n", err)
+		}
+		d := xml.NewDecoder(&buf)
+		for {
+			_, err := d.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Errorf("heatmap %d is not XML: %v\n", kind, err)
+				}
+				break
+			}
+		}
+	}
+}
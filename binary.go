@@ -1,132 +1,445 @@
 package serpent
 
-type uint32le [4]byte
-type uint128le [16]byte
-type uint256le [32]byte
-type bitsliceNew []uint32le
-
-const (
-	digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
 )
 
-var shifts = [len(digits) + 1]uint{
-	1 << 1: 1,
-	1 << 2: 2,
-	1 << 3: 3,
-	1 << 4: 4,
-	1 << 5: 5,
+// Uint32le, Uint128le and Uint256le hold 32, 128 and 256 bits as
+// little-endian bytes: bit j of byte i is bit 8*i+j, the bit at index
+// 8*i+j of the equivalent Bitstring. A Uint128le therefore holds the same
+// bytes as Bitstring.Bytes and the byte API, and formats as the same
+// Hexstring as the Bitstring.
+type Uint32le [4]byte
+type Uint128le [16]byte
+type Uint256le [32]byte
+
+// Function NewUint32le returns 'x' as a Uint32le.
+func NewUint32le(x uint32) (u Uint32le) {
+	binary.LittleEndian.PutUint32(u[:], x)
+	return
+}
+
+// Function ParseUint32le parses a Hexstring of 8 hexadecimal digits, most
+// significant first.
+func ParseUint32le(h Hexstring) (u Uint32le, err error) {
+	err = parseLE(u[:], h)
+	return
+}
+
+// Function Uint32leFromBitstring converts a 32-bit Bitstring.
+func Uint32leFromBitstring(s Bitstring) (u Uint32le, err error) {
+	err = fromBitstringLE(u[:], s)
+	return
+}
+
+// Function Uint128leFromUint64 returns the 128-bit value of 'n'.
+func Uint128leFromUint64(n uint64) (u Uint128le) {
+	binary.LittleEndian.PutUint64(u[:], n)
+	return
+}
+
+// Function ParseUint128le parses a Hexstring of 32 hexadecimal digits,
+// most significant first.
+func ParseUint128le(h Hexstring) (u Uint128le, err error) {
+	err = parseLE(u[:], h)
+	return
+}
+
+// Function ParseUint256le parses a Hexstring of 64 hexadecimal digits,
+// most significant first.
+func ParseUint256le(h Hexstring) (u Uint256le, err error) {
+	err = parseLE(u[:], h)
+	return
+}
+
+// Function Uint128leFromBitstring converts a 128-bit Bitstring.
+func Uint128leFromBitstring(s Bitstring) (u Uint128le, err error) {
+	err = fromBitstringLE(u[:], s)
+	return
+}
+
+// Function Uint256leFromBitstring converts a 256-bit Bitstring.
+func Uint256leFromBitstring(s Bitstring) (u Uint256le, err error) {
+	err = fromBitstringLE(u[:], s)
+	return
 }
 
-// Function newFromUint64 creates a uint128le from uint64 'num'.
-func newFromUint64(num uint64) (result uint128le) {
-	for i := 0; i < 8; i++ {
-		result[i] = byte(num & 0xff)
-		num >>= 8
+// Function parseLE fills 'b' from the big-endian hexadecimal 'h'.
+func parseLE(b []byte, h Hexstring) error {
+	if len(h) != 2*len(b) {
+		return fmt.Errorf("serpent: %d hexadecimal digits, want %d",
+			len(h), 2*len(b))
 	}
-	return result
+	if _, err := hex.Decode(b, []byte(h)); err != nil {
+		return fmt.Errorf("serpent: %v", err)
+	}
+	reverse(b)
+	return nil
 }
 
-// Function newUint32le takes a []byte and returns a uint32le.
-func newUint32le(num []byte) uint32le {
-	var result uint32le
-	for i, _ := range num {
-		result[i] = num[i]
+// Function fromBitstringLE fills 'b' from the Bitstring 's'.
+func fromBitstringLE(b []byte, s Bitstring) error {
+	if len(s) != 8*len(b) {
+		return fmt.Errorf("serpent: Bitstring has %d bits, want %d",
+			len(s), 8*len(b))
+	}
+	if err := checkBits(s); err != nil {
+		return err
 	}
+	copy(b, s.Bytes())
+	return nil
+}
 
-	return result
+// Function hexLE formats the little-endian 'b' as a Hexstring.
+func hexLE(b []byte) Hexstring {
+	r := append([]byte(nil), b...)
+	reverse(r)
+	return Hexstring(hex.EncodeToString(r))
 }
 
-// Function xor performs a sequential Xor operation on the elements
-// in 'args'.
-func xorNEW(args []uint128le) *uint128le {
-	result := &args[0]
-	for i := 1; i < len(args); i++ {
-		result.binaryXorNEW(args[i])
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
 	}
-	return result
 }
 
-// Method quadSplit splits a 128-bit bitstring into a list of 4 32-bit
-// bitstrings.
-func (num uint128le) quadSplitNEW() []uint32le {
-	result := bitsliceNew{newUint32le(num[0:3]),
-		newUint32le(num[4:7]),
-		newUint32le(num[8:11]),
-		newUint32le(num[12:15])}
-	return result
+// Method Uint32 returns the value of 'u'.
+func (u Uint32le) Uint32() uint32 {
+	return binary.LittleEndian.Uint32(u[:])
 }
 
-// Method binaryXor performs a Xor operation between 'num' and 'other'.
-func (num *uint128le) binaryXorNEW(other uint128le) {
-	for i := 0; i < 16; i++ {
-		num[i] ^= other[i]
-	}
-}
-
-// Method String returns 'num' as a string using shifts and a mask
-func (num uint128le) String() string {
-	s := shifts[2]
-	var a [128]byte
-	i := 0
-	m := uintptr(1)
-	for j := 0; j < len(num); j++ {
-		for l := 0; l < 8; l++ {
-			for num[j] > 1 {
-				a[i] = digits[uintptr(num[j])&m]
-				num[j] >>= s
-				i++
-				l++
-			}
-			if l < 7 && num[j] == 1 {
-				a[i] = digits[uintptr(num[j])]
-				num[j]--
-			} else {
-				a[i] = digits[uintptr(num[j])]
-			}
-			i++
-		}
+// Method Xor returns 'u' xor 'other'.
+func (u Uint32le) Xor(other Uint32le) Uint32le {
+	return NewUint32le(u.Uint32() ^ other.Uint32())
+}
+
+// Method RotateLeft rotates 'u' towards its most significant bit, as
+// Bitstring.RotateLeft does.
+func (u Uint32le) RotateLeft(places int) Uint32le {
+	return NewUint32le(bits.RotateLeft32(u.Uint32(), places))
+}
+
+// Method RotateRight rotates 'u' towards its least significant bit.
+func (u Uint32le) RotateRight(places int) Uint32le {
+	return u.RotateLeft(-places)
+}
+
+// Method Bitstring converts 'u' into a 32-bit Bitstring.
+func (u Uint32le) Bitstring() Bitstring {
+	return BitstringFromBytes(u[:])
+}
+
+// Method Hexstring formats 'u' as 8 hexadecimal digits.
+func (u Uint32le) Hexstring() Hexstring {
+	return hexLE(u[:])
+}
+
+func (u Uint32le) String() string {
+	return string(u.Hexstring())
+}
+
+// Method halves returns the low and high 64 bits of 'u'.
+func (u Uint128le) halves() (lo, hi uint64) {
+	return binary.LittleEndian.Uint64(u[:8]), binary.LittleEndian.Uint64(u[8:])
+}
+
+func fromHalves(lo, hi uint64) (u Uint128le) {
+	binary.LittleEndian.PutUint64(u[:8], lo)
+	binary.LittleEndian.PutUint64(u[8:], hi)
+	return
+}
+
+// Method Xor returns 'u' xor 'other'.
+func (u Uint128le) Xor(other Uint128le) (result Uint128le) {
+	for i := range u {
+		result[i] = u[i] ^ other[i]
 	}
-	result := string(a[0:])
-	return result
+	return
 }
 
-func (bstr *Bitstring) pad(l int) {
-	b := make([]byte, l)
-	for i := 0; i < l; i++ {
-		b[i] = 48
+// Function XorUint128le returns the xor of all of 'args'.
+func XorUint128le(args ...Uint128le) (result Uint128le) {
+	for _, a := range args {
+		result = result.Xor(a)
 	}
-	*bstr = Bitstring(b) + *bstr
+	return
 }
 
-func fromBitstring(b Bitstring) uint128le {
-	if len(b) < 128 {
-		b.pad(128 - len(b))
+// Method RotateLeft rotates 'u' towards its most significant bit, as
+// Bitstring.RotateLeft does.
+func (u Uint128le) RotateLeft(places int) Uint128le {
+	places = (places%128 + 128) % 128
+	lo, hi := u.halves()
+	if places >= 64 {
+		lo, hi = hi, lo
+		places -= 64
 	}
-	var result uint128le
-	var j int = 0
-	sl := make([]byte, 8)
-	var tempNum byte = 0
-	pt := &tempNum
-	for i := 0; i < 128; {
-		for p, t := range b[i : i+8] {
-			if t == 49 {
-				sl[p] = 1
-			} else {
-				sl[p] = 0
-			}
-		}
-		*pt = 0
-		for q := 7; q >= 0; q-- {
-			if q == 7 && sl[q] == 0 {
-				continue
-			} else if sl[q] == 1 {
-				*pt += 1 << uint(q)
-			}
+	if places == 0 {
+		return fromHalves(lo, hi)
+	}
+	n := uint(places)
+	return fromHalves(lo<<n|hi>>(64-n), hi<<n|lo>>(64-n))
+}
+
+// Method RotateRight rotates 'u' towards its least significant bit.
+func (u Uint128le) RotateRight(places int) Uint128le {
+	return u.RotateLeft(-places)
+}
+
+// Method QuadSplit splits 'u' into 4 32-bit values, least significant
+// first, as Bitstring.QuadSplit does.
+func (u Uint128le) QuadSplit() (q [4]Uint32le) {
+	for k := range q {
+		copy(q[k][:], u[4*k:])
+	}
+	return
+}
+
+// Function QuadJoinUint128le joins 4 32-bit values, least significant
+// first, into a 128-bit one.
+func QuadJoinUint128le(q [4]Uint32le) (u Uint128le) {
+	for k := range q {
+		copy(u[4*k:], q[k][:])
+	}
+	return
+}
+
+// Method Bitstring converts 'u' into a 128-bit Bitstring.
+func (u Uint128le) Bitstring() Bitstring {
+	return BitstringFromBytes(u[:])
+}
+
+// Method Hexstring formats 'u' as 32 hexadecimal digits.
+func (u Uint128le) Hexstring() Hexstring {
+	return hexLE(u[:])
+}
+
+func (u Uint128le) String() string {
+	return string(u.Hexstring())
+}
+
+// Method Xor returns 'u' xor 'other'.
+func (u Uint256le) Xor(other Uint256le) (result Uint256le) {
+	for i := range u {
+		result[i] = u[i] ^ other[i]
+	}
+	return
+}
+
+// Method RotateLeft rotates 'u' towards its most significant bit, as
+// Bitstring.RotateLeft does.
+func (u Uint256le) RotateLeft(places int) (result Uint256le) {
+	places = (places%256 + 256) % 256
+	var x [4]uint64
+	for k := range x {
+		x[k] = binary.LittleEndian.Uint64(u[8*k:])
+	}
+	limbs, n := places/64, uint(places%64)
+	for k := range x {
+		// Limb k takes limb k-limbs shifted up and the top bits of the
+		// limb below it; a shift by 64 gives 0.
+		y := x[(k-limbs+4)%4]<<n | x[(k-limbs+3)%4]>>(64-n)
+		binary.LittleEndian.PutUint64(result[8*k:], y)
+	}
+	return
+}
+
+// Method RotateRight rotates 'u' towards its least significant bit.
+func (u Uint256le) RotateRight(places int) Uint256le {
+	return u.RotateLeft(-places)
+}
+
+// Method Split splits 'u' into 8 32-bit values, least significant first,
+// the words of a user key that the key schedule starts from. It is the
+// 256-bit counterpart of QuadSplit.
+func (u Uint256le) Split() (w [8]Uint32le) {
+	for k := range w {
+		copy(w[k][:], u[4*k:])
+	}
+	return
+}
+
+// Function JoinUint256le joins 8 32-bit values, least significant first,
+// into a 256-bit one.
+func JoinUint256le(w [8]Uint32le) (u Uint256le) {
+	for k := range w {
+		copy(u[4*k:], w[k][:])
+	}
+	return
+}
+
+// Method Bitstring converts 'u' into a 256-bit Bitstring.
+func (u Uint256le) Bitstring() Bitstring {
+	return BitstringFromBytes(u[:])
+}
+
+// Method Hexstring formats 'u' as 64 hexadecimal digits.
+func (u Uint256le) Hexstring() Hexstring {
+	return hexLE(u[:])
+}
+
+func (u Uint256le) String() string {
+	return string(u.Hexstring())
+}
+
+// Function MakeSubkeysUint128le expands the 256-bit 'userKey' into the 33
+// subkeys of Serpent-1 in bitslice format.
+func MakeSubkeysUint128le(userKey Uint256le) []Uint128le {
+	var w [8]uint32
+	for k := range w {
+		w[k] = binary.LittleEndian.Uint32(userKey[4*k:])
+	}
+	words := serpent1.wordSubkeys(&w)
+	K := make([]Uint128le, len(words))
+	for i := range words {
+		storeWords(K[i][:], &words[i])
+	}
+	return K
+}
+
+// Function MakeSubkeysHatUint128le expands the 256-bit 'userKey' into the
+// 33 subkeys of Serpent-1 in the format of the normal algorithm.
+func MakeSubkeysHatUint128le(userKey Uint256le) []Uint128le {
+	KHat := MakeSubkeysUint128le(userKey)
+	for i := range KHat {
+		KHat[i] = IPUint128le(KHat[i])
+	}
+	return KHat
+}
+
+// Method bit returns bit 'i' of 'u' as 0 or 1.
+func (u Uint128le) bit(i int) byte {
+	return u[i/8] >> uint(i%8) & 1
+}
+
+// Function permuteUint128le applies 'table', in the format of IPTable, to
+// 'u'.
+func permuteUint128le(table []int, u Uint128le) (result Uint128le) {
+	for i, j := range table {
+		result[i/8] |= u.bit(j) << uint(i%8)
+	}
+	return
+}
+
+// Function IPUint128le is IP on Uint128le values.
+func IPUint128le(u Uint128le) Uint128le {
+	return permuteUint128le(IPTable, u)
+}
+
+// Function FPUint128le is FP on Uint128le values.
+func FPUint128le(u Uint128le) Uint128le {
+	return permuteUint128le(FPTable, u)
+}
+
+// Function ltTableUint128le applies the linear transformation 'table', in
+// the format of LTTable, to 'u'.
+func ltTableUint128le(table []Ttable, u Uint128le) (result Uint128le) {
+	for i, positions := range table {
+		var b byte
+		for _, j := range positions {
+			b ^= u.bit(j)
 		}
-		result[j] = tempNum
-		j++
-		i += 8
+		result[i/8] |= b << uint(i%8)
 	}
+	return
+}
+
+// Function sHatUint128le applies 'sbox' to each of the 32 nibbles of 'u',
+// nibble j being bits 4*j to 4*j+3.
+func sHatUint128le(sbox SBox, u Uint128le) (result Uint128le) {
+	for i, b := range u {
+		result[i] = byte(sbox[b&15]) | byte(sbox[b>>4])<<4
+	}
+	return
+}
+
+// Function RUint128le is R on Uint128le values: round 'i' of the normal
+// algorithm using the subkeys 'KHat'.
+func RUint128le(i int, BHati Uint128le, KHat []Uint128le) Uint128le {
+	v := serpent1
+	last := v.lastRound()
+	x := sHatUint128le(v.SBoxes[v.box(i)], BHati.Xor(KHat[i]))
+	if i == last {
+		return x.Xor(KHat[last+1])
+	}
+	return ltTableUint128le(LTTable, x)
+}
 
+// Function RInverseUint128le is RInverse on Uint128le values.
+func RInverseUint128le(i int, BHatiPlus1 Uint128le,
+	KHat []Uint128le) Uint128le {
+	v := serpent1
+	last := v.lastRound()
+	x := BHatiPlus1
+	if i == last {
+		x = x.Xor(KHat[last+1])
+	} else {
+		x = ltTableUint128le(LTTableInverse, x)
+	}
+	return sHatUint128le(v.SBoxes[v.box(i)].Inverse(), x).Xor(KHat[i])
+}
+
+// Function RBitsliceUint128le is RBitslice on Uint128le values.
+func RBitsliceUint128le(i int, Bi Uint128le, K []Uint128le) Uint128le {
+	v := serpent1
+	last := v.lastRound()
+	x := loadWords(Bi[:])
+	k := loadWords(K[i][:])
+	xorWords(&x, &k)
+	v.sWords(i, &x)
+	if i == last {
+		k = loadWords(K[last+1][:])
+		xorWords(&x, &k)
+	} else {
+		v.ltWords(&x)
+	}
+	var result Uint128le
+	storeWords(result[:], &x)
+	return result
+}
+
+// Function RBitsliceInverseUint128le is RBitsliceInverse on Uint128le
+// values.
+func RBitsliceInverseUint128le(i int, BiPlus1 Uint128le,
+	K []Uint128le) Uint128le {
+	v := serpent1
+	last := v.lastRound()
+	x := loadWords(BiPlus1[:])
+	if i == last {
+		k := loadWords(K[last+1][:])
+		xorWords(&x, &k)
+	} else {
+		v.ltWordsInverse(&x)
+	}
+	v.sWordsInverse(i, &x)
+	k := loadWords(K[i][:])
+	xorWords(&x, &k)
+	var result Uint128le
+	storeWords(result[:], &x)
 	return result
 }
+
+// Function EncryptUint128le encrypts 'plainText' with the 256-bit
+// 'userKey' by the bitslice algorithm on Uint128le values.
+func EncryptUint128le(plainText Uint128le, userKey Uint256le) Uint128le {
+	K := MakeSubkeysUint128le(userKey)
+	B := plainText
+	for i := 0; i < round; i++ {
+		B = RBitsliceUint128le(i, B, K)
+	}
+	return B
+}
+
+// Function DecryptUint128le decrypts 'cipherText' with the 256-bit
+// 'userKey' by the bitslice algorithm on Uint128le values.
+func DecryptUint128le(cipherText Uint128le, userKey Uint256le) Uint128le {
+	K := MakeSubkeysUint128le(userKey)
+	B := cipherText
+	for i := round - 1; i >= 0; i-- {
+		B = RBitsliceInverseUint128le(i, B, K)
+	}
+	return B
+}
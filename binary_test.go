@@ -0,0 +1,147 @@
+package serpent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function TestUint128leConversions checks the conversions to and from
+// Bitstring and Hexstring against the Bitstring implementation.
+func TestUint128leConversions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		s := BitstringFromBytes(randomBytes(r, 16))
+		u, err := Uint128leFromBitstring(s)
+		if err != nil {
+			t.Fatalf("Uint128leFromBitstring failed: %v\n", err)
+		}
+		if u.Bitstring() != s {
+			t.Errorf("Bitstring does not round trip\n")
+		}
+		if u.Hexstring() != s.ToHexstring() {
+			t.Errorf("Hexstring = %s, want %s\n", u, s.ToHexstring())
+		}
+		back, err := ParseUint128le(u.Hexstring())
+		if err != nil || back != u {
+			t.Errorf("ParseUint128le does not round trip: %v\n", err)
+		}
+		q := u.QuadSplit()
+		for k, w := range s.QuadSplit() {
+			if q[k].Bitstring() != w {
+				t.Errorf("QuadSplit word %d differs\n", k)
+			}
+		}
+		if QuadJoinUint128le(q) != u {
+			t.Errorf("QuadJoinUint128le does not undo QuadSplit\n")
+		}
+		n := r.Intn(300) - 150
+		if n >= 0 && u.RotateLeft(n).Bitstring() != s.RotateLeft(n%128) {
+			t.Errorf("RotateLeft(%d) differs\n", n)
+		}
+		if u.RotateLeft(n).RotateRight(n) != u {
+			t.Errorf("RotateRight(%d) does not undo RotateLeft\n", n)
+		}
+		w := q[0]
+		if w.RotateLeft(11).Bitstring() != w.Bitstring().RotateLeft(11) {
+			t.Errorf("Uint32le RotateLeft differs\n")
+		}
+
+		back32, err := ParseUint32le(w.Hexstring())
+		if err != nil || back32 != w {
+			t.Errorf("ParseUint32le does not round trip: %v\n", err)
+		}
+		if w32, err := Uint32leFromBitstring(w.Bitstring()); err != nil ||
+			w32 != w {
+			t.Errorf("Uint32leFromBitstring does not round trip: %v\n",
+				err)
+		}
+
+		key := BitstringFromBytes(randomBytes(r, 32))
+		k, _ := Uint256leFromBitstring(key)
+		if k.Bitstring() != key || k.Hexstring() != key.ToHexstring() {
+			t.Errorf("Uint256le does not round trip\n")
+		}
+		n = r.Intn(600) - 300
+		if n >= 0 && k.RotateLeft(n).Bitstring() != key.RotateLeft(n%256) {
+			t.Errorf("Uint256le RotateLeft(%d) differs\n", n)
+		}
+		if k.RotateLeft(n).RotateRight(n) != k {
+			t.Errorf("Uint256le RotateRight(%d) does not undo "+
+				"RotateLeft\n", n)
+		}
+		words := k.Split()
+		for j := range words {
+			if words[j].Bitstring() != key[32*j:32*j+32] {
+				t.Errorf("Split word %d differs\n", j)
+			}
+		}
+		if JoinUint256le(words) != k {
+			t.Errorf("JoinUint256le does not undo Split\n")
+		}
+	}
+	if _, err := Uint128leFromBitstring("0101"); err == nil {
+		t.Errorf("short Bitstring accepted\n")
+	}
+	if _, err := ParseUint128le("zz"); err == nil {
+		t.Errorf("bad Hexstring accepted\n")
+	}
+	if s := Uint128leFromUint64(0xab).String(); s !=
+		"000000000000000000000000000000ab" {
+		t.Errorf("String = %s\n", s)
+	}
+}
+
+// Function TestEncryptUint128le checks the round functions on Uint128le
+// values against the Bitstring implementation.
+func TestEncryptUint128le(t *testing.T) {
+	long := makeLongkey(bs)
+	key, _ := Uint256leFromBitstring(long)
+	plain, _ := Uint128leFromBitstring(testPlainText)
+	K, _ := makeSubkeys(long)
+	KU := MakeSubkeysUint128le(key)
+	for i := range K {
+		if KU[i].Bitstring() != K[i] {
+			t.Errorf("subkey %d differs\n", i)
+		}
+	}
+	if RBitsliceUint128le(0, plain, KU).Bitstring() !=
+		RBitslice(0, testPlainText, K) {
+		t.Errorf("RBitsliceUint128le differs from RBitslice\n")
+	}
+	c := EncryptUint128le(plain, key)
+	if c.Bitstring() != EncryptBitslice(testPlainText, long) {
+		t.Errorf("EncryptUint128le differs from EncryptBitslice\n")
+	}
+	if DecryptUint128le(c, key) != plain {
+		t.Errorf("DecryptUint128le does not yield plainText\n")
+	}
+	// The normal algorithm, end to end.
+	_, KHat := makeSubkeys(long)
+	KHatU := MakeSubkeysHatUint128le(key)
+	for i := range KHat {
+		if KHatU[i].Bitstring() != KHat[i] {
+			t.Errorf("subkey hat %d differs\n", i)
+		}
+	}
+	if IPUint128le(plain).Bitstring() != IP(testPlainText) ||
+		FPUint128le(plain).Bitstring() != FP(testPlainText) {
+		t.Errorf("IPUint128le or FPUint128le differs\n")
+	}
+	if RUint128le(0, plain, KHatU).Bitstring() !=
+		R(0, testPlainText, KHat) {
+		t.Errorf("RUint128le differs from R\n")
+	}
+	B := IPUint128le(plain)
+	for i := 0; i < round; i++ {
+		B = RUint128le(i, B, KHatU)
+	}
+	if FPUint128le(B) != c {
+		t.Errorf("the normal rounds give %s, want %s\n", FPUint128le(B), c)
+	}
+	for i := round - 1; i >= 0; i-- {
+		B = RInverseUint128le(i, B, KHatU)
+	}
+	if FPUint128le(B) != plain {
+		t.Errorf("RInverseUint128le does not undo RUint128le\n")
+	}
+}
@@ -0,0 +1,240 @@
+package serpent
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// BitVector is a packed sequence of bits with the methods of Bitstring.
+// Bit i is bit i%64 of word i/64, so index 0 is the least significant bit
+// as in a Bitstring, and bits beyond the length are kept 0. Like Bitstring
+// values, BitVectors are never modified once built.
+type BitVector struct {
+	n int
+	w []uint64
+}
+
+// BitVectors is a list of BitVectors, the counterpart of Bitslice.
+type BitVectors []BitVector
+
+// Function NewBitVector returns a BitVector of 'n' zero bits.
+func NewBitVector(n int) BitVector {
+	return BitVector{n: n, w: make([]uint64, (n+63)/64)}
+}
+
+// Function BitVectorFromBitstring packs the Bitstring 's'.
+func BitVectorFromBitstring(s Bitstring) BitVector {
+	v := NewBitVector(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '1' {
+			v.w[i/64] |= 1 << uint(i%64)
+		}
+	}
+	return v
+}
+
+// Method Bitstring unpacks 'v'.
+func (v BitVector) Bitstring() Bitstring {
+	b := make([]byte, v.n)
+	for i := range b {
+		b[i] = '0' + byte(v.Bit(i))
+	}
+	return Bitstring(b)
+}
+
+func (v BitVector) String() string {
+	return string(v.Bitstring())
+}
+
+// Method Len returns the number of bits of 'v'.
+func (v BitVector) Len() int {
+	return v.n
+}
+
+// Method Bit returns bit 'i' of 'v' as 0 or 1.
+func (v BitVector) Bit(i int) int {
+	return int(v.w[i/64] >> uint(i%64) & 1)
+}
+
+// Method Int returns the value of 'v', which must have at most 63 bits.
+// It is the inverse of FromInt.
+func (v BitVector) Int() int {
+	if v.n == 0 {
+		return 0
+	}
+	return int(v.w[0])
+}
+
+// Method ByteSlice returns the bits of 'v' as the characters '0' and '1',
+// as Bitstring.ByteSlice does.
+func (v BitVector) ByteSlice() []byte {
+	return []byte(v.Bitstring())
+}
+
+// Method Equal reports whether 'v' and 'other' hold the same bits.
+func (v BitVector) Equal(other BitVector) bool {
+	if v.n != other.n {
+		return false
+	}
+	for i := range v.w {
+		if v.w[i] != other.w[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Method FromInt returns 'n' as a BitVector of at least 'l' bits, see
+// Bitstring.FromInt. The receiver is not used.
+func (v BitVector) FromInt(n int, l int) BitVector {
+	if l < 1 {
+		fmt.Printf("a bitstring must have a least 1 char\n")
+	}
+	if n < 0 {
+		fmt.Printf("bitstring representation undefined for " +
+			"negative numbers\n")
+	}
+	if length := bits.Len64(uint64(n)); length > l {
+		l = length
+	}
+	result := NewBitVector(l)
+	if l > 0 && n > 0 {
+		result.w[0] = uint64(n)
+	}
+	return result
+}
+
+// Method BinaryXor returns the xor of two BitVectors of equal length.
+func (v BitVector) BinaryXor(other BitVector) BitVector {
+	if v.n != other.n {
+		fmt.Printf("cannot binaryXor bitstrings " +
+			"of different lengths\n")
+	}
+	result := NewBitVector(v.n)
+	for i := range result.w {
+		result.w[i] = v.w[i] ^ other.w[i]
+	}
+	return result
+}
+
+// Method Xor returns the xor of 'args', which must have the same length.
+// As with Bitstring.Xor, the receiver is not used.
+func (v BitVector) Xor(args BitVectors) BitVector {
+	if len(args) == 0 {
+		fmt.Printf("at least one argument needed\n")
+	}
+	result := NewBitVector(args[0].n)
+	for _, arg := range args {
+		if arg.n != result.n {
+			fmt.Printf("cannot binaryXor bitstrings " +
+				"of different lengths\n")
+		}
+		for i := range result.w {
+			result.w[i] ^= arg.w[i]
+		}
+	}
+	return result
+}
+
+// Method ShiftLeft shifts 'v' towards its most significant bit by 'places'
+// places, inserting zeros; negative values shift right. See
+// Bitstring.ShiftLeft.
+func (v BitVector) ShiftLeft(places int) BitVector {
+	if places < 0 {
+		return v.ShiftRight(-places)
+	}
+	result := NewBitVector(v.n)
+	words, shift := places/64, uint(places%64)
+	for i := len(v.w) - 1; i >= words; i-- {
+		result.w[i] = v.w[i-words] << shift
+		if shift != 0 && i-words-1 >= 0 {
+			result.w[i] |= v.w[i-words-1] >> (64 - shift)
+		}
+	}
+	result.clearTop()
+	return result
+}
+
+// Method ShiftRight shifts 'v' towards its least significant bit by
+// 'places' places, inserting zeros; negative values shift left.
+func (v BitVector) ShiftRight(places int) BitVector {
+	if places < 0 {
+		return v.ShiftLeft(-places)
+	}
+	result := NewBitVector(v.n)
+	words, shift := places/64, uint(places%64)
+	for i := 0; i+words < len(v.w); i++ {
+		result.w[i] = v.w[i+words] >> shift
+		if shift != 0 && i+words+1 < len(v.w) {
+			result.w[i] |= v.w[i+words+1] << (64 - shift)
+		}
+	}
+	return result
+}
+
+// Method RotateLeft rotates 'v' towards its most significant bit by
+// 'places' places. See Bitstring.RotateLeft.
+func (v BitVector) RotateLeft(places int) BitVector {
+	if v.n == 0 {
+		return v
+	}
+	places = (places%v.n + v.n) % v.n
+	left, right := v.ShiftLeft(places), v.ShiftRight(v.n-places)
+	for i := range left.w {
+		left.w[i] |= right.w[i]
+	}
+	return left
+}
+
+// Method RotateRight rotates 'v' towards its least significant bit by
+// 'places' places.
+func (v BitVector) RotateRight(places int) BitVector {
+	return v.RotateLeft(-places)
+}
+
+// Method QuadSplit breaks a 128-bit BitVector into 4 32-bit ones, least
+// significant first.
+func (v BitVector) QuadSplit() BitVectors {
+	if v.n != 128 {
+		fmt.Printf("Bitstring must be 128-bits to be quadsplit\n")
+	}
+	result := make(BitVectors, 4)
+	for k := range result {
+		result[k] = NewBitVector(32)
+		result[k].w[0] = v.w[k/2] >> uint(32*(k%2)) & 0xffffffff
+	}
+	return result
+}
+
+// Method QuadJoin joins 4 32-bit BitVectors, least significant first,
+// into a 128-bit one. The receiver is not used.
+func (v BitVector) QuadJoin(bs BitVectors) BitVector {
+	if len(bs) != 4 {
+		fmt.Printf("List of bitstrings must " +
+			"contain 4 * 32-bit bitstrings\n")
+	}
+	result := NewBitVector(128)
+	for k, b := range bs {
+		result.w[k/2] |= b.w[0] << uint(32*(k%2))
+	}
+	return result
+}
+
+// Method ToHexstring returns the Hexstring of 'v', whose length must be a
+// multiple of 4.
+func (v BitVector) ToHexstring() Hexstring {
+	const digits = "0123456789abcdef"
+	h := make([]byte, v.n/4)
+	for i := range h {
+		nibble := v.w[4*i/64] >> uint(4*i%64) & 0xf
+		h[len(h)-1-i] = digits[nibble]
+	}
+	return Hexstring(h)
+}
+
+// Method clearTop zeroes the bits beyond the length of 'v'.
+func (v BitVector) clearTop() {
+	if r := uint(v.n % 64); r != 0 {
+		v.w[len(v.w)-1] &= 1<<r - 1
+	}
+}
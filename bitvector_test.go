@@ -0,0 +1,156 @@
+package serpent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function TestBitVector checks every method of BitVector against the
+// same method of Bitstring.
+func TestBitVector(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func(n int) Bitstring {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = '0' + byte(r.Intn(2))
+		}
+		return Bitstring(b)
+	}
+	for _, n := range []int{32, 64, 70, 128, 200} {
+		s, s2 := random(n), random(n)
+		v, v2 := BitVectorFromBitstring(s), BitVectorFromBitstring(s2)
+		if v.Bitstring() != s || v.Len() != n {
+			t.Fatalf("%d bits: conversion does not round trip\n", n)
+		}
+		check := func(what string, got BitVector, want Bitstring) {
+			if got.Bitstring() != want {
+				t.Errorf("%d bits: %s = %s, want %s\n", n, what, got, want)
+			}
+		}
+		if string(v.ByteSlice()) != string(s.ByteSlice()) {
+			t.Errorf("%d bits: ByteSlice = %s, want %s\n", n,
+				v.ByteSlice(), s.ByteSlice())
+		}
+		check("BinaryXor", v.BinaryXor(v2), s.BinaryXor(s2))
+		check("Xor", v.Xor(BitVectors{v, v2, v}),
+			s.Xor(Bitslice{s, s2, s}))
+		for p := 0; p < n; p++ {
+			check("RotateLeft", v.RotateLeft(p), s.RotateLeft(p))
+			check("RotateRight", v.RotateRight(p), s.RotateRight(p))
+			check("ShiftLeft", v.ShiftLeft(p), s.ShiftLeft(p))
+			check("ShiftRight", v.ShiftRight(p), s.ShiftRight(p))
+		}
+		if n%4 == 0 && v.ToHexstring() != s.ToHexstring() {
+			t.Errorf("%d bits: ToHexstring = %s, want %s\n", n,
+				v.ToHexstring(), s.ToHexstring())
+		}
+		if n == 128 {
+			q, want := v.QuadSplit(), s.QuadSplit()
+			for k := range q {
+				check("QuadSplit", q[k], want[k])
+			}
+			check("QuadJoin", v.QuadJoin(q), s)
+		}
+	}
+	var v BitVector
+	var s Bitstring
+	for _, n := range []int{0, 1, 5, 0xdeadbeef} {
+		if got, want := v.FromInt(n, 8), s.FromInt(n, 8); got.Bitstring() !=
+			want {
+			t.Errorf("FromInt(%d) = %s, want %s\n", n, got, want)
+		}
+		if got, want := v.FromInt(n, 8).Int(), s.FromInt(n, 8).Int(); got !=
+			n || want != n {
+			t.Errorf("Int of FromInt(%d) = %d and %d\n", n, got, want)
+		}
+	}
+}
+
+// Function TestGenericRounds runs the generic round functions on both
+// representations.
+func TestGenericRounds(t *testing.T) {
+	long := makeLongkey(bs)
+	K, _ := makeSubkeys(long)
+	KV := make(BitVectors, len(K))
+	for i := range K {
+		KV[i] = BitVectorFromBitstring(K[i])
+	}
+	want := EncryptBitslice(testPlainText, long)
+	if got := EncryptBitsliceOf(testPlainText, K); got != want {
+		t.Errorf("EncryptBitsliceOf on Bitstrings differs\n")
+	}
+	got := EncryptBitsliceOf(BitVectorFromBitstring(testPlainText), KV)
+	if got.Bitstring() != want {
+		t.Errorf("EncryptBitsliceOf on BitVectors differs\n")
+	}
+	if DecryptBitsliceOf(got, KV).Bitstring() != testPlainText {
+		t.Errorf("DecryptBitsliceOf does not yield plainText\n")
+	}
+	if DecryptBitsliceOf(want, K) != testPlainText {
+		t.Errorf("DecryptBitsliceOf on Bitstrings differs\n")
+	}
+
+	_, KHat := makeSubkeys(long)
+	KHatV := make(BitVectors, len(KHat))
+	for i := range KHat {
+		KHatV[i] = BitVectorFromBitstring(KHat[i])
+	}
+	want = Encrypt(testPlainText, long)
+	if got := EncryptOf(testPlainText, KHat); got != want {
+		t.Errorf("EncryptOf on Bitstrings differs\n")
+	}
+	got = EncryptOf(BitVectorFromBitstring(testPlainText), KHatV)
+	if got.Bitstring() != want {
+		t.Errorf("EncryptOf on BitVectors differs\n")
+	}
+	if DecryptOf(got, KHatV).Bitstring() != testPlainText {
+		t.Errorf("DecryptOf does not yield plainText\n")
+	}
+	v := BitVectorFromBitstring(testPlainText)
+	for _, f := range []struct {
+		name string
+		of   func(BitVector) BitVector
+		want func(Bitstring) Bitstring
+	}{
+		{"IP", IPOf[BitVector], IP},
+		{"FP", FPOf[BitVector], FP},
+		{"LT", LTOf[BitVector], LT},
+		{"LTInverse", LTInverseOf[BitVector], LTInverse},
+	} {
+		if f.of(v).Bitstring() != f.want(testPlainText) {
+			t.Errorf("%sOf on BitVectors differs\n", f.name)
+		}
+	}
+}
+
+// Function BenchmarkRepresentations compares the generic algorithms on
+// Bitstrings and on BitVectors.
+func BenchmarkRepresentations(b *testing.B) {
+	K, KHat := makeSubkeys(makeLongkey(bs))
+	KV, KHatV := make(BitVectors, len(K)), make(BitVectors, len(KHat))
+	for i := range K {
+		KV[i] = BitVectorFromBitstring(K[i])
+		KHatV[i] = BitVectorFromBitstring(KHat[i])
+	}
+	v := BitVectorFromBitstring(testPlainText)
+	b.Run("Bitstring/normal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptOf(testPlainText, KHat)
+		}
+	})
+	b.Run("BitVector/normal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptOf(v, KHatV)
+		}
+	})
+	b.Run("Bitstring/bitslice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptBitsliceOf(testPlainText, K)
+		}
+	})
+	b.Run("BitVector/bitslice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncryptBitsliceOf(v, KV)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"runtime"
+	"strconv"
+)
+
+// The Serpent block size in bytes.
+const BlockSize = 16
+
+// KeySizeError is returned when a byte key is not a multiple of 4 bytes
+// between 8 and 32 bytes long.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "serpent: invalid key size " + strconv.Itoa(int(k))
+}
+
+// A block implements the crypto/cipher.Block interface with the word
+// core. 'k' is the view of the subkeys owned by 'keys'.
+type block struct {
+	v    *variant
+	k    [][4]uint32
+	keys *subkeys
+}
+
+// Function NewCipher creates and returns a standard Serpent cipher.Block.
+// The key argument should be 16, 24 or 32 bytes long, although any
+// multiple of 4 bytes between 8 and 32 is accepted.
+func NewCipher(key []byte) (cipher.Block, error) {
+	return NewCipherWithParams(Serpent1, key)
+}
+
+// Function NewCipherWithParams creates and returns a cipher.Block for the
+// parameter set 'p'.
+//
+// Blocks and keys are read as little-endian byte sequences, bit j of byte
+// i being bit 8*i+j of the Bitstring, so the byte API agrees with the
+// NESSIE test vectors and with other common Serpent implementations.
+func NewCipherWithParams(p Params, key []byte) (cipher.Block, error) {
+	return newCipher(p, key, false)
+}
+
+// Function newCipher is NewCipherWithParams, keeping the subkeys in locked
+// memory when 'lock' is set.
+func newCipher(p Params, key []byte, lock bool) (cipher.Block, error) {
+	if err := checkSelfTest(); err != nil {
+		return nil, err
+	}
+	return newUncheckedCipher(p, key, lock)
+}
+
+// Function newUncheckedCipher is newCipher without the self-test check,
+// for the self-test itself.
+func newUncheckedCipher(p Params, key []byte, lock bool) (cipher.Block,
+	error) {
+	k := len(key)
+	if k%4 != 0 || k < 8 || k > 32 {
+		return nil, KeySizeError(k)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	v := newVariant(p)
+	w := longKeyWords(key)
+	K := v.wordSubkeys(&w)
+	w = [8]uint32{}
+	runtime.KeepAlive(&w)
+	b, err := newBlock(v, K, lock)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Function newBlock creates a block for 'v' owning the subkeys 'K'.
+func newBlock(v *variant, K [][4]uint32, lock bool) (*block, error) {
+	keys, err := newSubkeys(K, lock)
+	if err != nil {
+		return nil, err
+	}
+	return &block{v: v, k: keys.k, keys: keys}, nil
+}
+
+func (b *block) BlockSize() int { return BlockSize }
+
+func (b *block) Encrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	b.keys.check()
+	x := loadWords(src)
+	b.v.encryptWords(&x, b.k)
+	storeWords(dst, &x)
+	runtime.KeepAlive(b.keys)
+}
+
+func (b *block) Decrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	b.keys.check()
+	x := loadWords(src)
+	b.v.decryptWords(&x, b.k)
+	storeWords(dst, &x)
+	runtime.KeepAlive(b.keys)
+}
+
+// Function checkBlock panics if either buffer is shorter than a block.
+func checkBlock(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("serpent: input not full block")
+	}
+	if len(dst) < BlockSize {
+		panic("serpent: output not full block")
+	}
+}
+
+// Function BitstringFromBytes returns the Bitstring holding the bits of
+// 'b', least significant bit of the first byte first.
+func BitstringFromBytes(b []byte) Bitstring {
+	result := make([]byte, 0, 8*len(b))
+	for _, c := range b {
+		for j := uint(0); j < 8; j++ {
+			result = append(result, '0'+(c>>j)&1)
+		}
+	}
+	return Bitstring(result)
+}
+
+// Method Bytes packs the bits of 's' into bytes, the reverse of
+// BitstringFromBytes. The length of 's' must be a multiple of 8.
+func (s Bitstring) Bytes() []byte {
+	result := make([]byte, len(s)/8)
+	for i := 0; i < len(result)*8; i++ {
+		if s[i] == '1' {
+			result[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package serpent
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Known answers in the byte order used by NESSIE and other
+// implementations.
+var blockTests = []struct {
+	key, plain, cipher string
+}{
+	{
+		"80000000000000000000000000000000",
+		"00000000000000000000000000000000",
+		"264e5481eff42a4606abda06c0bfda3d",
+	},
+	{
+		"0001020304050607",
+		"00112233445566778899aabbccddeeff",
+		"bc58de4c19dff612e41ac8d5e0968cac",
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f1011121314151617",
+		"00112233445566778899aabbccddeeff",
+		"6ab816c82de53b93005008afa2246a02",
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f" +
+			"101112131415161718191a1b1c1d1e1f",
+		"00112233445566778899aabbccddeeff",
+		"2868b7a2d28ecd5e4fdefac3c4330074",
+	},
+}
+
+// Function TestBlock checks NewCipher against known answers in both
+// directions.
+func TestBlock(t *testing.T) {
+	for i, test := range blockTests {
+		key, _ := hex.DecodeString(test.key)
+		plain, _ := hex.DecodeString(test.plain)
+		want, _ := hex.DecodeString(test.cipher)
+		b, err := NewCipher(key)
+		if err != nil {
+			t.Fatalf("test %d: NewCipher failed: %v\n", i, err)
+		}
+		got := make([]byte, BlockSize)
+		b.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			t.Errorf("test %d: Encrypt = %x, want %x\n", i, got, want)
+		}
+		b.Decrypt(got, want)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("test %d: Decrypt = %x, want %x\n", i, got, plain)
+		}
+	}
+	if _, err := NewCipher(make([]byte, 17)); err != KeySizeError(17) {
+		t.Errorf("17 byte key gave %v\n", err)
+	}
+}
+
+// Function TestKeyScheduleSBox checks that the key schedule S-Box order can
+// be replaced and that the default is the Serpent-1 order.
+func TestKeyScheduleSBox(t *testing.T) {
+	explicit := Serpent1
+	explicit.KeyScheduleSBox = func(i int) int { return (35 - i) % 8 }
+	other := Serpent1
+	other.KeyScheduleSBox = func(i int) int { return (3 + i) % 8 }
+
+	c1, _ := New(Serpent1, bs)
+	c2, _ := New(explicit, bs)
+	c3, _ := New(other, bs)
+	if c1.Encrypt(testPlainText) != c2.Encrypt(testPlainText) {
+		t.Errorf("explicit Serpent-1 key schedule order differs\n")
+	}
+	cipherText := c3.Encrypt(testPlainText)
+	if cipherText == c1.Encrypt(testPlainText) {
+		t.Errorf("key schedule order has no effect\n")
+	}
+	if c3.Decrypt(cipherText) != testPlainText {
+		t.Errorf("Decrypt does not yield plainText\n")
+	}
+}
+
+// Function TestBitstringBytes checks the conversion between bytes and
+// Bitstrings.
+func TestBitstringBytes(t *testing.T) {
+	b := []byte{0x01, 0x80, 0x5a}
+	s := BitstringFromBytes(b)
+	if s != "100000000000000101011010" {
+		t.Errorf("BitstringFromBytes = %s\n", s)
+	}
+	if !bytes.Equal(s.Bytes(), b) {
+		t.Errorf("Bytes does not yield the original bytes\n")
+	}
+}
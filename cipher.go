@@ -0,0 +1,152 @@
+package serpent
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Cipher is a key schedule expanded for one parameter set. It encrypts and
+// decrypts 128-bit Bitstrings by either the normal or the bitslice
+// algorithm; both give the same result.
+type Cipher struct {
+	v *variant
+	// keys holds the subkeys K in word form, see mixKey.
+	keys *subkeys
+}
+
+// Function New validates the parameter set 'p' and expands 'userKey' into
+// the subkeys it requires. Keys shorter than 256 bits are lengthened as
+// described in the Serpent specification, so any multiple of 32 bits from
+// 64 to 256 is accepted.
+func New(p Params, userKey Bitstring) (*Cipher, error) {
+	if err := checkSelfTest(); err != nil {
+		return nil, err
+	}
+	return newUnchecked(p, userKey)
+}
+
+// Function newUnchecked is New without the self-test check, for the
+// self-test itself.
+func newUnchecked(p Params, userKey Bitstring) (*Cipher, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	lk := len(userKey)
+	if lk%32 != 0 || lk < 64 || lk > 256 {
+		return nil, fmt.Errorf("serpent: invalid key length (%d bits)", lk)
+	}
+	if err := checkBits(userKey); err != nil {
+		return nil, err
+	}
+	v := newVariant(p)
+	key := userKey.Bytes()
+	w := longKeyWords(key)
+	K := v.wordSubkeys(&w)
+	wipeBytes(key)
+	w = [8]uint32{}
+	runtime.KeepAlive(&w)
+	// Without locking newSubkeys cannot fail.
+	keys, _ := newSubkeys(K, false)
+	return &Cipher{v: v, keys: keys}, nil
+}
+
+// Function newBitstringCipher creates a Cipher for 'v' keeping the subkeys
+// 'K', in bitslice format, as words.
+func newBitstringCipher(v *variant, K Bitslice) *Cipher {
+	w := make([][4]uint32, len(K))
+	for i, k := range K {
+		w[i] = wordsFromBitstring(k)
+	}
+	// Without locking newSubkeys cannot fail.
+	keys, _ := newSubkeys(w, false)
+	return &Cipher{v: v, keys: keys}
+}
+
+// A keyMixer mixes subkey i into a 128-bit state. A Bitslice of subkeys
+// is one; the Cipher mixes its word subkeys through bitsliceMixer and
+// normalMixer, so that no Bitstring copy of a subkey is made.
+type keyMixer interface {
+	mixKey(i int, state Bitstring) Bitstring
+}
+
+// Method mixKey returns 'state' XORed with subkey i of 'K'.
+func (K Bitslice) mixKey(i int, state Bitstring) Bitstring {
+	return state.BinaryXor(K[i])
+}
+
+// A bitsliceMixer mixes the word subkeys K of a Cipher into a state in
+// bitslice format.
+type bitsliceMixer struct {
+	keys *subkeys
+}
+
+func (m bitsliceMixer) mixKey(i int, state Bitstring) Bitstring {
+	x := wordsFromBitstring(state)
+	xorWords(&x, &m.keys.k[i])
+	return wordsToBitstring(&x)
+}
+
+// A normalMixer mixes the word subkeys of a Cipher into a state in the
+// format of the normal algorithm. As IP is a permutation, XORing
+// KHat = IP(K) into BHat equals IP(FP(BHat) XOR K).
+type normalMixer struct {
+	keys *subkeys
+}
+
+func (m normalMixer) mixKey(i int, state Bitstring) Bitstring {
+	return IP(bitsliceMixer(m).mixKey(i, FP(state)))
+}
+
+// Method bitsliceKeys returns the mixer of the subkeys K of 'c', which the
+// bitslice algorithm uses. It panics if 'c' has been destroyed.
+func (c *Cipher) bitsliceKeys() keyMixer {
+	c.keys.check()
+	return bitsliceMixer{c.keys}
+}
+
+// Method normalKeys returns the mixer of the subkeys KHat = IP(K) of 'c',
+// which the normal algorithm uses. It panics if 'c' has been destroyed.
+func (c *Cipher) normalKeys() keyMixer {
+	c.keys.check()
+	return normalMixer{c.keys}
+}
+
+// Function checkBits checks that 's' only contains the characters 0 and 1.
+func checkBits(s Bitstring) error {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' && s[i] != '1' {
+			return fmt.Errorf("serpent: invalid character %q at bit %d",
+				s[i], i)
+		}
+	}
+	return nil
+}
+
+// Method Params returns the parameter set the cipher was created with.
+func (c *Cipher) Params() Params {
+	return c.v.Params
+}
+
+// Method Encrypt encrypts the 128-bit Bitstring 'plainText' by the normal
+// algorithm.
+func (c *Cipher) Encrypt(plainText Bitstring) Bitstring {
+	return c.v.encrypt(plainText, c.normalKeys())
+}
+
+// Method Decrypt decrypts the 128-bit Bitstring 'cipherText' by the normal
+// algorithm.
+func (c *Cipher) Decrypt(cipherText Bitstring) Bitstring {
+	return c.v.decrypt(cipherText, c.normalKeys())
+}
+
+// Method EncryptBitslice encrypts the 128-bit Bitstring 'plainText' by the
+// bitslice algorithm.
+func (c *Cipher) EncryptBitslice(plainText Bitstring) Bitstring {
+	return c.v.encryptBitslice(plainText, c.bitsliceKeys())
+}
+
+// Method DecryptBitslice decrypts the 128-bit Bitstring 'cipherText' by the
+// bitslice algorithm.
+func (c *Cipher) DecryptBitslice(cipherText Bitstring) Bitstring {
+	return c.v.decryptBitslice(cipherText, c.bitsliceKeys())
+}
@@ -0,0 +1,432 @@
+package serpent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GateOp is the operation of a boolean circuit gate.
+type GateOp int
+
+const (
+	XOR GateOp = iota
+	AND
+	INV
+	// EQW copies its input wire.
+	EQW
+	// OR is not part of the Bristol Fashion format and only appears in
+	// S-Box circuits.
+	OR
+)
+
+var gateOpNames = []string{"XOR", "AND", "INV", "EQW", "OR"}
+
+func (op GateOp) String() string {
+	if op < 0 || int(op) >= len(gateOpNames) {
+		return fmt.Sprintf("GateOp(%d)", int(op))
+	}
+	return gateOpNames[op]
+}
+
+// Gate is one gate of a Circuit. INV and EQW use only In[0].
+type Gate struct {
+	Op  GateOp
+	In  [2]int
+	Out int
+}
+
+// Circuit is a boolean circuit in the form used by the Bristol Fashion
+// format: the input values occupy the first wires, the output values the
+// last ones, and gates are listed in evaluation order.
+type Circuit struct {
+	Wires   int
+	Inputs  []int
+	Outputs []int
+	Gates   []Gate
+}
+
+// Function BuildCircuit expresses the key schedule and rounds of 'p' as
+// AND, XOR and INV gates. Input 0 is the 128-bit plaintext and input 1 the
+// 256-bit user key, wire i of each holding bit i of the Bitstring; output 0
+// is the ciphertext. Shorter keys must be lengthened as by New before
+// being fed to the circuit.
+//
+// AND gates are the cost that matters in multi-party computation, XOR and
+// INV being free, so each S-Box is built from a circuit with few AND and
+// OR gates: the circuits of the word core for the Serpent-1 S-Boxes, found
+// by SBoxCircuitSearch for others. OR gates become AND gates by De
+// Morgan's law. The permutations cost no gates.
+func BuildCircuit(p Params) (*Circuit, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	v := newVariant(p)
+	circuits, err := v.sboxCircuits()
+	if err != nil {
+		return nil, err
+	}
+	b := &circuitBuilder{c: &Circuit{Inputs: []int{128, 256}},
+		zero: -1, sboxes: circuits}
+	plainText := b.inputs(128)
+	key := b.inputs(256)
+	KHat := b.subkeys(v, key)
+
+	state := permuteVars(IPTable, plainText)
+	for i := p.StartRound; i <= v.lastRound(); i++ {
+		x := b.xorWords(state, KHat[i])
+		s := make([]int, 128)
+		for j := 0; j < 32; j++ {
+			b.sbox(v.box(i), x[4*j:4*j+4], s[4*j:4*j+4])
+		}
+		if i < v.lastRound() {
+			state = b.linear(p.LTTable, s)
+		} else {
+			state = b.xorWords(s, KHat[i+1])
+		}
+	}
+
+	// The outputs must be the last wires.
+	for _, w := range permuteVars(FPTable, state) {
+		b.gate(EQW, w, 0)
+	}
+	b.c.Outputs = []int{128}
+	return b.c, nil
+}
+
+// Method sboxCircuits returns a circuit for each S-Box of 'v'.
+func (v *variant) sboxCircuits() ([]*SBoxCircuit, error) {
+	if v.standardSBoxes {
+		return wordSBoxCircuits[:], nil
+	}
+	circuits := make([]*SBoxCircuit, len(v.SBoxes))
+	for i, sbox := range v.SBoxes {
+		c, err := SBoxCircuitSearch{SBox: sbox, Attempts: 20}.Best()
+		if err != nil {
+			return nil, err
+		}
+		circuits[i] = c
+	}
+	return circuits, nil
+}
+
+// circuitBuilder allocates the wires of a circuit as gates are added.
+type circuitBuilder struct {
+	c *Circuit
+	// zero is the wire holding the constant 0, or -1 before it is
+	// needed.
+	zero int
+	// sboxes holds the circuit of each S-Box.
+	sboxes []*SBoxCircuit
+}
+
+func (b *circuitBuilder) inputs(n int) []int {
+	wires := make([]int, n)
+	for i := range wires {
+		wires[i] = b.c.Wires
+		b.c.Wires++
+	}
+	return wires
+}
+
+func (b *circuitBuilder) gate(op GateOp, x, y int) int {
+	g := Gate{Op: op, In: [2]int{x, y}, Out: b.c.Wires}
+	if op == INV || op == EQW {
+		g.In[1] = 0
+	}
+	b.c.Gates = append(b.c.Gates, g)
+	b.c.Wires++
+	return g.Out
+}
+
+// Method constant returns a wire holding 'bit', built from the first
+// input wire as the circuit has no constant inputs.
+func (b *circuitBuilder) constant(bit int) int {
+	if b.zero < 0 {
+		b.zero = b.gate(XOR, 0, 0)
+	}
+	if bit == 0 {
+		return b.zero
+	}
+	return b.gate(INV, b.zero, 0)
+}
+
+// Method xor returns a wire holding the xor of 'wires' and the low bit of
+// 'constant'.
+func (b *circuitBuilder) xor(wires []int, constant int) int {
+	if len(wires) == 0 {
+		return b.constant(constant)
+	}
+	w := wires[0]
+	for _, x := range wires[1:] {
+		w = b.gate(XOR, w, x)
+	}
+	if constant&1 == 1 {
+		w = b.gate(INV, w, 0)
+	}
+	return w
+}
+
+func (b *circuitBuilder) xorWords(x, y []int) []int {
+	result := make([]int, len(x))
+	for i := range x {
+		result[i] = b.gate(XOR, x[i], y[i])
+	}
+	return result
+}
+
+// Method sbox fills 'out' with the wires of the image of 'in' under S-Box
+// 'box'.
+func (b *circuitBuilder) sbox(box int, in, out []int) {
+	c := b.sboxes[box]
+	wires := append(make([]int, 0, 4+len(c.Gates)), in...)
+	for _, g := range c.Gates {
+		x, y := wires[g.In[0]], wires[g.In[1]]
+		var w int
+		if g.Op == OR {
+			w = b.gate(INV, b.gate(AND, b.gate(INV, x, 0),
+				b.gate(INV, y, 0)), 0)
+		} else {
+			w = b.gate(g.Op, x, y)
+		}
+		wires = append(wires, w)
+	}
+	for l := range out {
+		out[l] = wires[c.Outputs[l]]
+	}
+}
+
+func (b *circuitBuilder) linear(table []Ttable, s []int) []int {
+	result := make([]int, 128)
+	for i, positions := range table {
+		terms := make([]int, len(positions))
+		for k, j := range positions {
+			terms[k] = s[j]
+		}
+		result[i] = b.xor(terms, 0)
+	}
+	return result
+}
+
+// Method subkeys builds the key schedule and returns the wires of the
+// subkeys KHat used by the rounds.
+func (b *circuitBuilder) subkeys(v *variant, key []int) [][]int {
+	n := v.subkeyCount()
+	w := make([][]int, 4*n+8)
+	for i := 0; i < 8; i++ {
+		w[i] = key[32*i : 32*i+32]
+	}
+	for i := 0; i < 4*n; i++ {
+		word := make([]int, 32)
+		constant := uint32(v.Phi) ^ uint32(i)
+		for bit := 0; bit < 32; bit++ {
+			// Rotating left by 11 moves bit b-11 into bit b.
+			src := (bit + 32 - 11) % 32
+			word[bit] = b.xor([]int{w[i][src], w[i+3][src],
+				w[i+5][src], w[i+7][src]}, int(constant>>uint(src)))
+		}
+		w[i+8] = word
+	}
+
+	KHat := make([][]int, n)
+	for i := v.StartRound; i < n; i++ {
+		whichS := v.keyScheduleBox(i)
+		K := make([]int, 128)
+		for j := 0; j < 32; j++ {
+			var in, out [4]int
+			for l := 0; l < 4; l++ {
+				in[l] = w[4*i+l+8][j]
+			}
+			b.sbox(v.box(whichS), in[:], out[:])
+			for l := 0; l < 4; l++ {
+				K[32*l+j] = out[l]
+			}
+		}
+		KHat[i] = permuteVars(IPTable, K)
+	}
+	return KHat
+}
+
+// Method Count returns the number of gates of operation 'op'.
+func (c *Circuit) Count(op GateOp) (n int) {
+	for _, g := range c.Gates {
+		if g.Op == op {
+			n++
+		}
+	}
+	return
+}
+
+// Method Eval evaluates the circuit on one slice of bits per input value
+// and returns one slice per output value.
+func (c *Circuit) Eval(inputs ...[]bool) ([][]bool, error) {
+	if len(inputs) != len(c.Inputs) {
+		return nil, fmt.Errorf("serpent: circuit takes %d inputs, got %d",
+			len(c.Inputs), len(inputs))
+	}
+	wires := make([]bool, c.Wires)
+	n := 0
+	for i, in := range inputs {
+		if len(in) != c.Inputs[i] {
+			return nil, fmt.Errorf("serpent: circuit input %d has %d "+
+				"bits, got %d", i, c.Inputs[i], len(in))
+		}
+		n += copy(wires[n:], in)
+	}
+	for _, g := range c.Gates {
+		switch g.Op {
+		case XOR:
+			wires[g.Out] = wires[g.In[0]] != wires[g.In[1]]
+		case AND:
+			wires[g.Out] = wires[g.In[0]] && wires[g.In[1]]
+		case INV:
+			wires[g.Out] = !wires[g.In[0]]
+		case EQW:
+			wires[g.Out] = wires[g.In[0]]
+		case OR:
+			wires[g.Out] = wires[g.In[0]] || wires[g.In[1]]
+		}
+	}
+	var outputs [][]bool
+	n = c.Wires
+	for _, size := range c.Outputs {
+		n -= size
+	}
+	for _, size := range c.Outputs {
+		outputs = append(outputs, append([]bool(nil), wires[n:n+size]...))
+		n += size
+	}
+	return outputs, nil
+}
+
+// Method WriteBristol writes the circuit in Bristol Fashion format.
+func (c *Circuit) WriteBristol(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%d %d\n", len(c.Gates), c.Wires)
+	fmt.Fprintf(bw, "%s\n", bristolSizes(c.Inputs))
+	fmt.Fprintf(bw, "%s\n\n", bristolSizes(c.Outputs))
+	for _, g := range c.Gates {
+		if g.Op == INV || g.Op == EQW {
+			fmt.Fprintf(bw, "1 1 %d %d %s\n", g.In[0], g.Out, g.Op)
+		} else {
+			fmt.Fprintf(bw, "2 1 %d %d %d %s\n", g.In[0], g.In[1], g.Out,
+				g.Op)
+		}
+	}
+	return bw.Flush()
+}
+
+func bristolSizes(sizes []int) string {
+	s := strconv.Itoa(len(sizes))
+	for _, n := range sizes {
+		s += " " + strconv.Itoa(n)
+	}
+	return s
+}
+
+// Function ReadBristol reads a circuit in Bristol Fashion format using
+// the XOR, AND, INV and EQW gates.
+func ReadBristol(r io.Reader) (*Circuit, error) {
+	var fields []int
+	var gates [][]string
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		f := strings.Fields(scanner.Text())
+		if len(f) == 0 {
+			continue
+		}
+		line++
+		if line > 3 {
+			gates = append(gates, f)
+			continue
+		}
+		for _, s := range f {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("serpent: bad header field %q", s)
+			}
+			fields = append(fields, n)
+		}
+		fields = append(fields, -1)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	header := splitHeader(fields)
+	if len(header) != 3 || len(header[0]) != 2 {
+		return nil, fmt.Errorf("serpent: bad Bristol header")
+	}
+	c := &Circuit{Wires: header[0][1]}
+	for k, sizes := range header[1:] {
+		if len(sizes) == 0 || sizes[0] != len(sizes)-1 {
+			return nil, fmt.Errorf("serpent: bad Bristol header")
+		}
+		if k == 0 {
+			c.Inputs = sizes[1:]
+		} else {
+			c.Outputs = sizes[1:]
+		}
+	}
+	if len(gates) != header[0][0] {
+		return nil, fmt.Errorf("serpent: %d gates listed, header says %d",
+			len(gates), header[0][0])
+	}
+	for _, f := range gates {
+		g, err := parseGate(f, c.Wires)
+		if err != nil {
+			return nil, err
+		}
+		c.Gates = append(c.Gates, g)
+	}
+	return c, nil
+}
+
+// Function splitHeader splits the header numbers at the -1 line markers.
+func splitHeader(fields []int) (lines [][]int) {
+	var current []int
+	for _, n := range fields {
+		if n == -1 {
+			lines = append(lines, current)
+			current = nil
+			continue
+		}
+		current = append(current, n)
+	}
+	return
+}
+
+func parseGate(f []string, wires int) (Gate, error) {
+	var g Gate
+	op := -1
+	for i, name := range gateOpNames {
+		if f[len(f)-1] == name {
+			op = i
+		}
+	}
+	if op < 0 {
+		return g, fmt.Errorf("serpent: unsupported gate %q", f[len(f)-1])
+	}
+	g.Op = GateOp(op)
+	arity := 2
+	if g.Op == INV || g.Op == EQW {
+		arity = 1
+	}
+	if len(f) != arity+4 || f[0] != strconv.Itoa(arity) || f[1] != "1" {
+		return g, fmt.Errorf("serpent: bad %s gate", g.Op)
+	}
+	var numbers []int
+	for _, s := range f[2 : len(f)-1] {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 || n >= wires {
+			return g, fmt.Errorf("serpent: bad wire %q", s)
+		}
+		numbers = append(numbers, n)
+	}
+	copy(g.In[:], numbers[:arity])
+	g.Out = numbers[arity]
+	return g, nil
+}
@@ -0,0 +1,133 @@
+package serpent
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Function TestCircuit checks the full circuit, written out in Bristol
+// Fashion and read back, against Encrypt on random inputs.
+func TestCircuit(t *testing.T) {
+	c, err := BuildCircuit(Serpent1)
+	if err != nil {
+		t.Fatalf("BuildCircuit failed: %v\n", err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteBristol(&buf); err != nil {
+		t.Fatalf("WriteBristol failed: %v\n", err)
+	}
+	back, err := ReadBristol(&buf)
+	if err != nil {
+		t.Fatalf("ReadBristol failed: %v\n", err)
+	}
+	if back.Count(AND) != c.Count(AND) || back.Wires != c.Wires {
+		t.Errorf("circuit changed when read back\n")
+	}
+	// 32 rounds and 33 subkeys of 32 S-Boxes built from the word
+	// circuits, against 19520 from the algebraic normal forms.
+	if n := c.Count(AND); n != 17632 {
+		t.Errorf("%d AND gates, want 17632\n", n)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 3; i++ {
+		plainText := BitstringFromBytes(randomBytes(r, 16))
+		key := BitstringFromBytes(randomBytes(r, 32))
+		out, err := back.Eval(circuitBits(plainText), circuitBits(key))
+		if err != nil {
+			t.Fatalf("Eval failed: %v\n", err)
+		}
+		want := Encrypt(plainText, key)
+		got := make([]byte, 128)
+		for j, bit := range out[0] {
+			got[j] = '0'
+			if bit {
+				got[j] = '1'
+			}
+		}
+		if Bitstring(got) != want {
+			t.Errorf("circuit gives %s, want %s\n", Bitstring(got).ToHexstring(),
+				want.ToHexstring())
+		}
+	}
+}
+
+// Function TestCircuitReducedRounds checks a circuit of rounds 5 to 7
+// against Cipher.
+func TestCircuitReducedRounds(t *testing.T) {
+	p := Serpent1
+	p.StartRound, p.Rounds = 5, 3
+	c, _ := BuildCircuit(p)
+	cipher, _ := New(p, bs)
+	out, _ := c.Eval(circuitBits(testPlainText),
+		circuitBits(makeLongkey(bs)))
+	want := circuitBits(cipher.Encrypt(testPlainText))
+	for j := range want {
+		if out[0][j] != want[j] {
+			t.Fatalf("output bit %d differs\n", j)
+		}
+	}
+	// Rounds 5 to 7 use S5 to S7 and subkeys 5 to 8 use S6 down to S3,
+	// each S-Box costing its AND and OR gates.
+	ands := 0
+	for _, box := range []int{5, 6, 7, 6, 5, 4, 3} {
+		for _, g := range wordSBoxCircuits[box].Gates {
+			if g.Op == AND || g.Op == OR {
+				ands += 32
+			}
+		}
+	}
+	if n := c.Count(AND); n != ands {
+		t.Errorf("%d AND gates, want %d\n", n, ands)
+	}
+}
+
+// Function TestCircuitCustomSBoxes checks a circuit of a variant with
+// reordered S-Boxes, whose circuits come from SBoxCircuitSearch.
+func TestCircuitCustomSBoxes(t *testing.T) {
+	p := Serpent1
+	p.StartRound, p.Rounds = 0, 2
+	p.SBoxes = []SBox{SBoxDecimalTable[1], SBoxDecimalTable[0]}
+	c, err := BuildCircuit(p)
+	if err != nil {
+		t.Fatalf("BuildCircuit failed: %v\n", err)
+	}
+	cipher, _ := New(p, bs)
+	out, _ := c.Eval(circuitBits(testPlainText),
+		circuitBits(makeLongkey(bs)))
+	want := circuitBits(cipher.Encrypt(testPlainText))
+	for j := range want {
+		if out[0][j] != want[j] {
+			t.Fatalf("output bit %d differs\n", j)
+		}
+	}
+}
+
+// Function TestReadBristolBadGate checks that gates whose counts of input
+// and output wires do not match their operation are rejected.
+func TestReadBristolBadGate(t *testing.T) {
+	const header = "1 3\n2 1 1\n1 1\n\n"
+	if _, err := ReadBristol(strings.NewReader(header + "2 1 0 1 2 XOR\n")); err != nil {
+		t.Fatalf("valid gate rejected: %v\n", err)
+	}
+	for _, gate := range []string{
+		"1 1 0 1 2 XOR",
+		"2 2 0 1 2 XOR",
+		"2 1 0 1 INV",
+		"01 1 0 2 INV",
+	} {
+		if _, err := ReadBristol(strings.NewReader(header + gate + "\n")); err == nil {
+			t.Errorf("gate %q accepted\n", gate)
+		}
+	}
+}
+
+func circuitBits(s Bitstring) []bool {
+	bits := make([]bool, len(s))
+	for i := range bits {
+		bits[i] = s[i] == '1'
+	}
+	return bits
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runAvalanche implements "serpent avalanche".
+func runAvalanche(args []string) error {
+	fs := flag.NewFlagSet("avalanche", flag.ExitOnError)
+	rounds := fs.Int("rounds", 4, "number of rounds")
+	start := fs.Int("start", 0, "index of the first round")
+	flip := fs.String("flip", "plaintext", "flip plaintext or key bits")
+	samples := fs.Int("samples", 100, "number of random samples")
+	seed := fs.Int64("seed", 1, "seed of the samples")
+	bitslice := fs.Bool("bitslice", false, "use RBitslice rather than R")
+	svg := fs.String("svg", "", "write dependence and SAC heatmaps for "+
+		"each round into this directory")
+	fs.Parse(args)
+
+	p := serpent.Serpent1
+	p.Rounds = *rounds
+	p.StartRound = *start
+	a := serpent.AvalancheTest{Params: p, Samples: *samples, Seed: *seed,
+		Bitslice: *bitslice}
+	switch *flip {
+	case "plaintext":
+		a.Flip = serpent.FlipPlaintext
+	case "key":
+		a.Flip = serpent.FlipKey
+	default:
+		return fmt.Errorf("unknown input %q", *flip)
+	}
+	ms, err := a.Run()
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		m.WriteText(os.Stdout)
+		if *svg == "" {
+			continue
+		}
+		for kind, name := range map[serpent.Heatmap]string{
+			serpent.DependenceHeatmap: "dependence",
+			serpent.SACHeatmap:        "sac",
+		} {
+			path := filepath.Join(*svg,
+				fmt.Sprintf("%s-round%02d.svg", name, m.Round))
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			err = m.WriteSVG(f, kind)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runCircuit implements "serpent circuit".
+func runCircuit(args []string) error {
+	fs := flag.NewFlagSet("circuit", flag.ExitOnError)
+	rounds := fs.Int("rounds", 32, "number of rounds")
+	start := fs.Int("start", 0, "index of the first round")
+	fs.Parse(args)
+
+	p := serpent.Serpent1
+	p.Rounds = *rounds
+	p.StartRound = *start
+	c, err := serpent.BuildCircuit(p)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d AND, %d XOR, %d INV gates\n",
+		c.Count(serpent.AND), c.Count(serpent.XOR), c.Count(serpent.INV))
+	return c.WriteBristol(os.Stdout)
+}
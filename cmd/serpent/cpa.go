@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runCPA implements "serpent cpa".
+func runCPA(args []string) error {
+	fs := flag.NewFlagSet("cpa", flag.ExitOnError)
+	model := fs.String("model", "hw", "leakage model, hw or hd")
+	noise := fs.Float64("noise", 1, "standard deviation of the noise")
+	keyHex := fs.String("key", "000102030405060708090a0b0c0d0e0f",
+		"key in hexadecimal")
+	counts := fs.String("traces", "10,20,50,100,200,500",
+		"comma separated numbers of traces")
+	experiments := fs.Int("experiments", 20, "attacks per number of traces")
+	seed := fs.Int64("seed", 1, "seed of the plaintexts and noise")
+	out := fs.String("o", "", "write the largest number of traces to "+
+		"this file instead of reporting success rates")
+	in := fs.String("in", "", "attack the traces in this file")
+	masked := fs.Bool("masked", false, "simulate the masked core")
+	fs.Parse(args)
+
+	key, err := hex.DecodeString(*keyHex)
+	if err != nil {
+		return err
+	}
+	s := serpent.LeakageSimulator{Params: serpent.Serpent1, Key: key,
+		Noise: *noise, Seed: *seed, Masked: *masked}
+	switch *model {
+	case "hw":
+		s.Model = serpent.HammingWeight
+	case "hd":
+		s.Model = serpent.HammingDistance
+	default:
+		return fmt.Errorf("unknown leakage model %q", *model)
+	}
+	c := serpent.CPA{Params: s.Params, Model: s.Model}
+
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		traces, err := serpent.ReadTraces(f)
+		if err != nil {
+			return err
+		}
+		r, err := c.Attack(traces)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("K[0] = %s\n", r.Subkey.ToHexstring())
+		return nil
+	}
+
+	var ns []int
+	for _, field := range strings.Split(*counts, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return err
+		}
+		ns = append(ns, n)
+	}
+	if *out != "" {
+		max := 0
+		for _, n := range ns {
+			if n > max {
+				max = n
+			}
+		}
+		traces, err := s.Traces(max)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		err = serpent.WriteTraces(f, traces)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+
+	rates, err := c.SuccessRate(s, ns, *experiments)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%8s %8s %8s\n", "traces", "nibbles", "subkey")
+	for _, r := range rates {
+		fmt.Printf("%8d %8.3f %8.3f\n", r.Traces, r.Nibbles, r.Subkey)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/cipher"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/JonPulfer/serpent"
+	"github.com/JonPulfer/serpent/dudect"
+)
+
+// Function runDudect implements "serpent dudect".
+func runDudect(args []string) error {
+	fs := flag.NewFlagSet("dudect", flag.ExitOnError)
+	targets := fs.String("targets", "encrypt,decrypt,keysetup",
+		"comma separated targets among encrypt, decrypt, keysetup, "+
+			"masked, reference, unpad and open")
+	counts := fs.String("measurements", "10000,100000,1000000",
+		"comma separated numbers of measurements to report after")
+	seed := fs.Int64("seed", 1, "seed of the inputs")
+	fs.Parse(args)
+
+	var checkpoints []int
+	for _, field := range strings.Split(*counts, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return err
+		}
+		checkpoints = append(checkpoints, n)
+	}
+	key := make([]byte, 32)
+	b, err := serpent.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	runner := dudect.Runner{Checkpoints: checkpoints, Seed: *seed}
+	for _, name := range strings.Split(*targets, ",") {
+		var target dudect.Target
+		switch name {
+		case "encrypt":
+			target = dudect.EncryptTarget(name, b)
+		case "decrypt":
+			target = dudect.DecryptTarget(name, b)
+		case "keysetup":
+			target = dudect.KeySetupTarget(name, 32, serpent.NewCipher)
+		case "masked":
+			m, err := serpent.NewMaskedCipher(serpent.Serpent1, key, nil)
+			if err != nil {
+				return err
+			}
+			target = dudect.EncryptTarget(name, m)
+		case "reference":
+			r, err := serpent.NewImplementationCipher("bitstring",
+				serpent.Serpent1, key)
+			if err != nil {
+				return err
+			}
+			target = dudect.EncryptTarget(name, r)
+		case "unpad":
+			target = dudect.UnpadTarget(name, 4*serpent.BlockSize,
+				serpent.BlockSize, serpent.Unpad)
+		case "open":
+			aead, err := cipher.NewGCM(b)
+			if err != nil {
+				return err
+			}
+			target = dudect.OpenTarget(name, aead, 4*serpent.BlockSize)
+		default:
+			return fmt.Errorf("unknown target %q", name)
+		}
+		if err := dudect.WriteReports(os.Stdout,
+			runner.Run(target)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
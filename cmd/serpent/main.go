@@ -0,0 +1,77 @@
+/*
+Command serpent gives command line access to the analysis tools of the
+serpent package.
+
+Usage:
+
+	serpent <command> [flags]
+
+The commands are:
+
+	avalanche	measure diffusion round by round
+	circuit	write Serpent as a Bristol Fashion circuit
+	cpa	simulate power traces and attack them by CPA
+	dudect	test encryption and key setup for constant time
+	model	write reduced-round Serpent as CNF or SMT-LIB2
+	randtest	run NIST SP 800-22 tests on CTR keystreams
+	sbox	report the cryptographic properties of the S-Boxes
+	sboxcircuit	search for small S-Box circuits
+	trail	search for the best differential or linear trail
+
+Run "serpent <command> -h" for the flags of a command.
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// A command runs one sub-command on its own arguments.
+type command struct {
+	run     func(args []string) error
+	summary string
+}
+
+var commands = map[string]command{
+	"avalanche":   {runAvalanche, "measure diffusion round by round"},
+	"circuit":     {runCircuit, "write Serpent as a Bristol Fashion circuit"},
+	"cpa":         {runCPA, "simulate power traces and attack them by CPA"},
+	"dudect":      {runDudect, "test encryption and key setup for constant time"},
+	"model":       {runModel, "write reduced-round Serpent as CNF or SMT-LIB2"},
+	"randtest":    {runRandtest, "run NIST SP 800-22 tests on CTR keystreams"},
+	"sbox":        {runSBox, "report the cryptographic properties of the S-Boxes"},
+	"sboxcircuit": {runSBoxCircuit, "search for small S-Box circuits"},
+	"trail":       {runTrail, "search for the best differential or linear trail"},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: serpent <command> [flags]\n\n"+
+		"The commands are:\n\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "\t%-12s %s\n", name, commands[name].summary)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "serpent: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "serpent %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
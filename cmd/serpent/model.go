@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runModel implements "serpent model".
+func runModel(args []string) error {
+	fs := flag.NewFlagSet("model", flag.ExitOnError)
+	format := fs.String("format", "dimacs", "output format: dimacs or smtlib")
+	rounds := fs.Int("rounds", 1, "number of rounds")
+	start := fs.Int("start", 0, "index of the first round")
+	plainText := fs.String("plaintext", "", "plaintext Bitstring, "+
+		"0 and 1 fix a bit, x leaves it free")
+	cipherText := fs.String("ciphertext", "", "ciphertext Bitstring")
+	key := fs.String("key", "", "user key Bitstring")
+	fs.Parse(args)
+
+	p := serpent.Serpent1
+	p.Rounds = *rounds
+	p.StartRound = *start
+	m, err := serpent.NewModel(p)
+	if err != nil {
+		return err
+	}
+	for _, fix := range []struct {
+		value string
+		f     func(serpent.Bitstring) error
+	}{
+		{*plainText, m.FixPlaintext},
+		{*cipherText, m.FixCiphertext},
+		{*key, m.FixKey},
+	} {
+		if fix.value == "" {
+			continue
+		}
+		if err := fix.f(serpent.Bitstring(fix.value)); err != nil {
+			return err
+		}
+	}
+
+	switch *format {
+	case "dimacs":
+		return m.WriteDIMACS(os.Stdout)
+	case "smtlib":
+		return m.WriteSMTLIB(os.Stdout)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/cipher"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+	"github.com/JonPulfer/serpent/randtest"
+)
+
+// Function runRandtest implements "serpent randtest".
+func runRandtest(args []string) error {
+	fs := flag.NewFlagSet("randtest", flag.ExitOnError)
+	rounds := fs.Int("rounds", 32, "number of rounds")
+	start := fs.Int("start", 0, "index of the first round")
+	n := fs.Int("bits", 1000000, "length of the keystream in bits")
+	seed := fs.Int64("seed", 1, "seed of the key and initial counter")
+	fs.Parse(args)
+	if *n <= 0 {
+		return fmt.Errorf("-bits must be positive, not %d", *n)
+	}
+
+	p := serpent.Serpent1
+	p.Rounds = *rounds
+	p.StartRound = *start
+	r := rand.New(rand.NewSource(*seed))
+	key := make([]byte, 32)
+	iv := make([]byte, serpent.BlockSize)
+	r.Read(key)
+	r.Read(iv)
+	b, err := serpent.NewCipherWithParams(p, key)
+	if err != nil {
+		return err
+	}
+	data := make([]byte, (*n+7)/8)
+	cipher.NewCTR(b, iv).XORKeyStream(data, data)
+
+	fmt.Printf("%d rounds from round %d, CTR keystream of %d bits\n",
+		*rounds, *start, *n)
+	return randtest.WriteResults(os.Stdout,
+		randtest.Battery{}.Run(randtest.Bits(data)[:*n]))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runSBox implements "serpent sbox".
+func runSBox(args []string) error {
+	fs := flag.NewFlagSet("sbox", flag.ExitOnError)
+	box := fs.Int("box", -1, "S-Box to report on, all when negative")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	table := fs.String("table", "ddt", "table written in csv format: "+
+		"ddt, lat or bct")
+	custom := fs.String("sbox", "", "comma separated S-Box to report on "+
+		"instead of the Serpent S-Boxes")
+	fs.Parse(args)
+
+	boxes := serpent.SBoxDecimalTable
+	first := 0
+	if *custom != "" {
+		var sbox serpent.SBox
+		for _, field := range strings.Split(*custom, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil {
+				return err
+			}
+			sbox = append(sbox, n)
+		}
+		if err := sbox.Validate(); err != nil {
+			return err
+		}
+		boxes = []serpent.SBox{sbox}
+	} else if *box >= 0 {
+		if *box >= len(boxes) {
+			return fmt.Errorf("no S-Box %d", *box)
+		}
+		boxes = boxes[*box : *box+1]
+		first = *box
+	}
+	var reports []serpent.SBoxReport
+	for _, sbox := range boxes {
+		reports = append(reports, sbox.Report())
+	}
+
+	switch *format {
+	case "text":
+		for i, r := range reports {
+			writeSBoxText(os.Stdout, first+i, r)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	case "csv":
+		if len(reports) != 1 {
+			return fmt.Errorf("csv output needs -box")
+		}
+		var t serpent.SBoxTable
+		switch *table {
+		case "ddt":
+			t = reports[0].DDT
+		case "lat":
+			t = reports[0].LAT
+		case "bct":
+			t = reports[0].BCT
+		default:
+			return fmt.Errorf("unknown table %q", *table)
+		}
+		return t.WriteCSV(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	return nil
+}
+
+// Function writeSBoxText writes a human readable report on S-Box 'i'.
+func writeSBoxText(w io.Writer, i int, r serpent.SBoxReport) {
+	fmt.Fprintf(w, "S%d: %v\n", i, []int(r.SBox))
+	fmt.Fprintf(w, "  differential uniformity  %d\n", r.DifferentialUniformity)
+	fmt.Fprintf(w, "  nonlinearity             %d\n", r.Nonlinearity)
+	fmt.Fprintf(w, "  boomerang uniformity     %d\n", r.BoomerangUniformity)
+	fmt.Fprintf(w, "  algebraic degree         %d\n", r.AlgebraicDegree)
+	fmt.Fprintf(w, "  fixed points             %v\n", r.FixedPoints)
+	for j, f := range r.ANF {
+		fmt.Fprintf(w, "  y%d = %s\n", j, f)
+	}
+	for _, t := range []struct {
+		name  string
+		table serpent.SBoxTable
+	}{{"DDT", r.DDT}, {"LAT", r.LAT}, {"BCT", r.BCT}} {
+		fmt.Fprintf(w, "  %s\n", t.name)
+		for _, row := range t.table {
+			fmt.Fprintf(w, "   ")
+			for _, v := range row {
+				fmt.Fprintf(w, " %3d", v)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runSBoxCircuit implements "serpent sboxcircuit".
+func runSBoxCircuit(args []string) error {
+	fs := flag.NewFlagSet("sboxcircuit", flag.ExitOnError)
+	box := fs.Int("box", 0, "S-Box to search a circuit for")
+	inverse := fs.Bool("inverse", false, "search for the inverse S-Box")
+	objective := fs.String("objective", "gates", "minimize gates or depth")
+	attempts := fs.Int("attempts", 2000, "number of randomized attempts")
+	seed := fs.Int64("seed", 1, "seed of the random choices")
+	goFile := fs.String("o", "", "write Go functions for every S-Box and "+
+		"its inverse to this file instead")
+	fs.Parse(args)
+
+	search := serpent.SBoxCircuitSearch{Attempts: *attempts, Seed: *seed}
+	switch *objective {
+	case "gates":
+		search.Objective = serpent.FewestGates
+	case "depth":
+		search.Objective = serpent.LowestDepth
+	default:
+		return fmt.Errorf("unknown objective %q", *objective)
+	}
+
+	if *goFile != "" {
+		return writeSBoxWords(*goFile, search)
+	}
+	if *box < 0 || *box >= len(serpent.SBoxDecimalTable) {
+		return fmt.Errorf("no S-Box %d", *box)
+	}
+	search.SBox = serpent.SBoxDecimalTable[*box]
+	if *inverse {
+		search.SBox = search.SBox.Inverse()
+	}
+	c, err := search.Best()
+	if err != nil {
+		return err
+	}
+	writeCircuitSummary(os.Stdout, c)
+	for _, g := range c.Gates {
+		if g.Op == serpent.INV {
+			fmt.Printf("w%d = %s w%d\n", g.Out, g.Op, g.In[0])
+		} else {
+			fmt.Printf("w%d = %s w%d w%d\n", g.Out, g.Op, g.In[0], g.In[1])
+		}
+	}
+	fmt.Printf("outputs w%d w%d w%d w%d\n", c.Outputs[0], c.Outputs[1],
+		c.Outputs[2], c.Outputs[3])
+	return nil
+}
+
+func writeCircuitSummary(w io.Writer, c *serpent.SBoxCircuit) {
+	fmt.Fprintf(w, "%d gates (%d AND, %d OR, %d XOR, %d INV), depth %d\n",
+		len(c.Gates), c.Count(serpent.AND), c.Count(serpent.OR),
+		c.Count(serpent.XOR), c.Count(serpent.INV), c.Depth())
+}
+
+// Function writeSBoxWords searches circuits for every S-Box and its
+// inverse and writes them as the Go functions of the word core.
+func writeSBoxWords(name string, search serpent.SBoxCircuitSearch) error {
+	src, err := generateSBoxWords(name, search)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, src, 0644)
+}
+
+// Function generateSBoxWords returns the formatted source that
+// writeSBoxWords writes to the file 'name'.
+func generateSBoxWords(name string,
+	search serpent.SBoxCircuitSearch) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by \"serpent sboxcircuit -o %s\"; "+
+		"DO NOT EDIT.\n\npackage serpent\n", name)
+	var names [2][]string
+	var circuits [2][]*serpent.SBoxCircuit
+	for i, sbox := range serpent.SBoxDecimalTable {
+		for k, s := range []serpent.SBox{sbox, sbox.Inverse()} {
+			search.SBox = s
+			c, err := search.Best()
+			if err != nil {
+				return nil, err
+			}
+			fn := fmt.Sprintf("sbox%dWords", i)
+			what := fmt.Sprintf("S%d", i)
+			if k == 1 {
+				fn = fmt.Sprintf("sboxInverse%dWords", i)
+				what = fmt.Sprintf("the inverse of S%d", i)
+			}
+			names[k] = append(names[k], fn)
+			circuits[k] = append(circuits[k], c)
+			fmt.Fprintf(&buf, "\n// Function %s applies %s in bitslice "+
+				"form.\n// ", fn, what)
+			writeCircuitSummary(&buf, c)
+			if err := c.WriteGo(&buf, fn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for k, table := range []string{"wordSBoxes", "wordSBoxesInverse"} {
+		fmt.Fprintf(&buf, "\nvar %s = [8]func(*[4]uint32){\n", table)
+		for _, fn := range names[k] {
+			fmt.Fprintf(&buf, "\t%s,\n", fn)
+		}
+		fmt.Fprintf(&buf, "}\n")
+	}
+	for k, table := range []string{"wordSBoxCircuits",
+		"wordSBoxCircuitsInverse"} {
+		fmt.Fprintf(&buf, "\n// The circuits of %s to %s as data.\n",
+			names[k][0], names[k][7])
+		fmt.Fprintf(&buf, "var %s = [8]*SBoxCircuit{\n", table)
+		for _, c := range circuits[k] {
+			writeCircuitValue(&buf, c)
+		}
+		fmt.Fprintf(&buf, "}\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// Function writeCircuitValue writes 'c' as a composite literal.
+func writeCircuitValue(w io.Writer, c *serpent.SBoxCircuit) {
+	fmt.Fprintf(w, "\t{\n\t\tGates: []Gate{\n")
+	for _, g := range c.Gates {
+		fmt.Fprintf(w, "\t\t\t{%s, [2]int{%d, %d}, %d},\n", g.Op, g.In[0],
+			g.In[1], g.Out)
+	}
+	fmt.Fprintf(w, "\t\t},\n\t\tOutputs: [4]int{%d, %d, %d, %d},\n\t},\n",
+		c.Outputs[0], c.Outputs[1], c.Outputs[2], c.Outputs[3])
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function TestGeneratedFile fails when sbox_words.go is not what
+// "serpent sboxcircuit -o sbox_words.go" writes with the default flags,
+// in which case running "go generate" in the package directory updates
+// it. The searches take about half a minute.
+func TestGeneratedFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the S-Box circuit searches in short mode")
+	}
+	want, err := generateSBoxWords("sbox_words.go",
+		serpent.SBoxCircuitSearch{Attempts: 2000, Seed: 1})
+	if err != nil {
+		t.Fatalf("generateSBoxWords failed: %v", err)
+	}
+	got, err := ioutil.ReadFile(filepath.Join("..", "..", "sbox_words.go"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("sbox_words.go is stale, run go generate\n")
+	}
+}
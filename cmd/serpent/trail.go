@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function runTrail implements "serpent trail".
+func runTrail(args []string) error {
+	fs := flag.NewFlagSet("trail", flag.ExitOnError)
+	kind := fs.String("kind", "differential", "differential or linear")
+	rounds := fs.Int("rounds", 3, "number of rounds")
+	start := fs.Int("start", 0, "index of the first round")
+	maxWeight := fs.Float64("max-weight", 128, "give up beyond this weight")
+	maxActive := fs.Int("max-active", 0, "most active S-Boxes per round, "+
+		"unlimited when 0")
+	fs.Parse(args)
+
+	p := serpent.Serpent1
+	p.Rounds = *rounds
+	p.StartRound = *start
+	s := serpent.TrailSearch{Params: p, MaxWeight: *maxWeight,
+		MaxActive: *maxActive}
+	switch *kind {
+	case "differential":
+		s.Kind = serpent.DifferentialTrail
+	case "linear":
+		s.Kind = serpent.LinearTrail
+	default:
+		return fmt.Errorf("unknown kind %q", *kind)
+	}
+	trail, err := s.Best()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(trail)
+}
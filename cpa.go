@@ -0,0 +1,142 @@
+package serpent
+
+import (
+	"fmt"
+	"math"
+)
+
+// CPA is a correlation power analysis of the subkey mixed in before the
+// S-Boxes of the first round, K[StartRound] of Params. For each S-Box it
+// correlates sample j of the traces, as produced by LeakageSimulator,
+// with the leakage predicted under Model by each of the 16 key guesses,
+// and keeps the guess correlating best.
+type CPA struct {
+	Params Params
+	Model  LeakageModel
+}
+
+// CPAResult holds the outcome of an attack.
+type CPAResult struct {
+	// Subkey is the recovered subkey in bitslice format.
+	Subkey Bitstring
+	// Nibbles[j] is the key recovered for S-Box j.
+	Nibbles [32]int
+	// Correlations[j][k] is the correlation of key guess k for S-Box j.
+	Correlations [32][16]float64
+}
+
+// CPASuccess is the success rate of attacks using Traces traces: Nibbles
+// is the fraction of S-Box keys recovered and Subkey the fraction of
+// attacks recovering the whole subkey.
+type CPASuccess struct {
+	Traces          int
+	Nibbles, Subkey float64
+}
+
+// Method Attack recovers the subkey from 'traces'.
+func (c CPA) Attack(traces []Trace) (*CPAResult, error) {
+	if err := c.Params.Validate(); err != nil {
+		return nil, err
+	}
+	if len(traces) < 2 {
+		return nil, fmt.Errorf("serpent: at least two traces are "+
+			"required, got %d", len(traces))
+	}
+	for i := range traces {
+		if len(traces[i].Samples) != 32 {
+			return nil, fmt.Errorf("serpent: trace %d has %d samples, "+
+				"want 32", i, len(traces[i].Samples))
+		}
+	}
+	v := newVariant(c.Params)
+	sbox := v.SBoxes[v.box(v.StartRound)]
+	result := &CPAResult{}
+	var k [4]uint32
+	hypothesis := make([]float64, len(traces))
+	samples := make([]float64, len(traces))
+	for j := uint(0); j < 32; j++ {
+		for t := range traces {
+			samples[t] = traces[t].Samples[j]
+		}
+		best := 0
+		for guess := 0; guess < 16; guess++ {
+			for t := range traces {
+				x := loadWords(traces[t].Plaintext[:])
+				in := column(&x, j) ^ guess
+				hypothesis[t] = float64(leakage(c.Model, in, sbox[in]))
+			}
+			r := correlation(hypothesis, samples)
+			result.Correlations[j][guess] = r
+			if r > result.Correlations[j][best] {
+				best = guess
+			}
+		}
+		result.Nibbles[j] = best
+		for l := uint(0); l < 4; l++ {
+			k[l] |= uint32(best>>l&1) << j
+		}
+	}
+	result.Subkey = wordsToBitstring(&k)
+	return result, nil
+}
+
+// Method SuccessRate attacks traces of the simulator 's' 'experiments'
+// times for each number of traces in 'counts', experiment e using the
+// seed of 's' plus e. The parameters of 's' should be those of 'c'.
+func (c CPA) SuccessRate(s LeakageSimulator, counts []int,
+	experiments int) ([]CPASuccess, error) {
+	b, err := NewCipherWithParams(s.Params, s.Key)
+	if err != nil {
+		return nil, err
+	}
+	want := b.(*block).k[s.Params.StartRound]
+	var result []CPASuccess
+	for _, n := range counts {
+		rate := CPASuccess{Traces: n}
+		for e := 0; e < experiments; e++ {
+			sim := s
+			sim.Seed = s.Seed + int64(e)
+			traces, err := sim.Traces(n)
+			if err != nil {
+				return nil, err
+			}
+			r, err := c.Attack(traces)
+			if err != nil {
+				return nil, err
+			}
+			found := 0
+			for j := uint(0); j < 32; j++ {
+				if r.Nibbles[j] == column(&want, j) {
+					found++
+				}
+			}
+			rate.Nibbles += float64(found) / 32
+			if found == 32 {
+				rate.Subkey++
+			}
+		}
+		rate.Nibbles /= float64(experiments)
+		rate.Subkey /= float64(experiments)
+		result = append(result, rate)
+	}
+	return result, nil
+}
+
+// Function correlation returns the Pearson correlation of 'x' and 'y', or
+// 0 when either is constant.
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	var sx, sy, sxx, syy, sxy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+	}
+	d := math.Sqrt((n*sxx - sx*sx) * (n*syy - sy*sy))
+	if d == 0 || math.IsNaN(d) {
+		return 0
+	}
+	return (n*sxy - sx*sy) / d
+}
@@ -0,0 +1,93 @@
+package serpent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// Function TestCPA recovers the first subkey from noise free and noisy
+// traces under both leakage models.
+func TestCPA(t *testing.T) {
+	key := []byte("sixteen byte key")
+	b, _ := NewCipher(key)
+	want := wordsToBitstring(&b.(*block).k[0])
+	for _, model := range []LeakageModel{HammingWeight, HammingDistance} {
+		for _, noise := range []float64{0, 1} {
+			s := LeakageSimulator{Params: Serpent1, Key: key, Model: model,
+				Noise: noise, Seed: 1}
+			traces, err := s.Traces(500)
+			if err != nil {
+				t.Fatalf("Traces failed: %v\n", err)
+			}
+			r, err := CPA{Params: Serpent1, Model: model}.Attack(traces)
+			if err != nil {
+				t.Fatalf("Attack failed: %v\n", err)
+			}
+			if r.Subkey != want {
+				t.Errorf("model %d, noise %v: wrong subkey\n", model,
+					noise)
+			}
+		}
+	}
+}
+
+// Function TestCPASuccessRate checks that the success rate grows with the
+// number of traces.
+func TestCPASuccessRate(t *testing.T) {
+	s := LeakageSimulator{Params: Serpent1, Key: make([]byte, 32),
+		Noise: 2, Seed: 1}
+	rates, err := CPA{Params: Serpent1}.SuccessRate(s, []int{5, 50, 500},
+		5)
+	if err != nil {
+		t.Fatalf("SuccessRate failed: %v\n", err)
+	}
+	if !(rates[0].Nibbles < rates[1].Nibbles &&
+		rates[1].Nibbles < rates[2].Nibbles) || rates[2].Subkey != 1 {
+		t.Errorf("unexpected success rates %v\n", rates)
+	}
+}
+
+// Function TestTraceFile checks that traces survive the trace file format.
+func TestTraceFile(t *testing.T) {
+	s := LeakageSimulator{Params: Serpent1, Key: make([]byte, 16)}
+	traces, _ := s.Traces(3)
+	var buf bytes.Buffer
+	if err := WriteTraces(&buf, traces); err != nil {
+		t.Fatalf("WriteTraces failed: %v\n", err)
+	}
+	if buf.Len() != 12+3*(32+4*32) {
+		t.Errorf("trace file has %d bytes\n", buf.Len())
+	}
+	back, err := ReadTraces(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadTraces failed: %v\n", err)
+	}
+	if !reflect.DeepEqual(back, traces) {
+		t.Errorf("traces changed in the trace file\n")
+	}
+	if _, err := ReadTraces(bytes.NewReader(buf.Bytes()[:50])); err == nil {
+		t.Errorf("truncated trace file accepted\n")
+	}
+	huge := append([]byte(nil), buf.Bytes()[:12]...)
+	binary.LittleEndian.PutUint32(huge[8:], 0xffffffff)
+	if _, err := ReadTraces(bytes.NewReader(huge)); err == nil {
+		t.Errorf("trace file with 2^32-1 samples per trace accepted\n")
+	}
+
+	// A header claiming many long traces with no data behind it must not
+	// allocate for them.
+	binary.LittleEndian.PutUint32(huge[4:], 0xffffffff)
+	binary.LittleEndian.PutUint32(huge[8:], maxTraceSamples)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := ReadTraces(bytes.NewReader(huge)); err == nil {
+		t.Errorf("trace file without data accepted\n")
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Errorf("ReadTraces allocated %d bytes for an empty file\n", n)
+	}
+}
@@ -0,0 +1,151 @@
+package serpent
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// A FaultPair holds the correct and the faulty ciphertext of the same
+// plaintext as 128-bit Bitstrings.
+type FaultPair struct {
+	Correct, Faulty Bitstring
+}
+
+// DFA is a differential fault analysis of the last two rounds. It assumes
+// every fault flipped a single, unknown bit of the state before the
+// S-Boxes of a round. A flipped bit reaches only one S-Box, whose key
+// nibble is then limited to the values for which decrypting both
+// ciphertexts through the S-Box gives inputs one bit apart.
+type DFA struct {
+	Params Params
+	// Last holds pairs faulted before the S-Boxes of the final round.
+	Last []FaultPair
+	// Penultimate holds pairs faulted before the S-Boxes of the round
+	// before it.
+	Penultimate []FaultPair
+}
+
+// Method LastSubkey recovers the subkey mixed in after the final round, in
+// bitslice format.
+func (d DFA) LastSubkey() (Bitstring, error) {
+	if err := d.Params.Validate(); err != nil {
+		return "", err
+	}
+	v := newVariant(d.Params)
+	last := v.lastRound()
+	k, err := v.dfaSubkey(last, d.Last, func(x *[4]uint32) {})
+	if err != nil {
+		return "", err
+	}
+	return wordsToBitstring(&k), nil
+}
+
+// Method PenultimateSubkey recovers the subkey of the final round, in
+// bitslice format, given the subkey 'lastSubkey' mixed in after it. The
+// ciphertexts are decrypted through the final round up to the subkey,
+// which is recovered through the linear transformation as the equivalent
+// key LT^-1(K).
+func (d DFA) PenultimateSubkey(lastSubkey Bitstring) (Bitstring, error) {
+	if err := d.Params.Validate(); err != nil {
+		return "", err
+	}
+	v := newVariant(d.Params)
+	last := v.lastRound()
+	if last == v.StartRound {
+		return "", fmt.Errorf("serpent: a single round has no " +
+			"penultimate round")
+	}
+	K := wordsFromBitstring(lastSubkey)
+	k, err := v.dfaSubkey(last-1, d.Penultimate, func(x *[4]uint32) {
+		xorWords(x, &K)
+		v.sWordsInverse(last, x)
+		v.ltWordsInverse(x)
+	})
+	if err != nil {
+		return "", err
+	}
+	v.ltWords(&k)
+	return wordsToBitstring(&k), nil
+}
+
+// Method Key recovers the last two subkeys and inverts the key schedule
+// with them, see RecoverUserKey.
+func (d DFA) Key() (Bitstring, []int, error) {
+	K2, err := d.LastSubkey()
+	if err != nil {
+		return "", nil, err
+	}
+	K1, err := d.PenultimateSubkey(K2)
+	if err != nil {
+		return "", nil, err
+	}
+	last := d.Params.StartRound + d.Params.Rounds - 1
+	return d.Params.RecoverUserKey([]Bitstring{K1, K2}, last)
+}
+
+// Method dfaSubkey recovers the key xored onto the output of the S-Boxes
+// of round 'i' from 'pairs' faulted before them. 'peel' maps a
+// ciphertext onto that output.
+func (v *variant) dfaSubkey(i int, pairs []FaultPair,
+	peel func(x *[4]uint32)) (k [4]uint32, err error) {
+	inverse := v.SBoxes[v.box(i)].Inverse()
+	// candidates[j] has bit n set while n is possible for column j.
+	var candidates [32]uint16
+	for j := range candidates {
+		candidates[j] = 0xffff
+	}
+	for _, pair := range pairs {
+		if err := checkFaultPair(pair); err != nil {
+			return k, err
+		}
+		c := wordsFromBitstring(pair.Correct)
+		f := wordsFromBitstring(pair.Faulty)
+		peel(&c)
+		peel(&f)
+		for j := uint(0); j < 32; j++ {
+			a, b := column(&c, j), column(&f, j)
+			if a == b {
+				continue
+			}
+			for n := 0; n < 16; n++ {
+				if bits.OnesCount(uint(inverse[a^n]^inverse[b^n])) != 1 {
+					candidates[j] &^= 1 << uint(n)
+				}
+			}
+		}
+	}
+	for j := uint(0); j < 32; j++ {
+		switch bits.OnesCount16(candidates[j]) {
+		case 0:
+			return k, fmt.Errorf("serpent: no key fits column %d; a "+
+				"fault flipped more than one bit", j)
+		case 1:
+			n := bits.TrailingZeros16(candidates[j])
+			for l := uint(0); l < 4; l++ {
+				k[l] |= uint32(n>>l&1) << j
+			}
+		default:
+			return k, fmt.Errorf("serpent: %d keys fit column %d; more "+
+				"faulty ciphertexts are needed",
+				bits.OnesCount16(candidates[j]), j)
+		}
+	}
+	return k, nil
+}
+
+// Function column returns the S-Box input or output 'j' of the words 'x',
+// the bits j of the four words.
+func column(x *[4]uint32, j uint) int {
+	return int(x[0]>>j&1 | x[1]>>j&1<<1 | x[2]>>j&1<<2 | x[3]>>j&1<<3)
+}
+
+func checkFaultPair(pair FaultPair) error {
+	if len(pair.Correct) != 128 || len(pair.Faulty) != 128 {
+		return fmt.Errorf("serpent: fault pair ciphertexts must have " +
+			"128 bits")
+	}
+	if err := checkBits(pair.Correct); err != nil {
+		return err
+	}
+	return checkBits(pair.Faulty)
+}
@@ -0,0 +1,178 @@
+// Package dudect tests whether an operation runs in constant time, in the
+// manner of dudect (Reparaz, Balasch and Verbauwhede, "Dude, is my code
+// constant time?", 2017).
+//
+// The operation is timed on inputs of two classes, a fixed input and
+// random ones, chosen at random for every measurement. Welch's t-test then
+// compares the two timing distributions: a large |t| means the running
+// time depends on the input. As in dudect the test is repeated on the
+// measurements below several percentiles, which removes the long tail
+// caused by interrupts and other noise, and the largest |t| is reported.
+package dudect
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Threshold is the |t| above which a Report flags timing leakage. Values
+// around 4.5 already make a false positive very unlikely.
+const Threshold = 4.5
+
+// batch is the number of inputs prepared at a time.
+const batch = 1000
+
+// crops is the number of percentiles tested on top of the raw
+// measurements.
+const crops = 10
+
+// A Target is an operation under test.
+type Target struct {
+	Name string
+	// Input returns an input of class 0, the fixed class, or class 1,
+	// the random class.
+	Input func(class int, r *rand.Rand) []byte
+	// Run performs the operation on an input.
+	Run func(input []byte)
+}
+
+// Runner runs the test.
+type Runner struct {
+	// Checkpoints lists the numbers of measurements after which a
+	// Report is made, in increasing order; nil means 1e4, 1e5 and 1e6.
+	Checkpoints []int
+	// Warmup is the number of measurements used to find the
+	// percentiles and then discarded; zero means 1000.
+	Warmup int
+	Seed   int64
+}
+
+// Report is the state of the test after a number of measurements.
+type Report struct {
+	Target       string
+	Measurements int
+	// T is the largest |t| statistic over the raw and cropped
+	// measurements.
+	T float64
+}
+
+// Method Leak reports whether T exceeds Threshold.
+func (r Report) Leak() bool {
+	return r.T > Threshold
+}
+
+// Method Run measures 'target' and returns one Report per checkpoint.
+func (r Runner) Run(target Target) []Report {
+	checkpoints := r.Checkpoints
+	if checkpoints == nil {
+		checkpoints = []int{1e4, 1e5, 1e6}
+	}
+	warmup := r.Warmup
+	if warmup == 0 {
+		warmup = 1000
+	}
+	rng := rand.New(rand.NewSource(r.Seed))
+	// Inputs are prepared in batches ahead of the measurements, so that
+	// preparing them does not disturb the timing of one class.
+	var classes []int
+	var inputs [][]byte
+	measure := func() (int, float64) {
+		if len(classes) == 0 {
+			classes = make([]int, batch)
+			inputs = make([][]byte, batch)
+			for i := range classes {
+				classes[i] = rng.Intn(2)
+				inputs[i] = target.Input(classes[i], rng)
+			}
+		}
+		class, input := classes[0], inputs[0]
+		classes, inputs = classes[1:], inputs[1:]
+		start := time.Now()
+		target.Run(input)
+		return class, float64(time.Since(start))
+	}
+
+	first := make([]float64, warmup)
+	for i := range first {
+		_, first[i] = measure()
+	}
+	sort.Float64s(first)
+	var limits [crops]float64
+	for i := range limits {
+		p := 1 - math.Pow(0.5, 10*float64(i+1)/crops)
+		limits[i] = first[int(p*float64(warmup-1))]
+	}
+
+	var raw Welch
+	var cropped [crops]Welch
+	var reports []Report
+	n := 0
+	for _, checkpoint := range checkpoints {
+		for ; n < checkpoint; n++ {
+			class, d := measure()
+			raw.Add(class, d)
+			for i := range cropped {
+				if d < limits[i] {
+					cropped[i].Add(class, d)
+				}
+			}
+		}
+		t := math.Abs(raw.T())
+		for i := range cropped {
+			t = math.Max(t, math.Abs(cropped[i].T()))
+		}
+		reports = append(reports, Report{Target: target.Name,
+			Measurements: n, T: t})
+	}
+	return reports
+}
+
+// Welch accumulates two samples for Welch's t-test, keeping running means
+// and variances.
+type Welch struct {
+	n        [2]float64
+	mean, m2 [2]float64
+}
+
+// Method Add adds 'x' to the sample of class 'class', 0 or 1.
+func (w *Welch) Add(class int, x float64) {
+	w.n[class]++
+	d := x - w.mean[class]
+	w.mean[class] += d / w.n[class]
+	w.m2[class] += d * (x - w.mean[class])
+}
+
+// Method T returns the t statistic, 0 while either sample has fewer than
+// two elements or both are constant.
+func (w *Welch) T() float64 {
+	if w.n[0] < 2 || w.n[1] < 2 {
+		return 0
+	}
+	v0 := w.m2[0] / (w.n[0] - 1)
+	v1 := w.m2[1] / (w.n[1] - 1)
+	d := math.Sqrt(v0/w.n[0] + v1/w.n[1])
+	if d == 0 {
+		return 0
+	}
+	return (w.mean[0] - w.mean[1]) / d
+}
+
+// Function WriteReports writes one line per report.
+func WriteReports(w io.Writer, reports []Report) error {
+	for _, r := range reports {
+		verdict := "no leakage detected"
+		if r.Leak() {
+			verdict = "LEAKAGE"
+		}
+		if _, err := fmt.Fprintf(w, "%-20s %10d measurements  |t| = "+
+			"%8.2f  %s\n", r.Target, r.Measurements, r.T,
+			verdict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package dudect
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function TestWelch checks the t statistic against a value computed by
+// hand.
+func TestWelch(t *testing.T) {
+	var w Welch
+	for _, x := range []float64{1, 2, 3, 4} {
+		w.Add(0, x)
+	}
+	for _, x := range []float64{2, 4, 6} {
+		w.Add(1, x)
+	}
+	// Means 2.5 and 4, variances 5/3 and 4.
+	want := -1.5 / math.Sqrt(5.0/12+4.0/3)
+	if got := w.T(); math.Abs(got-want) > 1e-12 {
+		t.Errorf("T = %v, want %v\n", got, want)
+	}
+	var empty Welch
+	empty.Add(0, 1)
+	if empty.T() != 0 {
+		t.Errorf("T of a single measurement is not 0\n")
+	}
+}
+
+// Function TestRunnerDetectsLeak runs a target whose running time grows
+// with its input.
+func TestRunnerDetectsLeak(t *testing.T) {
+	sink := 0
+	leaky := Target{
+		Name: "leaky",
+		Input: func(class int, r *rand.Rand) []byte {
+			return []byte{byte(class * 255)}
+		},
+		Run: func(input []byte) {
+			for i := 0; i < 20*int(input[0]); i++ {
+				sink += i
+			}
+		},
+	}
+	reports := Runner{Checkpoints: []int{1000, 5000}}.Run(leaky)
+	if len(reports) != 2 || reports[1].Measurements != 5000 {
+		t.Fatalf("unexpected reports %v\n", reports)
+	}
+	if !reports[1].Leak() {
+		t.Errorf("leak not detected: |t| = %.2f\n", reports[1].T)
+	}
+}
+
+// Function TestTargets checks the fixed class inputs of the padding and
+// tag verification targets: a message padded but for one byte and the
+// tag but for one bit.
+func TestTargets(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	unpad := UnpadTarget("unpad", 32, 16, nil)
+	input := unpad.Input(0, r)
+	if len(input) != 32 || input[16] != 0 || input[17] != 16 ||
+		input[31] != 16 {
+		t.Errorf("unpad fixed input %x\n", input)
+	}
+
+	b, _ := serpent.NewCipher(make([]byte, 16))
+	aead, _ := cipher.NewGCM(b)
+	open := OpenTarget("open", aead, 32)
+	tag := open.Input(0, r)
+	sealed := aead.Seal(nil, make([]byte, aead.NonceSize()),
+		make([]byte, 32), nil)
+	tag[len(tag)-1] ^= 1
+	if !bytes.Equal(tag, sealed[32:]) {
+		t.Errorf("open fixed input %x, want the tag %x but for the last "+
+			"bit\n", tag, sealed[32:])
+	}
+	if bytes.Equal(open.Input(1, r), tag) {
+		t.Errorf("open random input is the tag\n")
+	}
+}
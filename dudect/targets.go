@@ -0,0 +1,105 @@
+package dudect
+
+import (
+	"crypto/cipher"
+	"math/rand"
+)
+
+// Function EncryptTarget returns the target encrypting one block with 'b':
+// the fixed class is the all zero block, the random class random blocks.
+func EncryptTarget(name string, b cipher.Block) Target {
+	dst := make([]byte, b.BlockSize())
+	return Target{
+		Name:  name,
+		Input: randomInput(b.BlockSize()),
+		Run:   func(input []byte) { b.Encrypt(dst, input) },
+	}
+}
+
+// Function DecryptTarget is EncryptTarget for decryption.
+func DecryptTarget(name string, b cipher.Block) Target {
+	dst := make([]byte, b.BlockSize())
+	return Target{
+		Name:  name,
+		Input: randomInput(b.BlockSize()),
+		Run:   func(input []byte) { b.Decrypt(dst, input) },
+	}
+}
+
+// Function KeySetupTarget returns the target creating a cipher.Block with
+// 'newCipher' from a key of 'size' bytes: the fixed class is the all zero
+// key, the random class random keys.
+func KeySetupTarget(name string, size int,
+	newCipher func(key []byte) (cipher.Block, error)) Target {
+	return Target{
+		Name:  name,
+		Input: randomInput(size),
+		Run:   func(input []byte) { newCipher(input) },
+	}
+}
+
+// Function UnpadTarget returns the target removing the padding of a
+// message of 'size' bytes with 'unpad'. Both classes are badly padded,
+// since whether the padding is good shows anyway: the fixed class is a
+// message padded with a full block of 'blockSize' bytes but for the first,
+// which a removal stopping at the first bad byte checks last, the random
+// class random messages.
+func UnpadTarget(name string, size, blockSize int,
+	unpad func(data []byte) ([]byte, error)) Target {
+	return Target{
+		Name: name,
+		Input: func(class int, r *rand.Rand) []byte {
+			input := make([]byte, size)
+			if class == 1 {
+				r.Read(input)
+				return input
+			}
+			for i := size - blockSize + 1; i < size; i++ {
+				input[i] = byte(blockSize)
+			}
+			return input
+		},
+		Run: func(input []byte) { unpad(input) },
+	}
+}
+
+// Function OpenTarget returns the target verifying the tag of a message of
+// 'size' bytes with 'aead'. Both classes are wrong tags, since a message
+// with the right tag is decrypted as well: the fixed class is the right
+// tag but for the last byte, which a comparison stopping at the first
+// difference reaches last, the random class random tags.
+func OpenTarget(name string, aead cipher.AEAD, size int) Target {
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, nonce, make([]byte, size), nil)
+	tag := sealed[size:]
+	message := append([]byte(nil), sealed...)
+	dst := make([]byte, 0, size)
+	return Target{
+		Name: name,
+		Input: func(class int, r *rand.Rand) []byte {
+			input := append([]byte(nil), tag...)
+			if class == 1 {
+				r.Read(input)
+			} else {
+				input[len(input)-1] ^= 1
+			}
+			return input
+		},
+		Run: func(input []byte) {
+			copy(message[size:], input)
+			aead.Open(dst, nonce, message, nil)
+		},
+	}
+}
+
+// Function randomInput returns an Input function for inputs of 'size'
+// bytes, all zero in the fixed class.
+func randomInput(size int) func(int, *rand.Rand) []byte {
+	return func(class int, r *rand.Rand) []byte {
+		input := make([]byte, size)
+		if class == 1 {
+			r.Read(input)
+		}
+		return input
+	}
+}
@@ -0,0 +1,224 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// A FaultStep names the point of a round at which a fault is injected.
+type FaultStep int
+
+const (
+	// BeforeKeyMixing is the input of the round.
+	BeforeKeyMixing FaultStep = iota
+	// BeforeSBox follows the key mixing.
+	BeforeSBox
+	// BeforeLT follows the S-Boxes. In the final round it precedes the
+	// mixing of the last subkey.
+	BeforeLT
+	// AfterLT is the output of the round, after the linear transformation
+	// or, in the final round, the last key mixing.
+	AfterLT
+)
+
+var faultStepNames = []string{"before key mixing", "before S-Box",
+	"before LT", "after LT"}
+
+func (s FaultStep) String() string {
+	if s < 0 || int(s) >= len(faultStepNames) {
+		return fmt.Sprintf("FaultStep(%d)", int(s))
+	}
+	return faultStepNames[s]
+}
+
+// A Fault flips bits of the state during encryption.
+type Fault struct {
+	// Round is the index of the round the fault is injected in.
+	Round int
+	// Step is the point of the round at which the bits are flipped.
+	Step FaultStep
+	// Mask is a 128-bit Bitstring whose 1 bits are flipped. It is given
+	// in the bitslice domain, numbered like the byte API, so the same
+	// fault hits the same bits of every implementation; the normal
+	// algorithm applies it after IP.
+	Mask Bitstring
+}
+
+// Function BitFault returns the fault flipping bit 'bit' of the state, or
+// an error if there is no such bit.
+func BitFault(round int, step FaultStep, bit int) (Fault, error) {
+	if bit < 0 || bit >= 128 {
+		return Fault{}, fmt.Errorf("serpent: fault bit %d outside 0..127",
+			bit)
+	}
+	mask := []byte(strings.Repeat("0", 128))
+	mask[bit] = '1'
+	return Fault{Round: round, Step: step, Mask: Bitstring(mask)}, nil
+}
+
+// Function ByteFault returns the fault xoring 'value' into byte 'i' of the
+// state, or an error if there is no such byte.
+func ByteFault(round int, step FaultStep, i int, value byte) (Fault, error) {
+	if i < 0 || i >= BlockSize {
+		return Fault{}, fmt.Errorf("serpent: fault byte %d outside 0..%d",
+			i, BlockSize-1)
+	}
+	var b [BlockSize]byte
+	b[i] = value
+	return Fault{Round: round, Step: step,
+		Mask: BitstringFromBytes(b[:])}, nil
+}
+
+// Method checkFaults checks that every fault hits a round of 'v' at a
+// known step with a 128-bit mask.
+func (v *variant) checkFaults(faults []Fault) error {
+	for _, f := range faults {
+		if f.Round < v.StartRound || f.Round > v.lastRound() {
+			return fmt.Errorf("serpent: fault in round %d outside rounds "+
+				"%d to %d", f.Round, v.StartRound, v.lastRound())
+		}
+		if f.Step < BeforeKeyMixing || f.Step > AfterLT {
+			return fmt.Errorf("serpent: unknown fault step %d", f.Step)
+		}
+		if len(f.Mask) != 128 {
+			return fmt.Errorf("serpent: fault mask has %d bits, want 128",
+				len(f.Mask))
+		}
+		if err := checkBits(f.Mask); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function injectBitstring flips the masks of the faults hitting round
+// 'i' at step 'step'. 'domain' maps a mask into the domain of 'state'.
+func injectBitstring(faults []Fault, i int, step FaultStep, state Bitstring,
+	domain func(Bitstring) Bitstring) Bitstring {
+	for _, f := range faults {
+		if f.Round == i && f.Step == step {
+			state = state.BinaryXor(domain(f.Mask))
+		}
+	}
+	return state
+}
+
+// Method EncryptFaulty encrypts 'plainText' by the normal algorithm while
+// injecting 'faults'.
+func (c *Cipher) EncryptFaulty(plainText Bitstring,
+	faults ...Fault) (Bitstring, error) {
+	if err := c.v.checkFaults(faults); err != nil {
+		return "", err
+	}
+	v, KHat := c.v, c.normalKeys()
+	last := v.lastRound()
+	BHat := IP(plainText)
+	for i := v.StartRound; i <= last; i++ {
+		BHat = injectBitstring(faults, i, BeforeKeyMixing, BHat, IP)
+		xored := KHat.mixKey(i, BHat)
+		xored = injectBitstring(faults, i, BeforeSBox, xored, IP)
+		SHati := injectBitstring(faults, i, BeforeLT, v.sHat(i, xored), IP)
+		if i == last {
+			BHat = KHat.mixKey(last+1, SHati)
+		} else {
+			BHat = v.lt(SHati)
+		}
+		BHat = injectBitstring(faults, i, AfterLT, BHat, IP)
+	}
+	return FP(BHat), nil
+}
+
+// Method EncryptBitsliceFaulty encrypts 'plainText' by the bitslice
+// algorithm while injecting 'faults'.
+func (c *Cipher) EncryptBitsliceFaulty(plainText Bitstring,
+	faults ...Fault) (Bitstring, error) {
+	if err := c.v.checkFaults(faults); err != nil {
+		return "", err
+	}
+	v, K := c.v, c.bitsliceKeys()
+	last := v.lastRound()
+	same := func(s Bitstring) Bitstring { return s }
+	B := plainText
+	for i := v.StartRound; i <= last; i++ {
+		B = injectBitstring(faults, i, BeforeKeyMixing, B, same)
+		xored := injectBitstring(faults, i, BeforeSBox, K.mixKey(i, B),
+			same)
+		S := B.QuadJoin(v.sBitslice(i, xored.QuadSplit()))
+		S = injectBitstring(faults, i, BeforeLT, S, same)
+		if i == last {
+			B = K.mixKey(last+1, S)
+		} else {
+			B = B.QuadJoin(v.ltBitslice(S.QuadSplit()))
+		}
+		B = injectBitstring(faults, i, AfterLT, B, same)
+	}
+	return B, nil
+}
+
+// A wordFault is a Fault with its mask converted to words.
+type wordFault struct {
+	round int
+	step  FaultStep
+	mask  [4]uint32
+}
+
+// A faultyBlock is a block whose encryption injects faults.
+type faultyBlock struct {
+	block
+	faults []wordFault
+}
+
+// Function NewFaultyCipher creates a cipher.Block like NewCipherWithParams
+// whose Encrypt injects 'faults' into the word implementation. Decrypt is
+// left fault free.
+func NewFaultyCipher(p Params, key []byte,
+	faults ...Fault) (cipher.Block, error) {
+	b, err := NewCipherWithParams(p, key)
+	if err != nil {
+		return nil, err
+	}
+	fb := &faultyBlock{block: *b.(*block)}
+	if err := fb.v.checkFaults(faults); err != nil {
+		return nil, err
+	}
+	for _, f := range faults {
+		fb.faults = append(fb.faults,
+			wordFault{f.Round, f.Step, wordsFromBitstring(f.Mask)})
+	}
+	return fb, nil
+}
+
+func (b *faultyBlock) Encrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	b.keys.check()
+	x := loadWords(src)
+	v, K := b.v, b.k
+	last := v.lastRound()
+	for i := v.StartRound; i <= last; i++ {
+		b.inject(i, BeforeKeyMixing, &x)
+		xorWords(&x, &K[i])
+		b.inject(i, BeforeSBox, &x)
+		v.sWords(i, &x)
+		b.inject(i, BeforeLT, &x)
+		if i == last {
+			xorWords(&x, &K[last+1])
+		} else {
+			v.ltWords(&x)
+		}
+		b.inject(i, AfterLT, &x)
+	}
+	storeWords(dst, &x)
+	runtime.KeepAlive(b.keys)
+}
+
+// Method inject flips the masks of the faults hitting round 'i' at step
+// 'step'.
+func (b *faultyBlock) inject(i int, step FaultStep, x *[4]uint32) {
+	for k := range b.faults {
+		if f := &b.faults[k]; f.round == i && f.step == step {
+			xorWords(x, &f.mask)
+		}
+	}
+}
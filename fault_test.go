@@ -0,0 +1,99 @@
+package serpent
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// Function TestFaultImplementationsAgree injects the same faults into the
+// normal, bitslice and word implementations.
+func TestFaultImplementationsAgree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	p := Serpent1
+	p.Rounds = 6
+	key := randomBytes(r, 16)
+	c, _ := New(p, BitstringFromBytes(key))
+	for i := 0; i < 8; i++ {
+		bit, err := BitFault(r.Intn(6), FaultStep(r.Intn(4)), r.Intn(128))
+		if err != nil {
+			t.Fatalf("BitFault failed: %v\n", err)
+		}
+		byt, err := ByteFault(r.Intn(6), FaultStep(r.Intn(4)), r.Intn(16),
+			byte(r.Intn(256)))
+		if err != nil {
+			t.Fatalf("ByteFault failed: %v\n", err)
+		}
+		faults := []Fault{bit, byt}
+		b, err := NewFaultyCipher(p, key, faults...)
+		if err != nil {
+			t.Fatalf("NewFaultyCipher failed: %v\n", err)
+		}
+		plain := randomBytes(r, 16)
+		got := make([]byte, BlockSize)
+		b.Encrypt(got, plain)
+		normal, _ := c.EncryptFaulty(BitstringFromBytes(plain), faults...)
+		bitslice, _ := c.EncryptBitsliceFaulty(BitstringFromBytes(plain),
+			faults...)
+		if normal != bitslice || !bytes.Equal(normal.Bytes(), got) {
+			t.Errorf("faults %v: implementations disagree\n", faults)
+		}
+		if normal == c.Encrypt(BitstringFromBytes(plain)) {
+			t.Errorf("faults %v: no effect\n", faults)
+		}
+	}
+	outside, _ := BitFault(6, BeforeSBox, 0)
+	if _, err := c.EncryptFaulty(testPlainText, outside); err == nil {
+		t.Errorf("fault outside the rounds accepted\n")
+	}
+	for _, bit := range []int{-1, 128} {
+		if _, err := BitFault(0, BeforeSBox, bit); err == nil {
+			t.Errorf("bit %d accepted\n", bit)
+		}
+	}
+	for _, i := range []int{-1, BlockSize} {
+		if _, err := ByteFault(0, BeforeSBox, i, 1); err == nil {
+			t.Errorf("byte %d accepted\n", i)
+		}
+	}
+}
+
+// Function TestDFA recovers a 128-bit key from single bit faults before
+// the S-Boxes of the last two rounds.
+func TestDFA(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	key := randomBytes(r, 16)
+	correct, _ := NewCipher(key)
+	var d DFA
+	d.Params = Serpent1
+	pairs := func(round int) (result []FaultPair) {
+		for i := 0; i < 400; i++ {
+			fault, _ := BitFault(round, BeforeSBox, r.Intn(128))
+			b, _ := NewFaultyCipher(Serpent1, key, fault)
+			plain := randomBytes(r, 16)
+			c, f := make([]byte, BlockSize), make([]byte, BlockSize)
+			correct.Encrypt(c, plain)
+			b.Encrypt(f, plain)
+			result = append(result, FaultPair{BitstringFromBytes(c),
+				BitstringFromBytes(f)})
+		}
+		return
+	}
+	d.Last = pairs(31)
+	d.Penultimate = pairs(30)
+	long, lengths, err := d.Key()
+	if err != nil {
+		t.Fatalf("DFA failed: %v\n", err)
+	}
+	if long != makeLongkey(BitstringFromBytes(key)) {
+		t.Errorf("DFA recovered the wrong key\n")
+	}
+	if len(lengths) != 2 || lengths[0] != 128 {
+		t.Errorf("candidate lengths %v, want [128 256]\n", lengths)
+	}
+
+	d.Last = d.Last[:1]
+	if _, err := d.LastSubkey(); err == nil {
+		t.Errorf("a single pair determined the last subkey\n")
+	}
+}
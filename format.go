@@ -0,0 +1,190 @@
+package serpent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Function ParseBitstring parses a Bitstring written as its characters,
+// bit 0 first as in a Bitstring literal, or with a "0b" prefix as a binary
+// number, most significant bit first, as %#b writes it. White space and
+// underscores used for grouping are ignored; any character other than '0'
+// and '1' is an error.
+func ParseBitstring(s string) (Bitstring, error) {
+	digits, err := parseDigits(s, "0b", func(c byte) bool {
+		return c == '0' || c == '1'
+	})
+	if err != nil {
+		return "", err
+	}
+	b := Bitstring(digits)
+	t := strings.TrimSpace(s)
+	if len(t) >= 2 && strings.EqualFold(t[:2], "0b") {
+		b = Bitstring(b.binary())
+	}
+	return b, nil
+}
+
+// Function ParseHexstring parses a Hexstring, most significant digit first.
+// An optional "0x" prefix, white space and underscores used for grouping
+// are ignored, and upper case digits are accepted; the result is in lower
+// case, as ToHexstring returns it.
+func ParseHexstring(s string) (Hexstring, error) {
+	digits, err := parseDigits(strings.ToLower(s), "0x", func(c byte) bool {
+		return '0' <= c && c <= '9' || 'a' <= c && c <= 'f'
+	})
+	if err != nil {
+		return "", err
+	}
+	return Hexstring(digits), nil
+}
+
+// Function parseDigits strips 'prefix', white space and underscores from
+// 's' and checks that the remaining characters satisfy 'valid'.
+func parseDigits(s string, prefix string,
+	valid func(c byte) bool) (string, error) {
+	t := strings.TrimSpace(s)
+	if len(t) >= len(prefix) && strings.EqualFold(t[:len(prefix)], prefix) {
+		t = t[len(prefix):]
+	}
+	digits := make([]byte, 0, len(t))
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		switch {
+		case c == '_' || c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case valid(c):
+			digits = append(digits, c)
+		default:
+			return "", fmt.Errorf("serpent: invalid character %q in %q",
+				c, s)
+		}
+	}
+	if len(digits) == 0 {
+		return "", fmt.Errorf("serpent: no digits in %q", s)
+	}
+	return string(digits), nil
+}
+
+// Method Format implements fmt.Formatter. The verbs are
+//
+//	%v, %s  the Bitstring as it is stored, bit 0 first
+//	%q      the same, quoted
+//	%b      the Bitstring as a binary number, most significant bit first
+//	%x, %X  the Bitstring as a hexadecimal number, as ToHexstring returns
+//	        it; a length that is not a multiple of 4 is padded with zeros
+//
+// For %v, %b, %x and %X a precision groups the digits, 'n' at a time from
+// the start, separated by underscores, and the '#' flag adds a "0b" or "0x"
+// prefix to %b, %x and %X. The result is accepted by ParseBitstring and
+// ParseHexstring. Width and the '-' flag pad as for strings.
+func (s Bitstring) Format(f fmt.State, verb rune) {
+	var out, prefix string
+	switch verb {
+	case 'v', 's':
+		out = string(s)
+	case 'q':
+		out = strconv.Quote(string(s))
+	case 'b', 'x', 'X':
+		if err := checkBits(s); err != nil {
+			fmt.Fprintf(f, "%%!%c(serpent.Bitstring=%s)", verb, string(s))
+			return
+		}
+		if verb == 'b' {
+			out, prefix = s.binary(), "0b"
+		} else {
+			out, prefix = s.hexadecimal(), "0x"
+		}
+		if verb == 'X' {
+			out, prefix = strings.ToUpper(out), "0X"
+		}
+	default:
+		fmt.Fprintf(f, "%%!%c(serpent.Bitstring=%s)", verb, string(s))
+		return
+	}
+	if n, ok := f.Precision(); ok && n > 0 && verb != 's' && verb != 'q' {
+		out = group(out, n)
+	}
+	if f.Flag('#') && prefix != "" {
+		out = prefix + out
+	}
+	if width, ok := f.Width(); ok && len(out) < width {
+		padding := strings.Repeat(" ", width-len(out))
+		if f.Flag('-') {
+			out += padding
+		} else {
+			out = padding + out
+		}
+	}
+	fmt.Fprint(f, out)
+}
+
+// Method binary returns 's' reversed, most significant bit first.
+func (s Bitstring) binary() string {
+	b := make([]byte, len(s))
+	for i := range b {
+		b[i] = s[len(s)-1-i]
+	}
+	return string(b)
+}
+
+// Method hexadecimal returns 's' in hexadecimal, most significant digit
+// first, padding the last digit with zeros.
+func (s Bitstring) hexadecimal() string {
+	const digits = "0123456789abcdef"
+	h := make([]byte, (len(s)+3)/4)
+	for i := range h {
+		nibble := 0
+		for j := 0; j < 4 && 4*i+j < len(s); j++ {
+			nibble |= int(s[4*i+j]-'0') << uint(j)
+		}
+		h[len(h)-1-i] = digits[nibble]
+	}
+	return string(h)
+}
+
+// Function group inserts an underscore after every 'n' characters of 's'.
+func group(s string, n int) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i += n {
+		if i > 0 {
+			b.WriteByte('_')
+		}
+		end := i + n
+		if end > len(s) {
+			end = len(s)
+		}
+		b.WriteString(s[i:end])
+	}
+	return b.String()
+}
+
+// Method MarshalText implements encoding.TextMarshaler, writing 's' as it
+// is stored.
+func (s Bitstring) MarshalText() ([]byte, error) {
+	if err := checkBits(s); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// Method UnmarshalText implements encoding.TextUnmarshaler. It accepts
+// what ParseBitstring accepts and, with a "0x" prefix, what ParseHexstring
+// accepts, in which case each digit gives 4 bits.
+func (s *Bitstring) UnmarshalText(text []byte) error {
+	t := strings.TrimSpace(string(text))
+	if len(t) >= 2 && strings.EqualFold(t[:2], "0x") {
+		h, err := ParseHexstring(t)
+		if err != nil {
+			return err
+		}
+		*s = h.ToBitstring()
+		return nil
+	}
+	b, err := ParseBitstring(t)
+	if err != nil {
+		return err
+	}
+	*s = b
+	return nil
+}
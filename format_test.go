@@ -0,0 +1,196 @@
+package serpent
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// Function TestParseBitstring checks the accepted spellings of a Bitstring
+// and the rejection of invalid ones.
+func TestParseBitstring(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want Bitstring
+	}{
+		{"0110", "0110"},
+		{"0b0110", "0110"},
+		{"0b0001", "1000"},
+		{"0B_1100_0", "00011"},
+		{" 0110_1000\n1111 ", "011010001111"},
+	} {
+		got, err := ParseBitstring(c.in)
+		if err != nil {
+			t.Fatalf("ParseBitstring(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("ParseBitstring(%q) = %s, want %s\n", c.in, got, c.want)
+		}
+	}
+	for _, in := range []string{"", "0b", "0120", "01 x"} {
+		if _, err := ParseBitstring(in); err == nil {
+			t.Errorf("ParseBitstring(%q) succeeds\n", in)
+		}
+	}
+}
+
+// Function TestParseHexstring checks prefixes, case and grouping of
+// Hexstrings and the rejection of invalid ones.
+func TestParseHexstring(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want Hexstring
+	}{
+		{"00ff", "00ff"},
+		{"0X00FF", "00ff"},
+		{"0x0011_2233 4455", "001122334455"},
+	} {
+		got, err := ParseHexstring(c.in)
+		if err != nil {
+			t.Fatalf("ParseHexstring(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("ParseHexstring(%q) = %s, want %s\n", c.in, got, c.want)
+		}
+	}
+	for _, in := range []string{"", "0x", "0g", "12-34"} {
+		if _, err := ParseHexstring(in); err == nil {
+			t.Errorf("ParseHexstring(%q) succeeds\n", in)
+		}
+	}
+	if got := Hexstring("A").ToBitstring(); got != "0101" {
+		t.Errorf("ToBitstring of \"A\" = %s, want 0101\n", got)
+	}
+}
+
+// Function TestFormat checks the verbs and flags of Bitstring.Format and
+// that grouped output parses back.
+func TestFormat(t *testing.T) {
+	s := Bitstring("000101101")
+	for _, c := range []struct {
+		format, want string
+	}{
+		{"%v", "000101101"},
+		{"%s", "000101101"},
+		{"%q", `"000101101"`},
+		{"%.4v", "0001_0110_1"},
+		{"%b", "101101000"},
+		{"%#.4b", "0b1011_0100_0"},
+		{"%x", "168"},
+		{"%X", "168"},
+		{"%#x", "0x168"},
+		{"%12v", "   000101101"},
+		{"%-12v|", "000101101   |"},
+		{"%d", "%!d(serpent.Bitstring=000101101)"},
+	} {
+		if got := fmt.Sprintf(c.format, s); got != c.want {
+			t.Errorf("Sprintf(%q) = %q, want %q\n", c.format, got, c.want)
+		}
+	}
+	k := bs + bs
+	if got, want := fmt.Sprintf("%x", k), string(k.ToHexstring()); got != want {
+		t.Errorf("%%x gives %s, want %s\n", got, want)
+	}
+	if got := fmt.Sprintf("%#X", Bitstring("00001111")); got != "0XF0" {
+		t.Errorf("%%#X gives %s, want 0XF0\n", got)
+	}
+	if got := fmt.Sprintf("%x", Bitstring("012")); got !=
+		"%!x(serpent.Bitstring=012)" {
+		t.Errorf("%%x of an invalid Bitstring gives %s\n", got)
+	}
+
+	// Grouped output parses back.
+	for _, format := range []string{"%.8v", "%#.8x", "%#b", "%#.4b"} {
+		out := fmt.Sprintf(format, k)
+		var back Bitstring
+		if err := back.UnmarshalText([]byte(out)); err != nil {
+			t.Fatalf("UnmarshalText(%q): %v", out, err)
+		}
+		if back != k {
+			t.Errorf("%s does not parse back\n", format)
+		}
+	}
+}
+
+// Function TestHexDigit checks the conversion of a digit, its errors on
+// anything else and the panics of ToHexstring and ToBitstring on them.
+func TestHexDigit(t *testing.T) {
+	var s Bitstring
+	if h, err := hexDigit("1101"); h != "b" || err != nil {
+		t.Errorf("hexDigit(1101) = %q, %v\n", h, err)
+	}
+	if b, err := hexDigitBits("B"); b != "1101" || err != nil {
+		t.Errorf("hexDigitBits(B) = %q, %v\n", b, err)
+	}
+	if Bitstring("1101").ToHex() != "b" || s.FromHex("B") != "1101" {
+		t.Errorf("ToHex or FromHex differs\n")
+	}
+	for _, in := range []Bitstring{"", "110", "11010", "1121"} {
+		if _, err := hexDigit(in); err == nil {
+			t.Errorf("hexDigit(%q) succeeds\n", in)
+		}
+	}
+	for _, in := range []Hexstring{"", "ab", "g"} {
+		if _, err := hexDigitBits(in); err == nil {
+			t.Errorf("hexDigitBits(%q) succeeds\n", in)
+		}
+	}
+	for _, in := range []Bitstring{"110", "1121"} {
+		if !panics(func() { in.ToHexstring() }) {
+			t.Errorf("ToHexstring(%q) does not panic\n", in)
+		}
+	}
+	if !panics(func() { Hexstring("1g").ToBitstring() }) {
+		t.Errorf("ToBitstring(1g) does not panic\n")
+	}
+}
+
+// Function TestText checks that Bitstrings round trip through JSON.
+func TestText(t *testing.T) {
+	type config struct {
+		Key Bitstring
+	}
+	in := config{Key: "0110"}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"Key":"0110"}` {
+		t.Errorf("Marshal gives %s\n", data)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out != in {
+		t.Errorf("Unmarshal gives %v, want %v\n", out, in)
+	}
+	if err := json.Unmarshal([]byte(`{"Key":"0xA1"}`), &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out.Key != "10000101" {
+		t.Errorf("Unmarshal of 0xA1 gives %s, want 10000101\n", out.Key)
+	}
+	if err := json.Unmarshal([]byte(`{"Key":"0102"}`), &out); err == nil {
+		t.Errorf("Unmarshal accepts an invalid Bitstring\n")
+	}
+	if _, err := json.Marshal(config{Key: "012"}); err == nil {
+		t.Errorf("Marshal accepts an invalid Bitstring\n")
+	}
+}
+
+// This example parses a grouped hexadecimal value and prints it as bits,
+// least significant first, and back in hexadecimal.
+func ExampleParseHexstring() {
+	h, err := ParseHexstring("0x80_01")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	s := h.ToBitstring()
+	fmt.Printf("%.4v\n", s)
+	fmt.Printf("%#x\n", s)
+	// Output:
+	// 1000_0000_0000_0001
+	// 0x8001
+}
@@ -0,0 +1,307 @@
+package serpent
+
+// Bits lists the methods shared by the two representations of bit
+// sequences: Bitstring, whose lists are Bitslices, and BitVector, whose
+// lists are BitVectors. The round functions below are written once
+// against it and run on either.
+type Bits[T any, S ~[]T] interface {
+	Len() int
+	Bit(i int) int
+	Int() int
+	FromInt(n int, l int) T
+	Xor(args S) T
+	RotateLeft(places int) T
+	RotateRight(places int) T
+	ShiftLeft(places int) T
+	ShiftRight(places int) T
+	QuadSplit() S
+	QuadJoin(bs S) T
+	ToHexstring() Hexstring
+}
+
+// Method Len returns the number of bits of 's'.
+func (s Bitstring) Len() int {
+	return len(s)
+}
+
+// Method Bit returns bit 'i' of 's' as 0 or 1.
+func (s Bitstring) Bit(i int) int {
+	return int(s[i] - '0')
+}
+
+// Method Int returns the value of 's', which must have at most 63 bits.
+// It is the inverse of FromInt.
+func (s Bitstring) Int() int {
+	n := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		n = n<<1 | int(s[i]-'0')
+	}
+	return n
+}
+
+// Function wordsOf returns the 4 32-bit words of the 128-bit 'x'.
+func wordsOf[T Bits[T, S], S ~[]T](x T) (w [4]uint32) {
+	for k, word := range x.QuadSplit() {
+		w[k] = uint32(word.Int())
+	}
+	return
+}
+
+// Function fromWordsOf returns the 128 bits of the 4 words 'w' in the
+// representation of 'like'.
+func fromWordsOf[T Bits[T, S], S ~[]T](like T, w [4]uint32) T {
+	words := make(S, 4)
+	for k := range words {
+		words[k] = like.FromInt(int(w[k]), 32)
+	}
+	return like.QuadJoin(words)
+}
+
+// Function IPOf is IP on either representation.
+func IPOf[T Bits[T, S], S ~[]T](input T) T {
+	return permuteOf(IPTable, input)
+}
+
+// Function FPOf is FP on either representation.
+func FPOf[T Bits[T, S], S ~[]T](input T) T {
+	return permuteOf(FPTable, input)
+}
+
+// Function permuteOf moves bit p[i] of the 128-bit 'input' to position i.
+func permuteOf[T Bits[T, S], S ~[]T](p Permutation, input T) T {
+	var w [4]uint32
+	for i, j := range p {
+		w[i/32] |= uint32(input.Bit(j)) << uint(i%32)
+	}
+	return fromWordsOf(input, w)
+}
+
+// Function LTOf is LT on either representation.
+func LTOf[T Bits[T, S], S ~[]T](input T) T {
+	return applyLTTableOf(LTTable, input)
+}
+
+// Function LTInverseOf is LTInverse on either representation.
+func LTInverseOf[T Bits[T, S], S ~[]T](output T) T {
+	return applyLTTableOf(LTTableInverse, output)
+}
+
+// Function applyLTTableOf is applyLTTable on either representation.
+func applyLTTableOf[T Bits[T, S], S ~[]T](table []Ttable, input T) T {
+	var w [4]uint32
+	for i, positions := range table {
+		bit := 0
+		for _, j := range positions {
+			bit ^= input.Bit(j)
+		}
+		w[i/32] |= uint32(bit) << uint(i%32)
+	}
+	return fromWordsOf(input, w)
+}
+
+// Function SHatOf applies 'sbox' to each of the 32 nibbles of the 128-bit
+// 'input', as SHat does, on either representation.
+func SHatOf[T Bits[T, S], S ~[]T](sbox SBox, input T) T {
+	x := wordsOf(input)
+	var y [4]uint32
+	for n := uint(0); n < 32; n++ {
+		k, shift := n/8, 4*(n%8)
+		y[k] |= uint32(sbox[x[k]>>shift&0xf]) << shift
+	}
+	return fromWordsOf(input, y)
+}
+
+// Function SBitsliceOf is SBitslice on either representation.
+func SBitsliceOf[T Bits[T, S], S ~[]T](box int, words S) S {
+	return applyBitsliceOf(serpent1.SBoxes[serpent1.box(box)], words)
+}
+
+// Function SBitsliceInverseOf is SBitsliceInverse on either
+// representation.
+func SBitsliceInverseOf[T Bits[T, S], S ~[]T](box int, words S) S {
+	return applyBitsliceOf(serpent1.SBoxes[serpent1.box(box)].Inverse(),
+		words)
+}
+
+// Function applyBitsliceOf applies 'sbox' to the 4 bits found at each of
+// the 32 positions of 'words'. It works on whole words: for each input
+// value it selects the positions holding that value and sets the output
+// bits of the value there.
+func applyBitsliceOf[T Bits[T, S], S ~[]T](sbox SBox, words S) S {
+	var x [4]uint32
+	for l := range x {
+		x[l] = uint32(words[l].Int())
+	}
+	var out [4]uint32
+	for v, y := range sbox {
+		m := ^uint32(0)
+		for l := uint(0); l < 4; l++ {
+			if v>>l&1 == 1 {
+				m &= x[l]
+			} else {
+				m &^= x[l]
+			}
+		}
+		for l := uint(0); l < 4; l++ {
+			if y>>l&1 == 1 {
+				out[l] |= m
+			}
+		}
+	}
+	result := make(S, 4)
+	for l := range result {
+		result[l] = words[l].FromInt(int(out[l]), 32)
+	}
+	return result
+}
+
+// Function LTBitsliceOf is LTBitslice on either representation. Like
+// LTBitslice it works in place on 'x'.
+func LTBitsliceOf[T Bits[T, S], S ~[]T](x S) S {
+	x[0] = x[0].RotateLeft(13)
+	x[2] = x[2].RotateLeft(3)
+	x[1] = x[1].Xor(S{x[1], x[0], x[2]})
+	x[3] = x[3].Xor(S{x[3], x[2], x[0].ShiftLeft(3)})
+	x[1] = x[1].RotateLeft(1)
+	x[3] = x[3].RotateLeft(7)
+	x[0] = x[0].Xor(S{x[0], x[1], x[3]})
+	x[2] = x[2].Xor(S{x[2], x[3], x[1].ShiftLeft(7)})
+	x[0] = x[0].RotateLeft(5)
+	x[2] = x[2].RotateLeft(22)
+
+	return x
+}
+
+// Function LTBitsliceInverseOf is LTBitsliceInverse on either
+// representation.
+func LTBitsliceInverseOf[T Bits[T, S], S ~[]T](x S) S {
+	x[2] = x[2].RotateRight(22)
+	x[0] = x[0].RotateRight(5)
+	x[2] = x[2].Xor(S{x[2], x[3], x[1].ShiftLeft(7)})
+	x[0] = x[0].Xor(S{x[0], x[1], x[3]})
+	x[3] = x[3].RotateRight(7)
+	x[1] = x[1].RotateRight(1)
+	x[3] = x[3].Xor(S{x[3], x[2], x[0].ShiftLeft(3)})
+	x[1] = x[1].Xor(S{x[1], x[0], x[2]})
+	x[2] = x[2].RotateRight(3)
+	x[0] = x[0].RotateRight(13)
+
+	return x
+}
+
+// Function RBitsliceOf is RBitslice on either representation.
+func RBitsliceOf[T Bits[T, S], S ~[]T](i int, Bi T, K S) T {
+	last := serpent1.lastRound()
+
+	// 1. Key mixing
+	xored := Bi.Xor(S{Bi, K[i]})
+
+	// 2. S Boxes
+	Si := SBitsliceOf(i, xored.QuadSplit())
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		return Bi.Xor(S{Bi.QuadJoin(Si), K[last+1]})
+	}
+	return Bi.QuadJoin(LTBitsliceOf(Si))
+}
+
+// Function RBitsliceInverseOf is RBitsliceInverse on either
+// representation.
+func RBitsliceInverseOf[T Bits[T, S], S ~[]T](i int, BiPlus1 T, K S) T {
+	last := serpent1.lastRound()
+	var Si S
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		Si = BiPlus1.Xor(S{BiPlus1, K[last+1]}).QuadSplit()
+	} else {
+		Si = LTBitsliceInverseOf(BiPlus1.QuadSplit())
+	}
+
+	// 2. S Boxes
+	xored := SBitsliceInverseOf(i, Si)
+
+	// 1. Key mixing
+	return BiPlus1.Xor(S{BiPlus1.QuadJoin(xored), K[i]})
+}
+
+// Function ROf is R on either representation.
+func ROf[T Bits[T, S], S ~[]T](i int, BHati T, KHat S) T {
+	last := serpent1.lastRound()
+
+	// 1. Key mixing
+	xored := BHati.Xor(S{BHati, KHat[i]})
+
+	// 2. S Boxes
+	SHati := SHatOf(serpent1.SBoxes[serpent1.box(i)], xored)
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		return BHati.Xor(S{SHati, KHat[last+1]})
+	}
+	return LTOf(SHati)
+}
+
+// Function RInverseOf is RInverse on either representation.
+func RInverseOf[T Bits[T, S], S ~[]T](i int, BHatiPlus1 T, KHat S) T {
+	last := serpent1.lastRound()
+	var SHati T
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		SHati = BHatiPlus1.Xor(S{BHatiPlus1, KHat[last+1]})
+	} else {
+		SHati = LTInverseOf(BHatiPlus1)
+	}
+
+	// 2. S Boxes
+	xored := SHatOf(serpent1.SBoxes[serpent1.box(i)].Inverse(), SHati)
+
+	// 1. Key mixing
+	return BHatiPlus1.Xor(S{xored, KHat[i]})
+}
+
+// Function EncryptOf encrypts 'plainText' with the 33 subkeys 'KHat' by
+// the normal algorithm on either representation.
+func EncryptOf[T Bits[T, S], S ~[]T](plainText T, KHat S) T {
+	BHat := IPOf(plainText)
+	for i := 0; i < round; i++ {
+		BHat = ROf(i, BHat, KHat)
+	}
+	return FPOf(BHat)
+}
+
+// Function DecryptOf decrypts 'cipherText' with the 33 subkeys 'KHat' by
+// the normal algorithm on either representation.
+func DecryptOf[T Bits[T, S], S ~[]T](cipherText T, KHat S) T {
+	BHat := IPOf(cipherText)
+	for i := round - 1; i >= 0; i-- {
+		BHat = RInverseOf(i, BHat, KHat)
+	}
+	return FPOf(BHat)
+}
+
+// Function EncryptBitsliceOf encrypts 'plainText' with the 33 subkeys 'K'
+// by the bitslice algorithm on either representation.
+func EncryptBitsliceOf[T Bits[T, S], S ~[]T](plainText T, K S) T {
+	B := plainText
+	for i := 0; i < round; i++ {
+		B = RBitsliceOf(i, B, K)
+	}
+	return B
+}
+
+// Function DecryptBitsliceOf decrypts 'cipherText' with the 33 subkeys
+// 'K' by the bitslice algorithm on either representation.
+func DecryptBitsliceOf[T Bits[T, S], S ~[]T](cipherText T, K S) T {
+	B := cipherText
+	for i := round - 1; i >= 0; i-- {
+		B = RBitsliceInverseOf(i, B, K)
+	}
+	return B
+}
@@ -0,0 +1,227 @@
+// Package gf2 implements matrices over GF(2), the field of two elements,
+// with rows packed into 64-bit words.
+package gf2
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrSingular is returned when inverting a matrix that has no inverse.
+var ErrSingular = errors.New("gf2: matrix is singular")
+
+// A Vector holds bits packed into 64-bit words, bit i being bit i%64 of
+// word i/64.
+type Vector []uint64
+
+// Function NewVector returns a zero vector of 'n' bits.
+func NewVector(n int) Vector {
+	return make(Vector, (n+63)/64)
+}
+
+// Method Bit returns bit 'i' of the vector.
+func (v Vector) Bit(i int) int {
+	return int(v[i/64]>>uint(i%64)) & 1
+}
+
+// Method Set sets bit 'i' of the vector to the low bit of 'b'.
+func (v Vector) Set(i, b int) {
+	mask := uint64(1) << uint(i%64)
+	if b&1 == 1 {
+		v[i/64] |= mask
+	} else {
+		v[i/64] &^= mask
+	}
+}
+
+// Method Weight returns the number of bits set.
+func (v Vector) Weight() (n int) {
+	for _, w := range v {
+		n += bits.OnesCount64(w)
+	}
+	return
+}
+
+// Method Ones returns the positions of the bits set, in increasing order.
+func (v Vector) Ones() []int {
+	result := []int{}
+	for k, w := range v {
+		for ; w != 0; w &= w - 1 {
+			result = append(result, 64*k+bits.TrailingZeros64(w))
+		}
+	}
+	return result
+}
+
+// Method xor adds 'u' to the vector.
+func (v Vector) xor(u Vector) {
+	for k := range v {
+		v[k] ^= u[k]
+	}
+}
+
+// A Matrix is a rows x cols matrix over GF(2). Row i is a Vector of cols
+// bits, entry [i][j] being its bit j.
+type Matrix struct {
+	rows, cols int
+	data       []Vector
+}
+
+// Function New returns the zero matrix with 'rows' rows and 'cols'
+// columns.
+func New(rows, cols int) *Matrix {
+	m := &Matrix{rows: rows, cols: cols, data: make([]Vector, rows)}
+	for i := range m.data {
+		m.data[i] = NewVector(cols)
+	}
+	return m
+}
+
+// Function Identity returns the n x n identity matrix.
+func Identity(n int) *Matrix {
+	m := New(n, n)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// Method Rows returns the number of rows.
+func (m *Matrix) Rows() int { return m.rows }
+
+// Method Cols returns the number of columns.
+func (m *Matrix) Cols() int { return m.cols }
+
+// Method Bit returns entry [i][j].
+func (m *Matrix) Bit(i, j int) int {
+	return m.data[i].Bit(j)
+}
+
+// Method Set sets entry [i][j] to the low bit of 'b'.
+func (m *Matrix) Set(i, j, b int) {
+	m.data[i].Set(j, b)
+}
+
+// Method Row returns a copy of row 'i'.
+func (m *Matrix) Row(i int) Vector {
+	return append(Vector(nil), m.data[i]...)
+}
+
+// Method Clone returns a copy of the matrix.
+func (m *Matrix) Clone() *Matrix {
+	c := &Matrix{rows: m.rows, cols: m.cols, data: make([]Vector, m.rows)}
+	for i := range m.data {
+		c.data[i] = m.Row(i)
+	}
+	return c
+}
+
+// Method Equal reports whether 'm' and 'o' have the same dimensions and
+// entries.
+func (m *Matrix) Equal(o *Matrix) bool {
+	if m.rows != o.rows || m.cols != o.cols {
+		return false
+	}
+	for i := range m.data {
+		for k := range m.data[i] {
+			if m.data[i][k] != o.data[i][k] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Method Transpose returns the transposed matrix.
+func (m *Matrix) Transpose() *Matrix {
+	t := New(m.cols, m.rows)
+	for i, row := range m.data {
+		for _, j := range row.Ones() {
+			t.Set(j, i, 1)
+		}
+	}
+	return t
+}
+
+// Method Mul returns the product m.o. It panics if the number of columns
+// of 'm' differs from the number of rows of 'o'.
+func (m *Matrix) Mul(o *Matrix) *Matrix {
+	if m.cols != o.rows {
+		panic("gf2: dimension mismatch")
+	}
+	p := New(m.rows, o.cols)
+	for i, row := range m.data {
+		for _, k := range row.Ones() {
+			p.data[i].xor(o.data[k])
+		}
+	}
+	return p
+}
+
+// Method MulVector returns the product m.x of the matrix and the column
+// vector 'x' of cols bits.
+func (m *Matrix) MulVector(x Vector) Vector {
+	if len(x) != (m.cols+63)/64 {
+		panic("gf2: dimension mismatch")
+	}
+	y := NewVector(m.rows)
+	for i, row := range m.data {
+		var acc uint64
+		for k := range row {
+			acc ^= row[k] & x[k]
+		}
+		y.Set(i, bits.OnesCount64(acc))
+	}
+	return y
+}
+
+// Method Rank returns the rank of the matrix.
+func (m *Matrix) Rank() int {
+	return m.Clone().eliminate(nil)
+}
+
+// Method Inverse returns the inverse of the square matrix, or ErrSingular.
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if m.rows != m.cols {
+		return nil, ErrSingular
+	}
+	inverse := Identity(m.rows)
+	if m.Clone().eliminate(inverse) != m.rows {
+		return nil, ErrSingular
+	}
+	return inverse, nil
+}
+
+// Method eliminate reduces the matrix to reduced row echelon form by
+// Gauss-Jordan elimination, applying the same row operations to 'shadow'
+// when it is not nil, and returns the rank.
+func (m *Matrix) eliminate(shadow *Matrix) int {
+	rank := 0
+	for j := 0; j < m.cols && rank < m.rows; j++ {
+		pivot := -1
+		for i := rank; i < m.rows; i++ {
+			if m.Bit(i, j) == 1 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		m.data[rank], m.data[pivot] = m.data[pivot], m.data[rank]
+		if shadow != nil {
+			shadow.data[rank], shadow.data[pivot] =
+				shadow.data[pivot], shadow.data[rank]
+		}
+		for i := 0; i < m.rows; i++ {
+			if i != rank && m.Bit(i, j) == 1 {
+				m.data[i].xor(m.data[rank])
+				if shadow != nil {
+					shadow.data[i].xor(shadow.data[rank])
+				}
+			}
+		}
+		rank++
+	}
+	return rank
+}
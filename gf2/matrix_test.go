@@ -0,0 +1,84 @@
+package gf2
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function randomMatrix returns a matrix with uniformly random entries.
+func randomMatrix(r *rand.Rand, rows, cols int) *Matrix {
+	m := New(rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			m.Set(i, j, r.Intn(2))
+		}
+	}
+	return m
+}
+
+// Function TestInverse checks that invertible matrices multiply with
+// their inverse to the identity.
+func TestInverse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	found := 0
+	for found < 5 {
+		m := randomMatrix(r, 130, 130)
+		inverse, err := m.Inverse()
+		if m.Rank() < 130 {
+			if err != ErrSingular {
+				t.Errorf("singular matrix gave %v\n", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Inverse failed on a full rank matrix: %v\n", err)
+		}
+		if !m.Mul(inverse).Equal(Identity(130)) ||
+			!inverse.Mul(m).Equal(Identity(130)) {
+			t.Errorf("m.Inverse() is not an inverse\n")
+		}
+		found++
+	}
+}
+
+// Function TestRank checks the rank of products of known rank and of the
+// transpose.
+func TestRank(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	a := randomMatrix(r, 100, 7)
+	b := randomMatrix(r, 7, 90)
+	p := a.Mul(b)
+	if rank := p.Rank(); rank > 7 {
+		t.Errorf("product through 7 dimensions has rank %d\n", rank)
+	}
+	if p.Rank() != p.Transpose().Rank() {
+		t.Errorf("rank differs from the rank of the transpose\n")
+	}
+	if rank := New(5, 5).Rank(); rank != 0 {
+		t.Errorf("zero matrix has rank %d\n", rank)
+	}
+}
+
+// Function TestMulVector checks that multiplying by a vector agrees with
+// multiplying by a one column matrix, and the transpose identity
+// (AB)^T = B^T A^T.
+func TestMulVector(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	a := randomMatrix(r, 70, 128)
+	b := randomMatrix(r, 128, 1)
+	x := NewVector(128)
+	for i := 0; i < 128; i++ {
+		x.Set(i, b.Bit(i, 0))
+	}
+	y := a.MulVector(x)
+	p := a.Mul(b)
+	for i := 0; i < 70; i++ {
+		if y.Bit(i) != p.Bit(i, 0) {
+			t.Fatalf("MulVector differs from Mul at bit %d\n", i)
+		}
+	}
+	c := randomMatrix(r, 128, 40)
+	if !a.Mul(c).Transpose().Equal(c.Transpose().Mul(a.Transpose())) {
+		t.Errorf("(AB)^T differs from B^T A^T\n")
+	}
+}
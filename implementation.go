@@ -0,0 +1,285 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// An Implementation is a named engine that builds a cipher.Block from a
+// parameter set and a byte key, with the conventions of
+// NewCipherWithParams. All implementations of a parameter set compute the
+// same permutation; they differ in speed and in side channel behaviour.
+type Implementation struct {
+	Name        string
+	Description string
+	New         func(p Params, key []byte) (cipher.Block, error)
+}
+
+var (
+	implementationsMu sync.RWMutex
+	implementations   = map[string]Implementation{}
+)
+
+// The constructors of the built-in implementations without the self-test
+// check, through which the self-test runs its known answers.
+var uncheckedImplementations = map[string]func(Params, []byte) (
+	cipher.Block, error){
+	"bitstring":          newBitstringBlock(false, newUnchecked),
+	"bitstring-bitslice": newBitstringBlock(true, newUnchecked),
+	"word": func(p Params, key []byte) (cipher.Block, error) {
+		return newUncheckedCipher(p, key, false)
+	},
+	"masked": func(p Params, key []byte) (cipher.Block, error) {
+		b, err := newUncheckedCipher(p, key, false)
+		if err != nil {
+			return nil, err
+		}
+		return newMaskedBlock(b.(*block), nil)
+	},
+}
+
+func init() {
+	for _, impl := range []Implementation{
+		{"bitstring", "the normal algorithm on Bitstrings",
+			newBitstringBlock(false, New)},
+		{"bitstring-bitslice", "the bitslice algorithm on Bitstrings",
+			newBitstringBlock(true, New)},
+		{"word", "the bitslice algorithm on 32-bit words, as NewCipher",
+			NewCipherWithParams},
+		{"masked", "the first-order masked word core, as NewMaskedCipher",
+			func(p Params, key []byte) (cipher.Block, error) {
+				return NewMaskedCipher(p, key, nil)
+			}},
+	} {
+		if err := Register(impl); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Function Register adds 'impl' to the implementations available by name.
+// The name must be new.
+func Register(impl Implementation) error {
+	if impl.Name == "" || impl.New == nil {
+		return fmt.Errorf("serpent: an implementation needs a name and a " +
+			"constructor")
+	}
+	implementationsMu.Lock()
+	defer implementationsMu.Unlock()
+	if _, ok := implementations[impl.Name]; ok {
+		return fmt.Errorf("serpent: implementation %q is already "+
+			"registered", impl.Name)
+	}
+	implementations[impl.Name] = impl
+	return nil
+}
+
+// Function LookupImplementation returns the implementation called 'name'.
+// An implementation that failed SelfTest is refused with its
+// SelfTestError.
+func LookupImplementation(name string) (Implementation, error) {
+	implementationsMu.RLock()
+	impl, ok := implementations[name]
+	implementationsMu.RUnlock()
+	if !ok {
+		return Implementation{}, fmt.Errorf("serpent: unknown "+
+			"implementation %q", name)
+	}
+	if err := implementationSelfTest(name); err != nil {
+		return Implementation{}, err
+	}
+	return impl, nil
+}
+
+// Function Implementations returns the registered implementations sorted
+// by name.
+func Implementations() []Implementation {
+	implementationsMu.RLock()
+	defer implementationsMu.RUnlock()
+	var result []Implementation
+	for _, impl := range implementations {
+		result = append(result, impl)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Function NewImplementationCipher creates a cipher.Block for the
+// parameter set 'p' with the implementation called 'name'.
+func NewImplementationCipher(name string, p Params,
+	key []byte) (cipher.Block, error) {
+	impl, err := LookupImplementation(name)
+	if err != nil {
+		return nil, err
+	}
+	return impl.New(p, key)
+}
+
+// A bitstringBlock adapts a Cipher to the byte API.
+type bitstringBlock struct {
+	c        *Cipher
+	bitslice bool
+}
+
+// Function newBitstringBlock returns the constructor of the Bitstring
+// implementation using the bitslice or the normal algorithm, expanding
+// keys with 'newc'.
+func newBitstringBlock(bitslice bool,
+	newc func(Params, Bitstring) (*Cipher, error)) func(Params,
+	[]byte) (cipher.Block, error) {
+	return func(p Params, key []byte) (cipher.Block, error) {
+		if k := len(key); k%4 != 0 || k < 8 || k > 32 {
+			return nil, KeySizeError(k)
+		}
+		c, err := newc(p, BitstringFromBytes(key))
+		if err != nil {
+			return nil, err
+		}
+		return &bitstringBlock{c: c, bitslice: bitslice}, nil
+	}
+}
+
+func (b *bitstringBlock) BlockSize() int { return BlockSize }
+
+func (b *bitstringBlock) Encrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	s := BitstringFromBytes(src[:BlockSize])
+	if b.bitslice {
+		s = b.c.EncryptBitslice(s)
+	} else {
+		s = b.c.Encrypt(s)
+	}
+	copy(dst, s.Bytes())
+}
+
+func (b *bitstringBlock) Decrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	s := BitstringFromBytes(src[:BlockSize])
+	if b.bitslice {
+		s = b.c.DecryptBitslice(s)
+	} else {
+		s = b.c.Decrypt(s)
+	}
+	copy(dst, s.Bytes())
+}
+
+// A MismatchError reports a block on which two implementations disagree.
+type MismatchError struct {
+	// Op is "Encrypt" or "Decrypt".
+	Op     string
+	Names  [2]string
+	Input  [BlockSize]byte
+	Output [2][BlockSize]byte
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("serpent: %s of %x gives %x with %s but %x with %s",
+		e.Op, e.Input, e.Output[0], e.Names[0], e.Output[1], e.Names[1])
+}
+
+// CrossCheck is a cipher.Block that runs two implementations on every
+// block and compares their outputs, to catch regressions in an optimised
+// implementation by checking it against a reference one. The output is
+// that of the first implementation.
+//
+// EncryptChecked and DecryptChecked return a *MismatchError when the
+// outputs differ. Encrypt and Decrypt, which cannot, keep the first
+// mismatch for Err and pass every mismatch to OnMismatch when it is set.
+type CrossCheck struct {
+	OnMismatch func(*MismatchError)
+
+	blocks [2]cipher.Block
+	names  [2]string
+	mu     sync.Mutex
+	err    error
+}
+
+// Function NewCrossCheck creates a CrossCheck of the implementations
+// called 'a' and 'b' for the parameter set 'p'. It is safe for concurrent
+// use if both implementations are.
+func NewCrossCheck(p Params, key []byte, a, b string) (*CrossCheck, error) {
+	names := [2]string{a, b}
+	var blocks [2]cipher.Block
+	for i, name := range names {
+		block, err := NewImplementationCipher(name, p, key)
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = block
+	}
+	return newCrossCheck(names, blocks), nil
+}
+
+// Function newCrossCheck creates a CrossCheck of 'blocks' called 'names'.
+func newCrossCheck(names [2]string, blocks [2]cipher.Block) *CrossCheck {
+	return &CrossCheck{names: names, blocks: blocks}
+}
+
+func (c *CrossCheck) BlockSize() int { return BlockSize }
+
+func (c *CrossCheck) Encrypt(dst, src []byte) {
+	c.record(c.EncryptChecked(dst, src))
+}
+
+func (c *CrossCheck) Decrypt(dst, src []byte) {
+	c.record(c.DecryptChecked(dst, src))
+}
+
+// Method EncryptChecked encrypts 'src' into 'dst' with both
+// implementations.
+func (c *CrossCheck) EncryptChecked(dst, src []byte) error {
+	return c.run("Encrypt", dst, src)
+}
+
+// Method DecryptChecked decrypts 'src' into 'dst' with both
+// implementations.
+func (c *CrossCheck) DecryptChecked(dst, src []byte) error {
+	return c.run("Decrypt", dst, src)
+}
+
+// Method Err returns the first mismatch found by Encrypt or Decrypt, or
+// nil.
+func (c *CrossCheck) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Method run applies the operation 'op' of both implementations to 'src'.
+func (c *CrossCheck) run(op string, dst, src []byte) error {
+	checkBlock(dst, src)
+	e := &MismatchError{Op: op, Names: c.names}
+	copy(e.Input[:], src)
+	for i, b := range c.blocks {
+		if op == "Encrypt" {
+			b.Encrypt(e.Output[i][:], e.Input[:])
+		} else {
+			b.Decrypt(e.Output[i][:], e.Input[:])
+		}
+	}
+	copy(dst, e.Output[0][:])
+	if e.Output[0] != e.Output[1] {
+		return e
+	}
+	return nil
+}
+
+// Method record keeps the first mismatch and reports every one.
+func (c *CrossCheck) record(err error) {
+	if err == nil {
+		return
+	}
+	e := err.(*MismatchError)
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = e
+	}
+	c.mu.Unlock()
+	if c.OnMismatch != nil {
+		c.OnMismatch(e)
+	}
+}
@@ -0,0 +1,115 @@
+package serpent
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+)
+
+// Function TestImplementations checks every registered implementation
+// against the known answers.
+func TestImplementations(t *testing.T) {
+	impls := Implementations()
+	if len(impls) < 4 {
+		t.Fatalf("%d implementations registered\n", len(impls))
+	}
+	for _, impl := range impls {
+		for i, test := range blockTests {
+			key, _ := hex.DecodeString(test.key)
+			plain, _ := hex.DecodeString(test.plain)
+			want, _ := hex.DecodeString(test.cipher)
+			b, err := NewImplementationCipher(impl.Name, Serpent1, key)
+			if err != nil {
+				t.Fatalf("%s: %v", impl.Name, err)
+			}
+			got := make([]byte, BlockSize)
+			b.Encrypt(got, plain)
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s test %d: Encrypt = %x, want %x\n", impl.Name,
+					i, got, want)
+			}
+			b.Decrypt(got, got)
+			if !bytes.Equal(got, plain) {
+				t.Errorf("%s test %d: Decrypt = %x, want %x\n", impl.Name,
+					i, got, plain)
+			}
+		}
+		if _, err := impl.New(Serpent1, make([]byte, 17)); err !=
+			KeySizeError(17) {
+			t.Errorf("%s: 17 byte key gave %v\n", impl.Name, err)
+		}
+	}
+	if _, err := NewImplementationCipher("none", Serpent1,
+		make([]byte, 16)); err == nil {
+		t.Errorf("unknown implementation accepted\n")
+	}
+	if err := Register(Implementation{Name: "word",
+		New: NewCipherWithParams}); err == nil {
+		t.Errorf("duplicate name accepted\n")
+	}
+}
+
+// A brokenBlock flips a bit of the ciphertext of blocks starting with
+// 0xff.
+type brokenBlock struct {
+	cipher.Block
+}
+
+func (b brokenBlock) Encrypt(dst, src []byte) {
+	broken := src[0] == 0xff
+	b.Block.Encrypt(dst, src)
+	if broken {
+		dst[0] ^= 1
+	}
+}
+
+// Function TestCrossCheck checks that a cross-check passes on agreeing
+// implementations and reports a mismatch in each of its ways.
+func TestCrossCheck(t *testing.T) {
+	key := make([]byte, 32)
+	want, _ := NewCipher(key)
+	reference, err := NewImplementationCipher("bitstring", Serpent1, key)
+	if err != nil {
+		t.Fatalf("NewImplementationCipher failed: %v", err)
+	}
+	c := newCrossCheck([2]string{"broken", "bitstring"},
+		[2]cipher.Block{brokenBlock{want}, reference})
+	var reported []*MismatchError
+	c.OnMismatch = func(e *MismatchError) { reported = append(reported, e) }
+
+	block := make([]byte, BlockSize)
+	wantCT := make([]byte, BlockSize)
+	want.Encrypt(wantCT, block)
+	if err := c.EncryptChecked(block, block); err != nil {
+		t.Errorf("EncryptChecked: %v", err)
+	}
+	if !bytes.Equal(block, wantCT) {
+		t.Errorf("EncryptChecked = %x, want %x\n", block, wantCT)
+	}
+	c.Decrypt(block, block)
+	if c.Err() != nil || len(reported) != 0 {
+		t.Errorf("agreeing implementations report %v\n", c.Err())
+	}
+
+	block[0] = 0xff
+	err = c.EncryptChecked(block, block)
+	e, ok := err.(*MismatchError)
+	if !ok || e.Op != "Encrypt" || e.Names != [2]string{"broken",
+		"bitstring"} || e.Output[0][0]^e.Output[1][0] != 1 {
+		t.Errorf("EncryptChecked gives %v\n", err)
+	}
+	if c.Err() != nil {
+		t.Errorf("EncryptChecked records its mismatch\n")
+	}
+	block[0] = 0xff
+	c.Encrypt(block, block)
+	block[0] = 0xff
+	c.Encrypt(block, block)
+	if len(reported) != 2 {
+		t.Errorf("%d mismatches reported, want 2\n", len(reported))
+	}
+	if c.Err() != error(reported[0]) {
+		t.Errorf("Err gives %v, want the first mismatch\n", c.Err())
+	}
+}
@@ -0,0 +1,322 @@
+// Command gen writes the unrolled rounds of the word core.
+//
+// Usage:
+//
+//	go run -tags nounrolled ./internal/gen -o rounds_words.go
+//
+// The output holds encryptWordsUnrolled and decryptWordsUnrolled, the 32
+// rounds of Serpent-1 written out one after the other with the key mixing,
+// the S-Box circuit of each round and the linear transformation inlined.
+// It is derived from the package itself: the circuits are those of
+// serpent.WordSBoxCircuits, checked against SBoxDecimalTable, and the
+// linear transformation is traced from LTBitsliceOf and
+// LTBitsliceInverseOf, checked against LTBitslice and LTBitsliceInverse.
+// A change that the tracing cannot follow stops the generator instead of
+// producing a wrong file. The key schedule is left to the package.
+//
+// The output is excluded by the nounrolled tag, with which the package
+// takes rounds_words_stub.go instead and runs its rounds in a loop. The
+// generator is built with that tag, so it builds even when rounds_words.go
+// is missing or does not compile.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"math/bits"
+	"math/rand"
+	"os"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// rounds is the number of rounds of Serpent-1.
+const rounds = 32
+
+func main() {
+	out := flag.String("o", "rounds_words.go", "file to write")
+	flag.Parse()
+	src, err := generate(*out)
+	if err == nil {
+		err = ioutil.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// An ltStep is one step of the linear transformation on the words x0 to
+// x3: a rotation of x[dst] by 'rotate' places when 'xor' is false,
+// otherwise x[dst] ^= x[a] ^ x[b]<<shift.
+type ltStep struct {
+	dst    int
+	xor    bool
+	rotate int
+	a, b   int
+	shift  uint
+}
+
+// A symWord stands for word 'word' of the block shifted left by 'shift'
+// places while tracing the generic linear transformation. Operations are
+// recorded in 'program' of 't', assuming, as LTBitsliceOf does, that every
+// rotation and xor stores its result back in the word it was called on.
+type symWord struct {
+	t     *tracer
+	word  int
+	shift uint
+}
+
+type symWords []symWord
+
+// A tracer records the steps of a linear transformation.
+type tracer struct {
+	program []ltStep
+	err     error
+}
+
+// Method unsupported records the use of the operation 'op', which has no
+// ltStep.
+func (t *tracer) unsupported(op string) {
+	if t.err == nil {
+		t.err = fmt.Errorf("the linear transformation uses %s", op)
+	}
+}
+
+func (s symWord) Len() int { return 32 }
+
+func (s symWord) Bit(i int) int {
+	s.t.unsupported("Bit")
+	return 0
+}
+
+func (s symWord) Int() int {
+	s.t.unsupported("Int")
+	return 0
+}
+
+func (s symWord) FromInt(n int, l int) symWord {
+	s.t.unsupported("FromInt")
+	return s
+}
+
+func (s symWord) Xor(args symWords) symWord {
+	if len(args) != 3 || args[0].word != s.word || args[0].shift != 0 ||
+		args[1].shift != 0 {
+		s.t.unsupported("an xor other than x ^= a ^ b<<n")
+		return s
+	}
+	s.t.program = append(s.t.program, ltStep{dst: s.word, xor: true,
+		a: args[1].word, b: args[2].word, shift: args[2].shift})
+	return s
+}
+
+func (s symWord) RotateLeft(places int) symWord {
+	if s.shift != 0 {
+		s.t.unsupported("a rotation of a shifted word")
+	}
+	s.t.program = append(s.t.program, ltStep{dst: s.word, rotate: places})
+	return s
+}
+
+func (s symWord) RotateRight(places int) symWord {
+	return s.RotateLeft(-places)
+}
+
+func (s symWord) ShiftLeft(places int) symWord {
+	s.shift += uint(places)
+	return s
+}
+
+func (s symWord) ShiftRight(places int) symWord {
+	s.t.unsupported("ShiftRight")
+	return s
+}
+
+func (s symWord) QuadSplit() symWords {
+	s.t.unsupported("QuadSplit")
+	return nil
+}
+
+func (s symWord) QuadJoin(bs symWords) symWord {
+	s.t.unsupported("QuadJoin")
+	return s
+}
+
+func (s symWord) ToHexstring() serpent.Hexstring {
+	s.t.unsupported("ToHexstring")
+	return ""
+}
+
+// Function traceProgram returns the steps of the generic linear
+// transformation 'lt', found by running it on symbolic words.
+func traceProgram(lt func(symWords) symWords) ([]ltStep, error) {
+	t := &tracer{}
+	x := make(symWords, 4)
+	for k := range x {
+		x[k] = symWord{t: t, word: k}
+	}
+	lt(x)
+	return t.program, t.err
+}
+
+// Function run applies 'program' to 'x'.
+func run(program []ltStep, x [4]uint32) [4]uint32 {
+	for _, s := range program {
+		if s.xor {
+			x[s.dst] ^= x[s.a] ^ x[s.b]<<s.shift
+		} else {
+			x[s.dst] = bits.RotateLeft32(x[s.dst], s.rotate)
+		}
+	}
+	return x
+}
+
+// Function checkProgram compares 'program' with the Bitslice function
+// 'lt' on random blocks.
+func checkProgram(name string, program []ltStep,
+	lt func(serpent.Bitslice) serpent.Bitslice) error {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 100; n++ {
+		var x [4]uint32
+		in := make(serpent.Bitslice, 4)
+		for k := range x {
+			x[k] = r.Uint32()
+			in[k] = serpent.NewUint32le(x[k]).Bitstring()
+		}
+		got := run(program, x)
+		want := lt(in)
+		for k := range got {
+			if got[k] != binary.LittleEndian.Uint32(want[k].Bytes()) {
+				return fmt.Errorf("the program of %s differs from the "+
+					"package", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Function generate returns the formatted source of the file 'name'.
+func generate(name string) ([]byte, error) {
+	circuits, inverses := serpent.WordSBoxCircuits()
+	for i, sbox := range serpent.SBoxDecimalTable {
+		if err := circuits[i].Verify(sbox); err != nil {
+			return nil, fmt.Errorf("S%d: %v", i, err)
+		}
+		if err := inverses[i].Verify(sbox.Inverse()); err != nil {
+			return nil, fmt.Errorf("inverse of S%d: %v", i, err)
+		}
+	}
+	lt, err := traceProgram(serpent.LTBitsliceOf[symWord, symWords])
+	if err == nil {
+		err = checkProgram("LT", lt, serpent.LTBitslice)
+	}
+	if err != nil {
+		return nil, err
+	}
+	inverseLT, err := traceProgram(
+		serpent.LTBitsliceInverseOf[symWord, symWords])
+	if err == nil {
+		err = checkProgram("LTInverse", inverseLT, serpent.LTBitsliceInverse)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "//go:build !nounrolled\n\n"+
+		"// Code generated by \"go run -tags nounrolled ./internal/gen "+
+		"-o %s\"; DO NOT EDIT.\n\npackage serpent\n\n"+
+		"import \"math/bits\"\n", name)
+	fmt.Fprintf(&buf, "\n// The unrolled rounds are available.\n"+
+		"const haveUnrolled = true\n")
+
+	fmt.Fprintf(&buf, "\n// Function encryptWordsUnrolled runs the %d "+
+		"rounds of Serpent-1 over the\n// block 'x' using the subkeys 'K'.\n",
+		rounds)
+	fmt.Fprintf(&buf, "func encryptWordsUnrolled(x *[4]uint32, "+
+		"K [][4]uint32) {\n")
+	fmt.Fprintf(&buf, "\t_ = K[%d]\n", rounds)
+	fmt.Fprintf(&buf, "\tx0, x1, x2, x3 := x[0], x[1], x[2], x[3]\n")
+	for i := 0; i < rounds; i++ {
+		fmt.Fprintf(&buf, "\n\t// Round %d\n", i)
+		writeKeyMixing(&buf, i)
+		writeCircuit(&buf, circuits[i%8])
+		if i == rounds-1 {
+			writeKeyMixing(&buf, rounds)
+		} else {
+			writeProgram(&buf, lt)
+		}
+	}
+	fmt.Fprintf(&buf, "\tx[0], x[1], x[2], x[3] = x0, x1, x2, x3\n}\n")
+
+	fmt.Fprintf(&buf, "\n// Function decryptWordsUnrolled undoes "+
+		"encryptWordsUnrolled.\n")
+	fmt.Fprintf(&buf, "func decryptWordsUnrolled(x *[4]uint32, "+
+		"K [][4]uint32) {\n")
+	fmt.Fprintf(&buf, "\t_ = K[%d]\n", rounds)
+	fmt.Fprintf(&buf, "\tx0, x1, x2, x3 := x[0], x[1], x[2], x[3]\n")
+	for i := rounds - 1; i >= 0; i-- {
+		fmt.Fprintf(&buf, "\n\t// Round %d\n", i)
+		if i == rounds-1 {
+			writeKeyMixing(&buf, rounds)
+		} else {
+			writeProgram(&buf, inverseLT)
+		}
+		writeCircuit(&buf, inverses[i%8])
+		writeKeyMixing(&buf, i)
+	}
+	fmt.Fprintf(&buf, "\tx[0], x[1], x[2], x[3] = x0, x1, x2, x3\n}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// Function writeKeyMixing writes the mixing of subkey 'i'.
+func writeKeyMixing(buf *bytes.Buffer, i int) {
+	fmt.Fprintf(buf, "\tx0 ^= K[%d][0]\n\tx1 ^= K[%d][1]\n"+
+		"\tx2 ^= K[%d][2]\n\tx3 ^= K[%d][3]\n", i, i, i, i)
+}
+
+// Function writeCircuit writes 'c' as a block computing the words x0 to
+// x3 from themselves.
+func writeCircuit(buf *bytes.Buffer, c *serpent.SBoxCircuit) {
+	fmt.Fprintf(buf, "\t{\n\t\tw0, w1, w2, w3 := x0, x1, x2, x3\n")
+	for i, g := range c.Gates {
+		var expr string
+		switch g.Op {
+		case serpent.XOR:
+			expr = fmt.Sprintf("w%d ^ w%d", g.In[0], g.In[1])
+		case serpent.AND:
+			expr = fmt.Sprintf("w%d & w%d", g.In[0], g.In[1])
+		case serpent.OR:
+			expr = fmt.Sprintf("w%d | w%d", g.In[0], g.In[1])
+		case serpent.INV:
+			expr = fmt.Sprintf("^w%d", g.In[0])
+		default:
+			expr = fmt.Sprintf("w%d", g.In[0])
+		}
+		fmt.Fprintf(buf, "\t\tw%d := %s\n", 4+i, expr)
+	}
+	fmt.Fprintf(buf, "\t\tx0, x1, x2, x3 = w%d, w%d, w%d, w%d\n\t}\n",
+		c.Outputs[0], c.Outputs[1], c.Outputs[2], c.Outputs[3])
+}
+
+// Function writeProgram writes the steps of 'program'.
+func writeProgram(buf *bytes.Buffer, program []ltStep) {
+	for _, s := range program {
+		switch {
+		case !s.xor:
+			fmt.Fprintf(buf, "\tx%d = bits.RotateLeft32(x%d, %d)\n", s.dst,
+				s.dst, s.rotate)
+		case s.shift != 0:
+			fmt.Fprintf(buf, "\tx%d ^= x%d ^ x%d<<%d\n", s.dst, s.a, s.b,
+				s.shift)
+		default:
+			fmt.Fprintf(buf, "\tx%d ^= x%d ^ x%d\n", s.dst, s.a, s.b)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// Function TestGeneratedFile fails when rounds_words.go is not what the
+// generator writes from the current tables, in which case running
+// "go generate" in the package directory updates it.
+func TestGeneratedFile(t *testing.T) {
+	want, err := generate("rounds_words.go")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	got, err := ioutil.ReadFile(filepath.Join("..", "..", "rounds_words.go"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("rounds_words.go is stale, run go generate\n")
+	}
+}
+
+// Function TestTraceProgram checks that the programs traced from the
+// linear transformation and its inverse undo each other.
+func TestTraceProgram(t *testing.T) {
+	lt, err := traceProgram(serpent.LTBitsliceOf[symWord, symWords])
+	if err != nil {
+		t.Fatalf("tracing LT failed: %v", err)
+	}
+	inverse, err := traceProgram(
+		serpent.LTBitsliceInverseOf[symWord, symWords])
+	if err != nil {
+		t.Fatalf("tracing LTInverse failed: %v", err)
+	}
+	x := [4]uint32{0x01234567, 0x89abcdef, 0xdeadbeef, 0x0badf00d}
+	if got := run(inverse, run(lt, x)); got != x {
+		t.Errorf("the inverse program gives %x, want %x\n", got, x)
+	}
+}
@@ -0,0 +1,129 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"runtime"
+	"sync/atomic"
+)
+
+// The subkeys are kept in word arrays that can be wiped. The Cipher of the
+// Bitstring API keeps them so as well and mixes them into its Bitstring
+// state as words, so that only the state becomes a Bitstring; Subkeys,
+// which returns Bitstring copies, is the exception. Destroy wipes the
+// words at once; otherwise a finalizer wipes them once no block refers to
+// them any more. NewLockedCipher also locks them into memory, where the
+// system supports it, so that they are never written to swap. The
+// temporary values of the key schedule are wiped before the constructors
+// return.
+
+// A Destroyer holds key material that Destroy wipes. Every cipher.Block
+// returned by this package, and the Cipher, is a Destroyer.
+type Destroyer interface {
+	Destroy()
+}
+
+// A subkeys owns the words of an expanded key. The blocks sharing it,
+// such as a maskedBlock built on a block, all refer to it, which keeps the
+// finalizer from wiping the words while any of them is in use.
+type subkeys struct {
+	k [][4]uint32
+	// mem is the locked memory holding k, if any.
+	mem []byte
+	// destroyed is set by destroy and release, and read by check from
+	// any goroutine using a block.
+	destroyed atomic.Bool
+}
+
+// Function newSubkeys moves 'K' into a subkeys, in locked memory when
+// 'lock' is set, and wipes 'K'.
+func newSubkeys(K [][4]uint32, lock bool) (*subkeys, error) {
+	s := &subkeys{}
+	if lock {
+		mem, k, err := lockedWords(len(K))
+		if err != nil {
+			wipeWords(K)
+			return nil, err
+		}
+		s.mem, s.k = mem, k
+		copy(s.k, K)
+		wipeWords(K)
+	} else {
+		s.k = K
+	}
+	runtime.SetFinalizer(s, (*subkeys).release)
+	return s, nil
+}
+
+// Method destroy wipes the words of 's'. They remain mapped until the
+// finalizer runs, so that blocks used after Destroy panic instead of
+// faulting.
+func (s *subkeys) destroy() {
+	wipeWords(s.k)
+	s.destroyed.Store(true)
+}
+
+// Method release wipes the words of 's' and frees its locked memory.
+func (s *subkeys) release() {
+	wipeWords(s.k)
+	s.destroyed.Store(true)
+	if s.mem != nil {
+		unlockWords(s.mem)
+		s.mem = nil
+	}
+	s.k = nil
+}
+
+// Method check panics if 's' has been destroyed.
+func (s *subkeys) check() {
+	if s.destroyed.Load() {
+		panic("serpent: use of destroyed cipher")
+	}
+}
+
+// Function wipeWords sets every word of 'K' to zero.
+func wipeWords(K [][4]uint32) {
+	for i := range K {
+		K[i] = [4]uint32{}
+	}
+}
+
+// Function wipeBytes sets every byte of 'b' to zero.
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// Function NewLockedCipher is NewCipherWithParams with the subkeys locked
+// into memory. It fails where locking is not supported or not permitted,
+// such as when the limit on locked memory is reached.
+func NewLockedCipher(p Params, key []byte) (cipher.Block, error) {
+	return newCipher(p, key, true)
+}
+
+// Method Destroy wipes the subkeys of 'b'. The block must not be used
+// afterwards; Encrypt and Decrypt panic.
+func (b *block) Destroy() {
+	b.keys.destroy()
+}
+
+// Method Destroy wipes the subkeys of 'c'. The Cipher must not be used
+// afterwards; its methods panic.
+func (c *Cipher) Destroy() {
+	c.keys.destroy()
+}
+
+// Method Destroy wipes the subkeys of 'b', see Cipher.Destroy.
+func (b *bitstringBlock) Destroy() {
+	b.c.Destroy()
+}
+
+// Method Destroy destroys both implementations of 'c' that are
+// Destroyers.
+func (c *CrossCheck) Destroy() {
+	for _, b := range c.blocks {
+		if d, ok := b.(Destroyer); ok {
+			d.Destroy()
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package serpent
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Function lockedWords maps anonymous memory for 'n' subkeys and locks it
+// into memory. It returns the mapping and the subkeys viewed over it.
+func lockedWords(n int) ([]byte, [][4]uint32, error) {
+	if n == 0 {
+		return nil, nil, nil
+	}
+	mem, err := syscall.Mmap(-1, 0, 16*n, syscall.PROT_READ|syscall.PROT_WRITE,
+		syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, fmt.Errorf("serpent: mapping key memory: %v", err)
+	}
+	if err := syscall.Mlock(mem); err != nil {
+		syscall.Munmap(mem)
+		return nil, nil, fmt.Errorf("serpent: locking key memory: %v", err)
+	}
+	k := unsafe.Slice((*[4]uint32)(unsafe.Pointer(&mem[0])), n)
+	return mem, k, nil
+}
+
+// Function unlockWords unlocks and unmaps the memory of lockedWords. Its
+// contents must have been wiped.
+func unlockWords(mem []byte) {
+	syscall.Munlock(mem)
+	syscall.Munmap(mem)
+}
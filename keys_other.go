@@ -0,0 +1,14 @@
+//go:build !linux
+
+package serpent
+
+import "errors"
+
+// Function lockedWords fails: locked memory is only supported on Linux.
+func lockedWords(n int) ([]byte, [][4]uint32, error) {
+	return nil, nil, errors.New("serpent: locked key memory is not " +
+		"supported on this system")
+}
+
+// Function unlockWords is never called without locked memory.
+func unlockWords(mem []byte) {}
@@ -0,0 +1,166 @@
+package serpent
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Function zeroed reports whether every subkey in 'K' is zero.
+func zeroed(K [][4]uint32) bool {
+	for i := range K {
+		if K[i] != [4]uint32{} {
+			return false
+		}
+	}
+	return true
+}
+
+// Function panics reports whether 'f' panics.
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return false
+}
+
+// Function TestDestroy checks that Destroy wipes the subkeys of the word,
+// masked and faulty blocks and that the blocks refuse service afterwards.
+func TestDestroy(t *testing.T) {
+	key, _ := hex.DecodeString(blockTests[0].key)
+	plain := make([]byte, BlockSize)
+	var blocks []cipher.Block
+	for _, f := range []func(Params, []byte) (cipher.Block, error){
+		NewCipherWithParams,
+		func(p Params, key []byte) (cipher.Block, error) {
+			return NewMaskedCipher(p, key, nil)
+		},
+		func(p Params, key []byte) (cipher.Block, error) {
+			return NewFaultyCipher(p, key)
+		},
+	} {
+		b, err := f(Serpent1, key)
+		if err != nil {
+			t.Fatalf("creating the cipher failed: %v", err)
+		}
+		blocks = append(blocks, b)
+	}
+	for i, b := range blocks {
+		var K [][4]uint32
+		switch b := b.(type) {
+		case *block:
+			K = b.k
+		case *maskedBlock:
+			K = b.k
+		case *faultyBlock:
+			K = b.k
+		}
+		if zeroed(K) {
+			t.Fatalf("block %d: the subkeys are zero before Destroy", i)
+		}
+		b.(Destroyer).Destroy()
+		if !zeroed(K) {
+			t.Errorf("block %d: Destroy leaves the subkeys\n", i)
+		}
+		out := make([]byte, BlockSize)
+		if !panics(func() { b.Encrypt(out, plain) }) ||
+			!panics(func() { b.Decrypt(out, plain) }) {
+			t.Errorf("block %d: the destroyed block is still served\n", i)
+		}
+	}
+}
+
+// Function TestLockedCipher checks that a cipher in locked memory agrees
+// with NewCipher and is wiped by Destroy.
+func TestLockedCipher(t *testing.T) {
+	for i, test := range blockTests {
+		key, _ := hex.DecodeString(test.key)
+		plain, _ := hex.DecodeString(test.plain)
+		want, _ := hex.DecodeString(test.cipher)
+		b, err := NewLockedCipher(Serpent1, key)
+		if err != nil {
+			t.Skipf("no locked memory: %v", err)
+		}
+		got := make([]byte, BlockSize)
+		b.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			t.Errorf("test %d: Encrypt = %x, want %x\n", i, got, want)
+		}
+		b.Decrypt(got, want)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("test %d: Decrypt = %x, want %x\n", i, got, plain)
+		}
+		K := b.(*block).k
+		b.(Destroyer).Destroy()
+		if !zeroed(K) {
+			t.Errorf("Destroy leaves the locked subkeys\n")
+		}
+		// The memory stays mapped until the block is collected.
+		runtime.KeepAlive(b)
+	}
+}
+
+// Function TestFinalizer checks that the subkeys of a block that is
+// dropped without Destroy are wiped once it is collected.
+func TestFinalizer(t *testing.T) {
+	key, _ := hex.DecodeString(blockTests[0].key)
+	b, err := NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	K := b.(*block).k
+	if zeroed(K) {
+		t.Fatalf("the subkeys are zero before collection")
+	}
+	b = nil
+	for i := 0; i < 100 && !zeroed(K); i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !zeroed(K) {
+		t.Errorf("the subkeys of a collected block are not wiped\n")
+	}
+}
+
+// Function TestCipherDestroy checks that Destroy wipes the subkeys of a
+// Cipher and of the blocks wrapping one and that the Cipher refuses
+// service afterwards.
+func TestCipherDestroy(t *testing.T) {
+	c, err := New(Serpent1, makeLongkey(bs))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	words := c.keys.k
+	c.Destroy()
+	if !zeroed(words) {
+		t.Errorf("Destroy leaves the subkeys\n")
+	}
+	for name, f := range map[string]func(){
+		"Encrypt":         func() { c.Encrypt(bs[:128]) },
+		"DecryptBitslice": func() { c.DecryptBitslice(bs[:128]) },
+		"Subkeys":         func() { c.Subkeys() },
+	} {
+		if !panics(f) {
+			t.Errorf("%s does not panic after Destroy\n", name)
+		}
+	}
+
+	key, _ := hex.DecodeString(blockTests[0].key)
+	x, err := NewCrossCheck(Serpent1, key, "word", "bitstring")
+	if err != nil {
+		t.Fatalf("NewCrossCheck failed: %v", err)
+	}
+	word := x.blocks[0].(*block).k
+	bitstring := x.blocks[1].(*bitstringBlock).c.keys.k
+	x.Destroy()
+	if !zeroed(word) {
+		t.Errorf("CrossCheck.Destroy leaves the word subkeys\n")
+	}
+	if !zeroed(bitstring) {
+		t.Errorf("CrossCheck.Destroy leaves the Bitstring subkeys\n")
+	}
+}
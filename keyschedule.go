@@ -0,0 +1,95 @@
+package serpent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// Function RecoverUserKey inverts the Serpent-1 key schedule. 'K' holds
+// consecutive subkeys in bitslice format, as returned by makeSubkeys, the
+// first of them being subkey 'round'; at least two are needed since eight
+// prekey words determine all the others. The result is the 256-bit long
+// key together with the user key lengths, in bits, that are consistent
+// with its padding. The user key of length l is the first l bits of the
+// long key.
+func RecoverUserKey(K []Bitstring, round int) (Bitstring, []int, error) {
+	return Serpent1.RecoverUserKey(K, round)
+}
+
+// Method RecoverUserKey inverts the key schedule of 'p', see the package
+// level RecoverUserKey. Any subkeys beyond the first two are checked
+// against the schedule of the recovered key.
+func (p Params) RecoverUserKey(K []Bitstring, round int) (Bitstring,
+	[]int, error) {
+	if err := p.Validate(); err != nil {
+		return "", nil, err
+	}
+	v := newVariant(p)
+	if len(K) < 2 {
+		return "", nil, fmt.Errorf("serpent: at least two subkeys are "+
+			"required, got %d", len(K))
+	}
+	if round < 0 || round+len(K) > v.subkeyCount() {
+		return "", nil, fmt.Errorf("serpent: subkeys %d to %d are out of "+
+			"range", round, round+len(K)-1)
+	}
+	for i, k := range K {
+		if err := checkBits(k); err != nil {
+			return "", nil, err
+		}
+		if len(k) != 128 {
+			return "", nil, fmt.Errorf("serpent: subkey %d has %d bits",
+				round+i, len(k))
+		}
+	}
+
+	// Undo the S-Boxes of the key schedule to obtain the prekey words
+	// w[4*round] to w[4*round+7], stored at offset 8 so that the user key
+	// ends up in w[0] to w[7].
+	a := 4 * round
+	w := make([]uint32, a+16)
+	for i := 0; i < 2; i++ {
+		x := wordsFromBitstring(K[i])
+		v.sWordsInverse(v.keyScheduleBox(round+i), &x)
+		copy(w[a+8+4*i:], x[:])
+	}
+
+	// Run the affine recurrence backwards.
+	for i := a + 7; i >= 0; i-- {
+		w[i] = bits.RotateLeft32(w[i+8], -11) ^ w[i+3] ^ w[i+5] ^ w[i+7] ^
+			uint32(v.Phi) ^ uint32(i)
+	}
+
+	var key [8]uint32
+	copy(key[:], w[:8])
+	subkeys := v.wordSubkeys(&key)
+	for i := 2; i < len(K); i++ {
+		if wordsToBitstring(&subkeys[round+i]) != K[i] {
+			return "", nil, fmt.Errorf("serpent: subkey %d does not "+
+				"follow from the subkeys before it", round+i)
+		}
+	}
+
+	var b [32]byte
+	for i := range key {
+		binary.LittleEndian.PutUint32(b[4*i:], key[i])
+	}
+	long := BitstringFromBytes(b[:])
+	return long, keyLengths(long), nil
+}
+
+// Function keyLengths returns the user key lengths from which makeLongkey
+// yields 'long', shortest first. The full 256 bits are always a candidate;
+// a shorter key is one if it is followed by a single 1 bit and zeros.
+func keyLengths(long Bitstring) []int {
+	var lengths []int
+	last := len(long) - 1
+	for last >= 0 && long[last] == '0' {
+		last--
+	}
+	if last%32 == 0 && last >= 64 && last < 256 {
+		lengths = append(lengths, last)
+	}
+	return append(lengths, 256)
+}
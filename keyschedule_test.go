@@ -0,0 +1,63 @@
+package serpent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function TestRecoverUserKey recovers keys of every length from pairs of
+// subkeys taken anywhere in the schedule.
+func TestRecoverUserKey(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for l := 64; l <= 256; l += 32 {
+		key := BitstringFromBytes(randomBytes(r, l/8))
+		long := makeLongkey(key)
+		K, _ := makeSubkeys(long)
+		for _, round := range []int{0, 5, 31} {
+			got, lengths, err := RecoverUserKey(K[round:round+2], round)
+			if err != nil {
+				t.Fatalf("%d bits, round %d: %v\n", l, round, err)
+			}
+			if got != long {
+				t.Errorf("%d bits, round %d: wrong long key\n", l, round)
+			}
+			found := false
+			for _, n := range lengths {
+				found = found || n == l
+			}
+			if !found || lengths[len(lengths)-1] != 256 {
+				t.Errorf("%d bits: candidate lengths %v\n", l, lengths)
+			}
+		}
+	}
+}
+
+// Function TestRecoverUserKeyErrors checks the consistency check and the
+// range of the subkeys.
+func TestRecoverUserKeyErrors(t *testing.T) {
+	K, _ := makeSubkeys(makeLongkey(bs))
+	if _, _, err := RecoverUserKey(K[10:20], 10); err != nil {
+		t.Errorf("consistent subkeys gave %v\n", err)
+	}
+	bad := append(Bitslice{}, K[10:13]...)
+	bad[2] = bad[2].BinaryXor(bad[0])
+	if _, _, err := RecoverUserKey(bad, 10); err == nil {
+		t.Errorf("inconsistent subkeys accepted\n")
+	}
+	if _, _, err := RecoverUserKey(K[31:33], 32); err == nil {
+		t.Errorf("subkeys past the schedule accepted\n")
+	}
+	if _, _, err := RecoverUserKey(K[:1], 0); err == nil {
+		t.Errorf("a single subkey accepted\n")
+	}
+
+	p := Serpent1
+	p.Phi = 0x12345678
+	p.KeyScheduleSBox = func(i int) int { return (3 + i) % 8 }
+	c, _ := New(p, bs)
+	Kc, _ := c.Subkeys()
+	long, _, err := p.RecoverUserKey(Kc[7:9], 7)
+	if err != nil || long != makeLongkey(bs) {
+		t.Errorf("variant key schedule not inverted: %v\n", err)
+	}
+}
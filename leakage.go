@@ -0,0 +1,225 @@
+package serpent
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// LeakageModel selects what a LeakageSimulator records of each S-Box of
+// the first round.
+type LeakageModel int
+
+const (
+	// HammingWeight leaks the number of bits set in the S-Box output.
+	HammingWeight LeakageModel = iota
+	// HammingDistance leaks the number of bits that differ between the
+	// S-Box input and output, as when the output overwrites the input in
+	// a register.
+	HammingDistance
+)
+
+// LeakageSimulator produces simulated power traces of the word core. Each
+// trace has one sample per S-Box of the first round: the leakage of its
+// 4-bit output under Model, plus Gaussian noise of standard deviation
+// Noise. The S-Boxes are the columns of the word core, S-Box j taking
+// bit j of each word.
+//
+// When Masked is set the traces come from the masked core of
+// NewMaskedCipher instead, masked by the same random source as the
+// plaintexts, and each sample sums the leakage of the two shares.
+type LeakageSimulator struct {
+	Params Params
+	// Key is the byte key, see NewCipherWithParams.
+	Key    []byte
+	Model  LeakageModel
+	Noise  float64
+	Seed   int64
+	Masked bool
+}
+
+// A Trace is the leakage recorded while encrypting one block.
+type Trace struct {
+	Plaintext, Ciphertext [BlockSize]byte
+	Samples               []float64
+}
+
+// Method Traces encrypts 'n' random plaintexts and returns their traces.
+func (s LeakageSimulator) Traces(n int) ([]Trace, error) {
+	rng := rand.New(rand.NewSource(s.Seed))
+	var b cipher.Block
+	var err error
+	if s.Masked {
+		b, err = NewMaskedCipher(s.Params, s.Key, rng)
+	} else {
+		b, err = NewCipherWithParams(s.Params, s.Key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	traces := make([]Trace, n)
+	for t := range traces {
+		tr := &traces[t]
+		rng.Read(tr.Plaintext[:])
+		var leaks [32]int
+		if s.Masked {
+			leaks = s.maskedLeakage(b.(*maskedBlock), tr.Plaintext[:])
+		} else {
+			leaks = s.leakage(b.(*block), tr.Plaintext[:])
+		}
+		tr.Samples = make([]float64, 32)
+		for j, l := range leaks {
+			tr.Samples[j] = float64(l) + rng.NormFloat64()*s.Noise
+		}
+		b.Encrypt(tr.Ciphertext[:], tr.Plaintext[:])
+	}
+	return traces, nil
+}
+
+// Method leakage returns the noise free leakage of the S-Boxes of the
+// first round of 'b' encrypting 'plaintext'.
+func (s LeakageSimulator) leakage(b *block, plaintext []byte) (l [32]int) {
+	x := loadWords(plaintext)
+	xorWords(&x, &b.k[b.v.StartRound])
+	in := x
+	b.v.sWords(b.v.StartRound, &x)
+	for j := uint(0); j < 32; j++ {
+		l[j] = leakage(s.Model, column(&in, j), column(&x, j))
+	}
+	return
+}
+
+// Method maskedLeakage is leakage for the masked core.
+func (s LeakageSimulator) maskedLeakage(b *maskedBlock,
+	plaintext []byte) (l [32]int) {
+	x := b.share(loadWords(plaintext))
+	xorWords(&x[0], &b.k[b.v.StartRound])
+	in := x
+	b.sbox(b.v.StartRound, 0, &x)
+	for j := uint(0); j < 32; j++ {
+		for k := range x {
+			l[j] += leakage(s.Model, column(&in[k], j), column(&x[k], j))
+		}
+	}
+	return
+}
+
+// Function leakage returns the noise free leakage of an S-Box mapping
+// 'in' to 'out'.
+func leakage(model LeakageModel, in, out int) int {
+	if model == HammingDistance {
+		return bits.OnesCount(uint(in ^ out))
+	}
+	return bits.OnesCount(uint(out))
+}
+
+// The trace file format is little-endian: the magic "SPTR", the number of
+// traces and the number of samples per trace as uint32, then for each
+// trace the plaintext, the ciphertext and the samples as float32. A trace
+// has at most maxTraceSamples samples. ReadTraces reads the samples in
+// chunks of traceChunk, so that the memory it takes grows with the data
+// read rather than with the counts a corrupt header claims.
+const (
+	traceMagic      = "SPTR"
+	maxTraceSamples = 1 << 24
+	traceChunk      = 4096
+)
+
+// Function WriteTraces writes 'traces' in the trace file format. All
+// traces must have the same number of samples.
+func WriteTraces(w io.Writer, traces []Trace) error {
+	samples := 0
+	if len(traces) > 0 {
+		samples = len(traces[0].Samples)
+	}
+	if samples > maxTraceSamples {
+		return fmt.Errorf("serpent: %d samples per trace, at most %d",
+			samples, maxTraceSamples)
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(traceMagic)
+	binary.Write(bw, binary.LittleEndian,
+		[2]uint32{uint32(len(traces)), uint32(samples)})
+	buf := make([]byte, 4*samples)
+	for i := range traces {
+		t := &traces[i]
+		if len(t.Samples) != samples {
+			return fmt.Errorf("serpent: trace %d has %d samples, want %d",
+				i, len(t.Samples), samples)
+		}
+		bw.Write(t.Plaintext[:])
+		bw.Write(t.Ciphertext[:])
+		for j, x := range t.Samples {
+			binary.LittleEndian.PutUint32(buf[4*j:],
+				math.Float32bits(float32(x)))
+		}
+		bw.Write(buf)
+	}
+	return bw.Flush()
+}
+
+// Function ReadTraces reads traces written by WriteTraces.
+func ReadTraces(r io.Reader) ([]Trace, error) {
+	br := bufio.NewReader(r)
+	var magic [4]byte
+	var header [2]uint32
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, err
+	}
+	if string(magic[:]) != traceMagic {
+		return nil, errors.New("serpent: not a trace file")
+	}
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header[1] > maxTraceSamples {
+		return nil, fmt.Errorf("serpent: trace file has %d samples per "+
+			"trace, at most %d", header[1], maxTraceSamples)
+	}
+	n, samples := int(header[0]), int(header[1])
+	var traces []Trace
+	buf := make([]byte, 4*traceChunk)
+	for i := 0; i < n; i++ {
+		var t Trace
+		if _, err := io.ReadFull(br, buf[:2*BlockSize]); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		copy(t.Plaintext[:], buf)
+		copy(t.Ciphertext[:], buf[BlockSize:])
+		capacity := samples
+		if capacity > traceChunk {
+			capacity = traceChunk
+		}
+		t.Samples = make([]float64, 0, capacity)
+		for len(t.Samples) < samples {
+			k := samples - len(t.Samples)
+			if k > traceChunk {
+				k = traceChunk
+			}
+			if _, err := io.ReadFull(br, buf[:4*k]); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			for j := 0; j < k; j++ {
+				t.Samples = append(t.Samples, float64(math.Float32frombits(
+					binary.LittleEndian.Uint32(buf[4*j:]))))
+			}
+		}
+		traces = append(traces, t)
+	}
+	return traces, nil
+}
+
+// Function unexpectedEOF turns io.EOF, met in the middle of a trace file,
+// into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
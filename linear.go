@@ -271,21 +271,13 @@ var LTTableInverse []Ttable = []Ttable{
 	[]int{4, 27, 86, 97, 113, 115, 127},
 }
 
+// Function LT applies the table based version of the linear transformation
+// to the 128-bit Bitstring 'input' and returns a 128-bit Bitstring.
 func LT(input Bitstring) Bitstring {
 	if len(input) != 128 {
 		fmt.Printf("input is not 128 bits long\n")
 	}
-	var result Bitstring
-	t := len(LTTable)
-	for i := 0; i < t; i++ {
-		var outputBit Bitstring = "0"
-		for _, j := range LTTable[i] {
-			bsj := Bitstring(input[j])
-			outputBit = outputBit.BinaryXor(bsj)
-		}
-		result = result + Bitstring(outputBit)
-	}
-	return result
+	return applyLTTable(LTTable, input)
 }
 
 // Function LTInverse applies the inverse of the table based version of
@@ -295,15 +287,21 @@ func LTInverse(output Bitstring) Bitstring {
 	if len(output) != 128 {
 		fmt.Printf("output is not 128 bits long\n")
 	}
+	return applyLTTable(LTTableInverse, output)
+}
+
+// Function applyLTTable computes each output bit as the xor of the bits of
+// 'input' listed in the corresponding entry of 'table'.
+func applyLTTable(table []Ttable, input Bitstring) Bitstring {
 	var result Bitstring
-	t := len(LTTableInverse)
+	t := len(table)
 	for i := 0; i < t; i++ {
-		var inputBit Bitstring = "0"
-		for _, j := range LTTableInverse[i] {
-			bsj := Bitstring(output[j])
-			inputBit = inputBit.BinaryXor(bsj)
+		var outputBit Bitstring = "0"
+		for _, j := range table[i] {
+			bsj := Bitstring(input[j])
+			outputBit = outputBit.BinaryXor(bsj)
 		}
-		result = result + Bitstring(inputBit)
+		result = result + Bitstring(outputBit)
 	}
 	return result
 }
@@ -312,34 +310,12 @@ func LTInverse(output Bitstring) Bitstring {
 // transformation to 'x', a list of 4 32-bit Bitstrings, least significant
 // Bitstring first. Returns a list of 4 32-bit Bitstrings.
 func LTBitslice(x Bitslice) Bitslice {
-	x[0] = x[0].RotateLeft(13)
-	x[2] = x[2].RotateLeft(3)
-	x[1] = x[1].Xor(Bitslice{x[1], x[0], x[2]})
-	x[3] = x[3].Xor(Bitslice{x[3], x[2], x[0].ShiftLeft(3)})
-	x[1] = x[1].RotateLeft(1)
-	x[3] = x[3].RotateLeft(7)
-	x[0] = x[0].Xor(Bitslice{x[0], x[1], x[3]})
-	x[2] = x[2].Xor(Bitslice{x[2], x[3], x[1].ShiftLeft(7)})
-	x[0] = x[0].RotateLeft(5)
-	x[2] = x[2].RotateLeft(22)
-
-	return x
+	return LTBitsliceOf(x)
 }
 
 // Function LTBitsliceInverse applies, in reverse, the equations-based
 // version of the linear transformation to 'x', a list of 4 32-bit Bitstrings,
 // least significant bit first. Returns a list of 4 32-bit Bitstrings.
 func LTBitsliceInverse(x Bitslice) Bitslice {
-	x[2] = x[2].RotateRight(22)
-	x[0] = x[0].RotateRight(5)
-	x[2] = x[2].Xor(Bitslice{x[2], x[3], x[1].ShiftLeft(7)})
-	x[0] = x[0].Xor(Bitslice{x[0], x[1], x[3]})
-	x[3] = x[3].RotateRight(7)
-	x[1] = x[1].RotateRight(1)
-	x[3] = x[3].Xor(Bitslice{x[3], x[2], x[0].ShiftLeft(3)})
-	x[1] = x[1].Xor(Bitslice{x[1], x[0], x[2]})
-	x[2] = x[2].RotateRight(3)
-	x[0] = x[0].RotateRight(13)
-
-	return x
+	return LTBitsliceInverseOf(x)
 }
@@ -0,0 +1,125 @@
+package serpent
+
+import (
+	"strings"
+
+	"github.com/JonPulfer/serpent/gf2"
+)
+
+// Function LTMatrix derives the matrix of the linear transformation in the
+// normal (IP) domain from the equations of LTBitslice: entry [i][j] is 1
+// when output bit i depends on input bit j. Column j is the image of the
+// block with only bit j set, taken through FP into the bitslice domain and
+// back through IP.
+func LTMatrix() *gf2.Matrix {
+	m := gf2.New(128, 128)
+	var s Bitstring
+	for j := 0; j < 128; j++ {
+		unit := []byte(strings.Repeat("0", 128))
+		unit[j] = '1'
+		x := FP(Bitstring(unit)).QuadSplit()
+		y := IP(s.QuadJoin(LTBitslice(x)))
+		for i := 0; i < 128; i++ {
+			if y[i] == '1' {
+				m.Set(i, j, 1)
+			}
+		}
+	}
+	return m
+}
+
+// Function TablesFromMatrix lists, for each row of 'm', the columns that
+// are set, giving the XOR positions in the form of LTTable.
+func TablesFromMatrix(m *gf2.Matrix) []Ttable {
+	table := make([]Ttable, m.Rows())
+	for i := range table {
+		table[i] = Ttable(m.Row(i).Ones())
+	}
+	return table
+}
+
+// Function GenerateLTTables regenerates LTTable and LTTableInverse from
+// LTMatrix.
+func GenerateLTTables() (table, inverse []Ttable, err error) {
+	m := LTMatrix()
+	minv, err := m.Inverse()
+	if err != nil {
+		return nil, nil, err
+	}
+	return TablesFromMatrix(m), TablesFromMatrix(minv), nil
+}
+
+// Function BranchNumber returns the branch number of the 128 x 128 matrix
+// 'm' over 4-bit words: the least number of non-zero nibbles, S-Box i
+// owning bits 4i..4i+3, in a non-zero input and its image together.
+//
+// The least pair has a side with at most half of the branch number active
+// nibbles, so inputs and outputs with k active nibbles are searched for
+// increasing k until 2k reaches the best found. 'm' must be invertible.
+func BranchNumber(m *gf2.Matrix) (int, error) {
+	minv, err := m.Inverse()
+	if err != nil {
+		return 0, err
+	}
+	best := 2 * 32
+	for k := 1; 2*k < best; k++ {
+		for _, f := range []*gf2.Matrix{m, minv} {
+			forEachNibbleVector(k, func(x gf2.Vector) {
+				if b := k + activeNibbles(f.MulVector(x)); b < best {
+					best = b
+				}
+			})
+		}
+	}
+	return best, nil
+}
+
+// Function LTBranchNumbers returns the differential branch number of the
+// linear transformation and its linear branch number, the latter being
+// that of the transpose as masks propagate through it.
+func LTBranchNumbers() (differential, linear int, err error) {
+	m := LTMatrix()
+	if differential, err = BranchNumber(m); err != nil {
+		return
+	}
+	linear, err = BranchNumber(m.Transpose())
+	return
+}
+
+// Function activeNibbles counts the non-zero nibbles of a 128-bit vector.
+func activeNibbles(v gf2.Vector) (n int) {
+	for _, w := range v {
+		w = (w | w>>1 | w>>2 | w>>3) & 0x1111111111111111
+		for ; w != 0; w &= w - 1 {
+			n++
+		}
+	}
+	return
+}
+
+// Function forEachNibbleVector calls 'f' with every 128-bit vector having
+// exactly 'k' non-zero nibbles. The vector is reused between calls.
+func forEachNibbleVector(k int, f func(gf2.Vector)) {
+	x := gf2.NewVector(128)
+	var rec func(first, left int)
+	rec = func(first, left int) {
+		if left == 0 {
+			f(x)
+			return
+		}
+		for pos := first; pos <= 32-left; pos++ {
+			for v := 1; v < 16; v++ {
+				setNibble(x, pos, v)
+				rec(pos+1, left-1)
+			}
+			setNibble(x, pos, 0)
+		}
+	}
+	rec(0, k)
+}
+
+func setNibble(x gf2.Vector, pos, v int) {
+	for b := 0; b < 4; b++ {
+		x.Set(4*pos+b, v>>uint(b))
+	}
+}
@@ -0,0 +1,39 @@
+package serpent
+
+import (
+	"testing"
+)
+
+// Function TestLTTablesMatchBitslice fails if LTTable or LTTableInverse
+// drift from the equations of LTBitslice.
+func TestLTTablesMatchBitslice(t *testing.T) {
+	table, inverse, err := GenerateLTTables()
+	if err != nil {
+		t.Fatalf("GenerateLTTables failed: %v\n", err)
+	}
+	for i := range table {
+		if !ttablesEqual(table[i:i+1], LTTable[i:i+1]) {
+			t.Errorf("LTTable[%d] = %v, derived %v\n", i, LTTable[i],
+				table[i])
+		}
+		if !ttablesEqual(inverse[i:i+1], LTTableInverse[i:i+1]) {
+			t.Errorf("LTTableInverse[%d] = %v, derived %v\n", i,
+				LTTableInverse[i], inverse[i])
+		}
+	}
+	if rank := LTMatrix().Rank(); rank != 128 {
+		t.Errorf("LT matrix has rank %d\n", rank)
+	}
+}
+
+// Function TestLTBranchNumbers checks the branch numbers of the linear
+// transformation: 3 both for differences and for masks.
+func TestLTBranchNumbers(t *testing.T) {
+	d, l, err := LTBranchNumbers()
+	if err != nil {
+		t.Fatalf("LTBranchNumbers failed: %v\n", err)
+	}
+	if d != 3 || l != 3 {
+		t.Errorf("branch numbers %d and %d, want 3 and 3\n", d, l)
+	}
+}
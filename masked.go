@@ -0,0 +1,177 @@
+package serpent
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"runtime"
+)
+
+// The masked core is a first-order boolean masked version of the word
+// core. Every intermediate block x is held as two shares x0 and x1 with
+// x = x0 xor x1, the plaintext being split with fresh random words. Subkeys
+// are mixed into share 0 and LT is applied to each share, both being
+// linear. The S-Boxes are evaluated gate by gate from their circuits: XOR
+// acts on each share, INV on share 0 only, AND is the two-share gadget of
+// Ishai, Sahai and Wagner taking one fresh random word, and OR is computed
+// from AND by De Morgan's law. The key schedule itself is not masked.
+
+// A maskedBlock implements cipher.Block with the masked core.
+type maskedBlock struct {
+	block
+	random   *bufio.Reader
+	circuits [][2]*SBoxCircuit
+	// wires[w] holds the two shares of wire w of a circuit.
+	wires [][2]uint32
+}
+
+// Function NewMaskedCipher creates a cipher.Block for the parameter set
+// 'p' that computes on two shares, taking its masks from 'random', or
+// from crypto/rand when 'random' is nil. Every block consumes fresh
+// randomness; Encrypt and Decrypt panic if 'random' fails. Unlike the
+// block of NewCipher, the result is not safe for concurrent use.
+func NewMaskedCipher(p Params, key []byte,
+	random io.Reader) (cipher.Block, error) {
+	b, err := NewCipherWithParams(p, key)
+	if err != nil {
+		return nil, err
+	}
+	return newMaskedBlock(b.(*block), random)
+}
+
+// Function newMaskedBlock builds the masked block sharing the subkeys of
+// 'b'.
+func newMaskedBlock(b *block, random io.Reader) (cipher.Block, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	mb := &maskedBlock{block: *b, random: bufio.NewReader(random)}
+	mb.circuits = make([][2]*SBoxCircuit, len(mb.v.SBoxes))
+	for i, sbox := range mb.v.SBoxes {
+		if mb.v.standardSBoxes {
+			mb.circuits[i] = [2]*SBoxCircuit{wordSBoxCircuits[i],
+				wordSBoxCircuitsInverse[i]}
+			continue
+		}
+		for k, s := range []SBox{sbox, sbox.Inverse()} {
+			c, err := SBoxCircuitSearch{SBox: s, Attempts: 20}.Best()
+			if err != nil {
+				return nil, err
+			}
+			mb.circuits[i][k] = c
+		}
+	}
+	return mb, nil
+}
+
+func (b *maskedBlock) Encrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	b.keys.check()
+	x := b.share(loadWords(src))
+	last := b.v.lastRound()
+	for i := b.v.StartRound; i <= last; i++ {
+		xorWords(&x[0], &b.k[i])
+		b.sbox(i, 0, &x)
+		if i == last {
+			xorWords(&x[0], &b.k[last+1])
+		} else {
+			b.v.ltWords(&x[0])
+			b.v.ltWords(&x[1])
+		}
+	}
+	y := b.unshare(&x)
+	storeWords(dst, &y)
+	runtime.KeepAlive(b.keys)
+}
+
+func (b *maskedBlock) Decrypt(dst, src []byte) {
+	checkBlock(dst, src)
+	b.keys.check()
+	x := b.share(loadWords(src))
+	last := b.v.lastRound()
+	for i := last; i >= b.v.StartRound; i-- {
+		if i == last {
+			xorWords(&x[0], &b.k[last+1])
+		} else {
+			b.v.ltWordsInverse(&x[0])
+			b.v.ltWordsInverse(&x[1])
+		}
+		b.sbox(i, 1, &x)
+		xorWords(&x[0], &b.k[i])
+	}
+	y := b.unshare(&x)
+	storeWords(dst, &y)
+	runtime.KeepAlive(b.keys)
+}
+
+// Method share splits 'x' into two shares with a fresh random mask.
+func (b *maskedBlock) share(x [4]uint32) (s [2][4]uint32) {
+	for k := range x {
+		m := b.word()
+		s[0][k], s[1][k] = x[k]^m, m
+	}
+	return
+}
+
+// Method unshare recombines the shares of 'x'.
+func (b *maskedBlock) unshare(x *[2][4]uint32) (y [4]uint32) {
+	y = x[0]
+	xorWords(&y, &x[1])
+	return
+}
+
+// Method word returns a fresh random word.
+func (b *maskedBlock) word() uint32 {
+	var buf [4]byte
+	if _, err := io.ReadFull(b.random, buf[:]); err != nil {
+		panic("serpent: reading masks: " + err.Error())
+	}
+	return binary.LittleEndian.Uint32(buf[:])
+}
+
+// Method sbox applies S-Box 'i', or its inverse when 'inverse' is 1, to
+// the shared block 'x' by evaluating its circuit on the shares.
+func (b *maskedBlock) sbox(i, inverse int, x *[2][4]uint32) {
+	c := b.circuits[b.v.box(i)][inverse]
+	n := 4 + len(c.Gates)
+	if len(b.wires) < n {
+		b.wires = make([][2]uint32, n)
+	}
+	w := b.wires
+	for k := 0; k < 4; k++ {
+		w[k][0], w[k][1] = x[0][k], x[1][k]
+	}
+	for _, g := range c.Gates {
+		a, d := w[g.In[0]], w[g.In[1]]
+		var o [2]uint32
+		switch g.Op {
+		case XOR:
+			o[0], o[1] = a[0]^d[0], a[1]^d[1]
+		case INV:
+			o[0], o[1] = ^a[0], a[1]
+		case AND:
+			o[0], o[1] = b.and(a[0], a[1], d[0], d[1])
+		case OR:
+			o[0], o[1] = b.and(^a[0], a[1], ^d[0], d[1])
+			o[0] = ^o[0]
+		default:
+			o = a
+		}
+		w[g.Out] = o
+	}
+	for k, out := range c.Outputs {
+		x[0][k], x[1][k] = w[out][0], w[out][1]
+	}
+}
+
+// Method and computes shares of the AND of (a0 xor a1) and (b0 xor b1)
+// with the gadget of Ishai, Sahai and Wagner. The order of the xors keeps every
+// intermediate independent of the unmasked values.
+func (b *maskedBlock) and(a0, a1, b0, b1 uint32) (uint32, uint32) {
+	r := b.word()
+	c0 := a0&b0 ^ r
+	c1 := a1&b1 ^ (r ^ a0&b1 ^ a1&b0)
+	return c0, c1
+}
@@ -0,0 +1,74 @@
+package serpent
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Function TestMaskedCipher checks that the masked core agrees with the
+// word core for the standard and other S-Boxes.
+func TestMaskedCipher(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	other := Serpent1
+	other.Rounds = 8
+	other.SBoxes = []SBox{SBoxDecimalTable[5], SBoxDecimalTable[2]}
+	for _, p := range []Params{Serpent1, other} {
+		for i := 0; i < 10; i++ {
+			key := randomBytes(r, 8+4*r.Intn(7))
+			plain := randomBytes(r, BlockSize)
+			b, _ := NewCipherWithParams(p, key)
+			var random io.Reader = r
+			if i%2 == 0 {
+				random = nil
+			}
+			m, err := NewMaskedCipher(p, key, random)
+			if err != nil {
+				t.Fatalf("NewMaskedCipher failed: %v\n", err)
+			}
+			want, got := make([]byte, BlockSize), make([]byte, BlockSize)
+			b.Encrypt(want, plain)
+			m.Encrypt(got, plain)
+			if !bytes.Equal(got, want) {
+				t.Errorf("Encrypt = %x, want %x\n", got, want)
+			}
+			m.Decrypt(got, want)
+			if !bytes.Equal(got, plain) {
+				t.Errorf("Decrypt = %x, want %x\n", got, plain)
+			}
+		}
+	}
+}
+
+// Function TestMaskedLeakage checks that CPA finds the first subkey from
+// unmasked traces but no first-order correlation in masked ones.
+func TestMaskedLeakage(t *testing.T) {
+	key := []byte("sixteen byte key")
+	for _, model := range []LeakageModel{HammingWeight, HammingDistance} {
+		for _, masked := range []bool{false, true} {
+			s := LeakageSimulator{Params: Serpent1, Key: key, Model: model,
+				Seed: 1, Masked: masked}
+			traces, _ := s.Traces(2000)
+			r, err := CPA{Params: Serpent1, Model: model}.Attack(traces)
+			if err != nil {
+				t.Fatalf("Attack failed: %v\n", err)
+			}
+			largest := 0.0
+			for j := range r.Correlations {
+				for _, c := range r.Correlations[j] {
+					largest = math.Max(largest, math.Abs(c))
+				}
+			}
+			if masked && largest > 0.1 {
+				t.Errorf("model %d: masked traces correlate by %.3f\n",
+					model, largest)
+			}
+			if !masked && largest < 0.9 {
+				t.Errorf("model %d: unmasked traces correlate by %.3f\n",
+					model, largest)
+			}
+		}
+	}
+}
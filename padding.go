@@ -0,0 +1,47 @@
+package serpent
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrPadding is returned by Unpad for data that is not correctly padded.
+var ErrPadding = errors.New("serpent: bad padding")
+
+// Function Pad appends PKCS #7 padding to 'data', from 1 to BlockSize
+// bytes each holding the number of bytes added, so that its length is a
+// multiple of BlockSize.
+func Pad(data []byte) []byte {
+	n := BlockSize - len(data)%BlockSize
+	out := make([]byte, len(data)+n)
+	copy(out, data)
+	for i := len(data); i < len(out); i++ {
+		out[i] = byte(n)
+	}
+	return out
+}
+
+// Function Unpad removes the PKCS #7 padding of 'data' and returns the
+// result, which shares the storage of 'data', or ErrPadding. Its running
+// time depends on the length of 'data' only, not on its padding, so that
+// it does not act as a padding oracle.
+func Unpad(data []byte) ([]byte, error) {
+	if len(data) == 0 || len(data)%BlockSize != 0 {
+		return nil, ErrPadding
+	}
+	block := data[len(data)-BlockSize:]
+	n := block[BlockSize-1]
+	// good stays 1 while the last n bytes all equal n, with n in
+	// 1..BlockSize.
+	good := subtle.ConstantTimeLessOrEq(1, int(n)) &
+		subtle.ConstantTimeLessOrEq(int(n), BlockSize)
+	for i := 0; i < BlockSize; i++ {
+		inPad := subtle.ConstantTimeLessOrEq(BlockSize-i, int(n))
+		same := subtle.ConstantTimeByteEq(block[i], n)
+		good &= same | (inPad ^ 1)
+	}
+	if good != 1 {
+		return nil, ErrPadding
+	}
+	return data[:len(data)-int(n)], nil
+}
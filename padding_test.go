@@ -0,0 +1,34 @@
+package serpent
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Function TestPadding checks that Unpad undoes Pad for every length of a
+// block and rejects bad padding.
+func TestPadding(t *testing.T) {
+	for n := 0; n <= 2*BlockSize; n++ {
+		data := bytes.Repeat([]byte{0xa5}, n)
+		padded := Pad(data)
+		if len(padded)%BlockSize != 0 || len(padded) <= n {
+			t.Errorf("length %d: padded to %d bytes\n", n, len(padded))
+		}
+		back, err := Unpad(padded)
+		if err != nil || !bytes.Equal(back, data) {
+			t.Errorf("length %d: Unpad gave %x, %v\n", n, back, err)
+		}
+	}
+	bad := [][]byte{
+		nil,
+		make([]byte, BlockSize-1),
+		make([]byte, BlockSize),
+		append(make([]byte, BlockSize-1), BlockSize+1),
+		append(bytes.Repeat([]byte{3}, BlockSize-3), 2, 3, 3),
+	}
+	for _, data := range bad {
+		if _, err := Unpad(data); err != ErrPadding {
+			t.Errorf("Unpad(%x) gave %v\n", data, err)
+		}
+	}
+}
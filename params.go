@@ -0,0 +1,159 @@
+package serpent
+
+import (
+	"fmt"
+)
+
+// Params describes a member of the Serpent family: how many rounds are
+// applied, which round the cipher starts at and the tables used by the
+// round function and the key schedule. Reduced-round variants are obtained
+// by changing Rounds and StartRound; the round at index StartRound+Rounds-1
+// is treated as the final round, so its linear transformation is replaced by
+// mixing in one more subkey exactly as in round 31 of the full cipher.
+type Params struct {
+	// Rounds is the number of rounds applied, at least 1.
+	Rounds int
+	// StartRound is the index of the first round applied. The round
+	// index selects both the S-Box and the subkey used by that round.
+	// StartRound+Rounds may not exceed the 32 rounds of Serpent.
+	StartRound int
+	// SBoxes lists the S-Boxes, round i using SBoxes[i % len(SBoxes)].
+	SBoxes []SBox
+	// Phi is the 32-bit constant mixed into every prekey word by the
+	// affine recurrence of the key schedule.
+	Phi int
+	// LTTable and LTTableInverse are the table based linear
+	// transformation and its inverse, in the format of the package level
+	// LTTable.
+	LTTable        []Ttable
+	LTTableInverse []Ttable
+	// KeyScheduleSBox returns the index of the S-Box used to derive
+	// subkey i from the prekey. When nil, the Serpent-1 order
+	// (3 - i) mod 8 is used.
+	KeyScheduleSBox func(i int) int
+}
+
+// Serpent1 is the standard 32-round Serpent as submitted to the AES
+// process.
+var Serpent1 = Params{
+	Rounds:         round,
+	StartRound:     0,
+	SBoxes:         SBoxDecimalTable,
+	Phi:            phi,
+	LTTable:        LTTable,
+	LTTableInverse: LTTableInverse,
+}
+
+// Method Validate checks that 'p' describes a usable cipher: the round
+// range is not empty and lies within rounds 0 to 31, every S-Box is a permutation of 0..15 and the two
+// linear transformation tables are the inverse of each other.
+func (p Params) Validate() error {
+	if p.Rounds < 1 {
+		return fmt.Errorf("serpent: at least one round is required, "+
+			"got %d", p.Rounds)
+	}
+	if p.StartRound < 0 {
+		return fmt.Errorf("serpent: start round %d is negative",
+			p.StartRound)
+	}
+	if p.Rounds > round || p.StartRound > round-p.Rounds {
+		return fmt.Errorf("serpent: rounds %d to %d exceed the %d rounds "+
+			"of Serpent", p.StartRound, p.StartRound+p.Rounds-1, round)
+	}
+	if p.Phi < 0 || p.Phi > 0xffffffff {
+		return fmt.Errorf("serpent: phi %#x is not a 32-bit constant",
+			p.Phi)
+	}
+	if len(p.SBoxes) == 0 {
+		return fmt.Errorf("serpent: no S-Boxes given")
+	}
+	for i, sbox := range p.SBoxes {
+		if !sbox.isPermutation() {
+			return fmt.Errorf("serpent: S-Box %d is not a permutation "+
+				"of 0..15", i)
+		}
+	}
+	if err := checkTtable("LTTable", p.LTTable); err != nil {
+		return err
+	}
+	if err := checkTtable("LTTableInverse", p.LTTableInverse); err != nil {
+		return err
+	}
+	if !ttablesInverse(p.LTTable, p.LTTableInverse) {
+		return fmt.Errorf("serpent: LTTableInverse is not the inverse " +
+			"of LTTable")
+	}
+
+	return nil
+}
+
+// Method isPermutation reports whether the S-Box maps 0..15 onto 0..15
+// without repetitions.
+func (sbox SBox) isPermutation() bool {
+	return sbox.Validate() == nil
+}
+
+// Function checkTtable checks that 'table' has one entry per bit of a
+// 128-bit block and only refers to bit positions within the block.
+func checkTtable(name string, table []Ttable) error {
+	if len(table) != 128 {
+		return fmt.Errorf("serpent: %s has %d entries, want 128",
+			name, len(table))
+	}
+	for i, positions := range table {
+		for _, j := range positions {
+			if j < 0 || j > 127 {
+				return fmt.Errorf("serpent: %s[%d] refers to bit %d",
+					name, i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Function ttablesInverse reports whether applying 'table' and then
+// 'inverse' leaves every 128-bit block unchanged. As both are linear it
+// suffices to check the 128 single bit blocks.
+func ttablesInverse(table, inverse []Ttable) bool {
+	for b := 0; b < 128; b++ {
+		var mid [128]bool
+		for i, positions := range table {
+			for _, j := range positions {
+				if j == b {
+					mid[i] = !mid[i]
+				}
+			}
+		}
+		for i, positions := range inverse {
+			var bit bool
+			for _, j := range positions {
+				if mid[j] {
+					bit = !bit
+				}
+			}
+			if bit != (i == b) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Function ttablesEqual reports whether two linear transformation tables
+// list the same positions in the same order.
+func ttablesEqual(a, b []Ttable) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
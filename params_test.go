@@ -0,0 +1,116 @@
+package serpent
+
+import (
+	"testing"
+)
+
+var testPlainText Bitstring = "10011010011010001101110001110100101001010" +
+	"10010101001110001010010100101000000011111110100101111110000" +
+	"0110101001110011001010110010"
+
+// Function TestParamsValidate checks that Validate accepts the standard
+// parameters and rejects broken ones.
+func TestParamsValidate(t *testing.T) {
+	if err := Serpent1.Validate(); err != nil {
+		t.Errorf("Serpent1 does not validate: %v\n", err)
+	}
+
+	p := Serpent1
+	p.Rounds = 0
+	if p.Validate() == nil {
+		t.Errorf("zero rounds accepted\n")
+	}
+	for _, r := range []struct{ start, n int }{{0, 33}, {31, 2},
+		{1, 1 << 30}, {1 << 30, 1}} {
+		p = Serpent1
+		p.StartRound, p.Rounds = r.start, r.n
+		if p.Validate() == nil {
+			t.Errorf("%d rounds from round %d accepted\n", r.n, r.start)
+		}
+	}
+	p = Serpent1
+	p.StartRound, p.Rounds = 31, 1
+	if err := p.Validate(); err != nil {
+		t.Errorf("the last round alone is rejected: %v\n", err)
+	}
+
+	p = Serpent1
+	p.SBoxes = []SBox{[]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13,
+		14, 14}}
+	if p.Validate() == nil {
+		t.Errorf("S-Box with a repeated value accepted\n")
+	}
+
+	p = Serpent1
+	p.LTTableInverse = LTTable
+	if p.Validate() == nil {
+		t.Errorf("LT tables that do not invert each other accepted\n")
+	}
+}
+
+// Function TestNewSerpent1 checks that a Cipher built from Serpent1 agrees
+// with the package level Encrypt and accepts a short key.
+func TestNewSerpent1(t *testing.T) {
+	var target Bitstring = "11111101110001101000110011011111010011000011" +
+		"11010101101101101100110101000100010011001110100101101101011" +
+		"1001011010111011110100101"
+	c, err := New(Serpent1, bs)
+	if err != nil {
+		t.Fatalf("New failed: %v\n", err)
+	}
+	if c.Encrypt(testPlainText) != target {
+		t.Errorf("Encrypt does not match target\n")
+	}
+	if c.EncryptBitslice(testPlainText) != target {
+		t.Errorf("EncryptBitslice does not match target\n")
+	}
+	if c.Decrypt(target) != testPlainText {
+		t.Errorf("Decrypt does not yield plainText\n")
+	}
+	if _, err := New(Serpent1, bs[:100]); err == nil {
+		t.Errorf("100-bit key accepted\n")
+	}
+	key := bs + bs
+	for _, n := range []int{64, 128, 160, 224} {
+		c, err := New(Serpent1, key[:n])
+		if err != nil {
+			t.Fatalf("New failed for a %d-bit key: %v\n", n, err)
+		}
+		K, KHat := c.Subkeys()
+		wantK, wantKHat := serpent1.makeSubkeys(makeLongkey(key[:n]))
+		for i := range wantK {
+			if K[i] != wantK[i] || KHat[i] != wantKHat[i] {
+				t.Errorf("%d-bit key: subkey %d differs from the key "+
+					"schedule\n", n, i)
+			}
+		}
+	}
+}
+
+// Function TestReducedRounds checks that the normal and bitslice
+// algorithms agree and invert for a reduced-round variant, both with the
+// standard and with a non-standard linear transformation.
+func TestReducedRounds(t *testing.T) {
+	reduced := Serpent1
+	reduced.StartRound = 5
+	reduced.Rounds = 3
+	swapped := reduced
+	swapped.LTTable, swapped.LTTableInverse = LTTableInverse, LTTable
+
+	for _, p := range []Params{reduced, swapped} {
+		c, err := New(p, makeLongkey(bs))
+		if err != nil {
+			t.Fatalf("New failed: %v\n", err)
+		}
+		normal := c.Encrypt(testPlainText)
+		if normal != c.EncryptBitslice(testPlainText) {
+			t.Errorf("normal and bitslice encryption differ\n")
+		}
+		if c.Decrypt(normal) != testPlainText {
+			t.Errorf("Decrypt does not yield plainText\n")
+		}
+		if c.DecryptBitslice(normal) != testPlainText {
+			t.Errorf("DecryptBitslice does not yield plainText\n")
+		}
+	}
+}
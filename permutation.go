@@ -1,7 +1,9 @@
 package serpent
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 )
 
 // The Initial and Final permutations are each represented by one list
@@ -20,46 +22,19 @@ var IPTable []int = []int{
 	28, 60, 92, 124, 29, 61, 93, 125, 30, 62, 94, 126, 31, 63, 95, 127,
 }
 
-var FPTable []int = []int{
-	0, 4, 8, 12, 16, 20, 24, 28, 32, 36, 40, 44, 48, 52, 56, 60,
-	64, 68, 72, 76, 80, 84, 88, 92, 96, 100, 104, 108, 112, 116, 120, 124,
-	1, 5, 9, 13, 17, 21, 25, 29, 33, 37, 41, 45, 49, 53, 57, 61,
-	65, 69, 73, 77, 81, 85, 89, 93, 97, 101, 105, 109, 113, 117, 121, 125,
-	2, 6, 10, 14, 18, 22, 26, 30, 34, 38, 42, 46, 50, 54, 58, 62,
-	66, 70, 74, 78, 82, 86, 90, 94, 98, 102, 106, 110, 114, 118, 122, 126,
-	3, 7, 11, 15, 19, 23, 27, 31, 35, 39, 43, 47, 51, 55, 59, 63,
-	67, 71, 75, 79, 83, 87, 91, 95, 99, 103, 107, 111, 115, 119, 123, 127,
-}
-
-// Function applyPermutation applies the permutation specified by the
-// 128-element list 'ptable' to the 128-bit Bitstring 'input' and return
-// a 128-bit Bitstring.
-func applyPermutation(ptable []int, input Bitstring) Bitstring {
-	ptlen := len(ptable)
-	iplen := len(input)
-	if iplen != ptlen {
-		fmt.Printf("Input size (%d) doesn't match ptable size "+
-			"(%d)\n", iplen, ptlen)
-	}
-	var result Bitstring
-	for i := 0; i < ptlen; i++ {
-		r := Bitstring(input[ptable[i]])
-		result = result + r
-	}
-
-	return result
-}
+// FPTable is the inverse of IPTable.
+var FPTable []int = Permutation(IPTable).Inverse()
 
 // Function IP applies the initial permutation table to the 128-bit
 // Bitstring 'input' and returns the result.
 func IP(input Bitstring) Bitstring {
-	return applyPermutation(IPTable, input)
+	return Permutation(IPTable).Apply(input)
 }
 
 // Function FP applies the final permutation table to the 128-bit Bitstring
 // 'input' and returns the result.
 func FP(input Bitstring) Bitstring {
-	return applyPermutation(FPTable, input)
+	return Permutation(FPTable).Apply(input)
 }
 
 // Function FPInverse applies the final permutation in reverse.
@@ -71,3 +46,260 @@ func FPInverse(output Bitstring) Bitstring {
 func IPInverse(output Bitstring) Bitstring {
 	return FP(output)
 }
+
+// Permutation is a permutation of the n positions 0..n-1, in the format
+// of IPTable: output position i takes input position p[i]. Read as a
+// map it sends i to p[i], which is how an SBox, a Permutation of the 16
+// nibble values, is read.
+type Permutation []int
+
+// Function IdentityPermutation returns the identity on 'n' positions.
+func IdentityPermutation(n int) Permutation {
+	p := make(Permutation, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+// Function RotationPermutation returns the permutation that rotates 'n'
+// bits towards the most significant bit by 'places' places, as
+// Bitstring.RotateLeft does.
+func RotationPermutation(n, places int) Permutation {
+	p := make(Permutation, n)
+	for i := range p {
+		p[i] = ((i-places)%n + n) % n
+	}
+	return p
+}
+
+// Method Validate checks that 'p' lists every position exactly once.
+func (p Permutation) Validate() error {
+	seen := make([]bool, len(p))
+	for i, j := range p {
+		if j < 0 || j >= len(p) {
+			return fmt.Errorf("serpent: position %d takes %d, out of "+
+				"range", i, j)
+		}
+		if seen[j] {
+			return fmt.Errorf("serpent: position %d is taken twice", j)
+		}
+		seen[j] = true
+	}
+	return nil
+}
+
+// Method Inverse returns the permutation that undoes 'p'. 'p' must be
+// valid.
+func (p Permutation) Inverse() Permutation {
+	inverse := make(Permutation, len(p))
+	for i, j := range p {
+		inverse[j] = i
+	}
+	return inverse
+}
+
+// Method Compose returns the permutation applying 'p' and then 'q', which
+// must have the same length. Read as maps it is p after q.
+func (p Permutation) Compose(q Permutation) Permutation {
+	if len(p) != len(q) {
+		fmt.Printf("cannot compose permutations of different lengths\n")
+	}
+	r := make(Permutation, len(q))
+	for i, j := range q {
+		r[i] = p[j]
+	}
+	return r
+}
+
+// Method IsIdentity reports whether 'p' leaves every position in place.
+func (p Permutation) IsIdentity() bool {
+	for i, j := range p {
+		if i != j {
+			return false
+		}
+	}
+	return true
+}
+
+// Method Cycles returns the cycles of 'p' read as a map, each starting at
+// its smallest position, in increasing order of that position. Fixed
+// points are cycles of length 1.
+func (p Permutation) Cycles() [][]int {
+	var cycles [][]int
+	seen := make([]bool, len(p))
+	for start := range p {
+		if seen[start] {
+			continue
+		}
+		var cycle []int
+		for i := start; !seen[i]; i = p[i] {
+			seen[i] = true
+			cycle = append(cycle, i)
+		}
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// Method Apply permutes the bits of 'input', which must have as many bits
+// as 'p' has positions.
+func (p Permutation) Apply(input Bitstring) Bitstring {
+	if len(input) != len(p) {
+		fmt.Printf("Input size (%d) doesn't match ptable size "+
+			"(%d)\n", len(input), len(p))
+	}
+	result := make([]byte, len(p))
+	for i, j := range p {
+		result[i] = input[j]
+	}
+	return Bitstring(result)
+}
+
+// Method ApplyBytes permutes the bits of 'src' into 'dst', bit j of byte
+// i being position 8*i+j. Both must hold the positions of 'p', and they
+// must not overlap.
+func (p Permutation) ApplyBytes(dst, src []byte) {
+	for i := range dst[:(len(p)+7)/8] {
+		dst[i] = 0
+	}
+	for i, j := range p {
+		dst[i/8] |= (src[j/8] >> uint(j%8) & 1) << uint(i%8)
+	}
+}
+
+// Method ApplyWords permutes the bits of 'src' into 'dst', bit j of word
+// k being position 32*k+j as in the word core. Both must hold the
+// positions of 'p', and they must not overlap.
+func (p Permutation) ApplyWords(dst, src []uint32) {
+	for i := range dst[:(len(p)+31)/32] {
+		dst[i] = 0
+	}
+	for i, j := range p {
+		dst[i/32] |= (src[j/32] >> uint(j%32) & 1) << uint(i%32)
+	}
+}
+
+// A CompiledPermutation applies a Permutation to 64-bit words, bit j of
+// word k being position 64*k+j. Bits that move together, between the same
+// words by the same distance, are moved by one mask and shift step. When
+// that takes more steps than there are bytes, as for a transposition such
+// as IP where every bit moves by its own distance, the bytes of the input
+// are looked up in tables instead.
+type CompiledPermutation struct {
+	n     int
+	steps []permutationStep
+	// table[(256*b+v)*words+k] is word k of the output for input byte b
+	// holding v, when the tables are used.
+	table []uint64
+}
+
+// A permutationStep moves the bits 'mask' of source word 'src' by 'shift'
+// places, left when positive, into destination word 'dst'.
+type permutationStep struct {
+	src, dst int
+	shift    int
+	mask     uint64
+}
+
+// Method Compile builds the steps of 'p', and its tables if they are
+// cheaper. 'p' must be valid.
+func (p Permutation) Compile() *CompiledPermutation {
+	c := &CompiledPermutation{n: len(p)}
+	index := map[[3]int]int{}
+	for i, j := range p {
+		key := [3]int{j / 64, i / 64, i%64 - j%64}
+		k, ok := index[key]
+		if !ok {
+			k = len(c.steps)
+			index[key] = k
+			c.steps = append(c.steps, permutationStep{src: key[0],
+				dst: key[1], shift: key[2]})
+		}
+		c.steps[k].mask |= 1 << uint(j%64)
+	}
+	bytes, words := (c.n+7)/8, (c.n+63)/64
+	if len(c.steps) <= bytes {
+		return c
+	}
+	inverse := p.Inverse()
+	c.table = make([]uint64, 256*bytes*words)
+	for b := 0; b < bytes; b++ {
+		for v := 0; v < 256; v++ {
+			row := c.table[(256*b+v)*words:]
+			for l := 0; l < 8 && 8*b+l < c.n; l++ {
+				if v>>uint(l)&1 == 1 {
+					i := inverse[8*b+l]
+					row[i/64] |= 1 << uint(i%64)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Method Steps returns the number of mask and shift steps, or of table
+// lookups, that Apply performs.
+func (c *CompiledPermutation) Steps() int {
+	if c.table != nil {
+		return (c.n + 7) / 8
+	}
+	return len(c.steps)
+}
+
+// Method Apply permutes the bits of 'src' into 'dst', which must hold the
+// positions of the permutation and must not overlap.
+func (c *CompiledPermutation) Apply(dst, src []uint64) {
+	words := (c.n + 63) / 64
+	for i := range dst[:words] {
+		dst[i] = 0
+	}
+	if c.table != nil {
+		for b := 0; b < (c.n+7)/8; b++ {
+			v := int(src[b/8] >> uint(8*(b%8)) & 0xff)
+			row := c.table[(256*b+v)*words:]
+			for k := range dst[:words] {
+				dst[k] |= row[k]
+			}
+		}
+		return
+	}
+	for _, s := range c.steps {
+		x := src[s.src] & s.mask
+		if s.shift >= 0 {
+			dst[s.dst] |= x << uint(s.shift)
+		} else {
+			dst[s.dst] |= x >> uint(-s.shift)
+		}
+	}
+}
+
+// Method WriteGo writes the mask and shift steps of 'c' as a Go function
+// 'name' taking the destination and source words as arrays. The tables are
+// not written.
+func (c *CompiledPermutation) WriteGo(w io.Writer, name string) error {
+	bw := bufio.NewWriter(w)
+	words := (c.n + 63) / 64
+	fmt.Fprintf(bw, "func %s(dst, src *[%d]uint64) {\n", name, words)
+	for d := 0; d < words; d++ {
+		fmt.Fprintf(bw, "\tdst[%d] = 0", d)
+		for _, s := range c.steps {
+			if s.dst != d {
+				continue
+			}
+			switch {
+			case s.shift > 0:
+				fmt.Fprintf(bw, " |\n\t\tsrc[%d]&0x%016x<<%d", s.src, s.mask,
+					s.shift)
+			case s.shift < 0:
+				fmt.Fprintf(bw, " |\n\t\tsrc[%d]&0x%016x>>%d", s.src, s.mask,
+					-s.shift)
+			default:
+				fmt.Fprintf(bw, " |\n\t\tsrc[%d]&0x%016x", s.src, s.mask)
+			}
+		}
+		fmt.Fprintf(bw, "\n")
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,139 @@
+package serpent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// The final permutation as given in the Serpent specification.
+var specFPTable = []int{
+	0, 4, 8, 12, 16, 20, 24, 28, 32, 36, 40, 44, 48, 52, 56, 60,
+	64, 68, 72, 76, 80, 84, 88, 92, 96, 100, 104, 108, 112, 116, 120, 124,
+	1, 5, 9, 13, 17, 21, 25, 29, 33, 37, 41, 45, 49, 53, 57, 61,
+	65, 69, 73, 77, 81, 85, 89, 93, 97, 101, 105, 109, 113, 117, 121, 125,
+	2, 6, 10, 14, 18, 22, 26, 30, 34, 38, 42, 46, 50, 54, 58, 62,
+	66, 70, 74, 78, 82, 86, 90, 94, 98, 102, 106, 110, 114, 118, 122, 126,
+	3, 7, 11, 15, 19, 23, 27, 31, 35, 39, 43, 47, 51, 55, 59, 63,
+	67, 71, 75, 79, 83, 87, 91, 95, 99, 103, 107, 111, 115, 119, 123, 127,
+}
+
+// Function TestPermutation checks the derived final permutation and the
+// algebra of permutations.
+func TestPermutation(t *testing.T) {
+	for i := range specFPTable {
+		if FPTable[i] != specFPTable[i] {
+			t.Fatalf("FPTable[%d] = %d, want %d", i, FPTable[i],
+				specFPTable[i])
+		}
+	}
+	ip := Permutation(IPTable)
+	if err := ip.Validate(); err != nil {
+		t.Errorf("IPTable: %v", err)
+	}
+	if !ip.Compose(ip.Inverse()).IsIdentity() ||
+		!ip.Inverse().Compose(ip).IsIdentity() {
+		t.Errorf("Inverse does not invert IP\n")
+	}
+	if ip.IsIdentity() || !IdentityPermutation(5).IsIdentity() {
+		t.Errorf("IsIdentity is wrong\n")
+	}
+	for _, bad := range []Permutation{{0, 2}, {1, 1}, {-1, 0}} {
+		if bad.Validate() == nil {
+			t.Errorf("%v is accepted\n", bad)
+		}
+	}
+
+	// Composition applies the first permutation first.
+	a, b := RotationPermutation(6, 1), Permutation{1, 0, 2, 3, 4, 5}
+	var s Bitstring = "110100"
+	if got, want := a.Compose(b).Apply(s), b.Apply(a.Apply(s)); got != want {
+		t.Errorf("Compose gives %s, want %s\n", got, want)
+	}
+	for places := 0; places <= 6; places++ {
+		if got := RotationPermutation(6, places).Apply(s); got !=
+			s.RotateLeft(places) {
+			t.Errorf("rotation by %d gives %s, want %s\n", places, got,
+				s.RotateLeft(places))
+		}
+		if got := RotationPermutation(6, -places).Apply(s); got !=
+			s.RotateRight(places) {
+			t.Errorf("rotation by %d gives %s, want %s\n", -places, got,
+				s.RotateRight(places))
+		}
+	}
+
+	cycles := RotationPermutation(6, 2).Cycles()
+	if len(cycles) != 2 || len(cycles[0]) != 3 || cycles[1][0] != 1 {
+		t.Errorf("Cycles gives %v\n", cycles)
+	}
+	var fixed []int
+	for _, c := range Permutation(SBoxDecimalTable[3]).Cycles() {
+		if len(c) == 1 {
+			fixed = append(fixed, c[0])
+		}
+	}
+	want := SBoxDecimalTable[3].FixedPoints()
+	if len(fixed) != len(want) {
+		t.Errorf("the 1-cycles of S3 are %v, want %v\n", fixed, want)
+	}
+}
+
+// Function TestPermutationApply checks the byte, word and compiled forms
+// against Apply on random blocks.
+func TestPermutationApply(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, p := range []Permutation{IPTable, FPTable,
+		RotationPermutation(128, 13),
+		Permutation(IPTable).Compose(RotationPermutation(128, 70))} {
+		c := p.Compile()
+		for i := 0; i < 20; i++ {
+			in := randomBytes(r, 16)
+			want := p.Apply(BitstringFromBytes(in))
+
+			out := make([]byte, 16)
+			p.ApplyBytes(out, in)
+			if got := BitstringFromBytes(out); got != want {
+				t.Fatalf("ApplyBytes gives %s, want %s", got, want)
+			}
+
+			x := loadWords(in)
+			var y [4]uint32
+			p.ApplyWords(y[:], x[:])
+			if got := wordsToBitstring(&y); got != want {
+				t.Fatalf("ApplyWords gives %s, want %s", got, want)
+			}
+
+			src := []uint64{binary.LittleEndian.Uint64(in),
+				binary.LittleEndian.Uint64(in[8:])}
+			dst := make([]uint64, 2)
+			c.Apply(dst, src)
+			binary.LittleEndian.PutUint64(out, dst[0])
+			binary.LittleEndian.PutUint64(out[8:], dst[1])
+			if got := BitstringFromBytes(out); got != want {
+				t.Fatalf("the compiled permutation gives %s, want %s",
+					got, want)
+			}
+		}
+	}
+	if n := Permutation(IPTable).Compile().Steps(); n != 16 {
+		t.Errorf("IP compiles to %d steps, want 16 lookups\n", n)
+	}
+	if n := RotationPermutation(128, 13).Compile().Steps(); n != 4 {
+		t.Errorf("a rotation compiles to %d steps, want 4\n", n)
+	}
+
+	var buf bytes.Buffer
+	if err := RotationPermutation(64, 3).Compile().WriteGo(&buf,
+		"rotate3"); err != nil {
+		t.Fatalf("WriteGo failed: %v", err)
+	}
+	want := "func rotate3(dst, src *[1]uint64) {\n" +
+		"\tdst[0] = 0 |\n" +
+		"\t\tsrc[0]&0xe000000000000000>>61 |\n" +
+		"\t\tsrc[0]&0x1fffffffffffffff<<3\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteGo gives\n%s\nwant\n%s\n", got, want)
+	}
+}
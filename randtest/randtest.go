@@ -0,0 +1,183 @@
+// Package randtest implements a subset of the NIST SP 800-22 statistical
+// tests for random number generators: frequency, block frequency, runs,
+// longest run of ones, binary matrix rank, discrete Fourier transform,
+// approximate entropy and cumulative sums.
+//
+// Sequences are slices of bits, each 0 or 1. Every test returns p-values;
+// a sequence passes a test when all its p-values are at least Alpha.
+package randtest
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Alpha is the significance level recommended by SP 800-22.
+const Alpha = 0.01
+
+// Result is the outcome of one test over a sequence.
+type Result struct {
+	Name    string
+	PValues []float64
+	// Err is set when the sequence is unsuitable for the test, usually
+	// because it is too short.
+	Err error
+}
+
+// Method Pass reports whether the test ran and every p-value is at least
+// Alpha.
+func (r Result) Pass() bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, p := range r.PValues {
+		if p < Alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// Battery runs every test with the given parameters.
+type Battery struct {
+	// BlockLength is the block length M of the block frequency test;
+	// zero means 128.
+	BlockLength int
+	// EntropyLength is the length m of the approximate entropy test;
+	// zero means the largest value below log2(n)-5, at most 10.
+	EntropyLength int
+}
+
+// Method Run runs the battery over the sequence 'e'.
+func (b Battery) Run(e []uint8) []Result {
+	blockLength := b.BlockLength
+	if blockLength == 0 {
+		blockLength = 128
+	}
+	entropyLength := b.EntropyLength
+	if entropyLength == 0 {
+		entropyLength = int(math.Log2(float64(len(e)))) - 6
+		if entropyLength > 10 {
+			entropyLength = 10
+		}
+		if entropyLength < 1 {
+			entropyLength = 1
+		}
+	}
+	one := func(name string, p float64, err error) Result {
+		return Result{Name: name, PValues: []float64{p}, Err: err}
+	}
+	var results []Result
+	p, err := Frequency(e)
+	results = append(results, one("frequency", p, err))
+	p, err = BlockFrequency(e, blockLength)
+	results = append(results, one("block frequency", p, err))
+	p, err = Runs(e)
+	results = append(results, one("runs", p, err))
+	p, err = LongestRun(e)
+	results = append(results, one("longest run", p, err))
+	p, err = Rank(e)
+	results = append(results, one("rank", p, err))
+	p, err = Spectral(e)
+	results = append(results, one("dft", p, err))
+	p, err = ApproximateEntropy(e, entropyLength)
+	results = append(results, one("approximate entropy", p, err))
+	forward, backward, err := CumulativeSums(e)
+	results = append(results, Result{Name: "cumulative sums",
+		PValues: []float64{forward, backward}, Err: err})
+	return results
+}
+
+// Function WriteResults writes one line per result.
+func WriteResults(w io.Writer, results []Result) error {
+	for _, r := range results {
+		status := "PASS"
+		if r.Err != nil {
+			status = "SKIP " + r.Err.Error()
+		} else if !r.Pass() {
+			status = "FAIL"
+		}
+		line := fmt.Sprintf("%-20s", r.Name)
+		for _, p := range r.PValues {
+			line += fmt.Sprintf(" %.6f", p)
+		}
+		if _, err := fmt.Fprintf(w, "%-45s %s\n", line, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Function Bits expands bytes into bits, least significant bit of each
+// byte first.
+func Bits(data []byte) []uint8 {
+	e := make([]uint8, 0, 8*len(data))
+	for _, c := range data {
+		for j := uint(0); j < 8; j++ {
+			e = append(e, (c>>j)&1)
+		}
+	}
+	return e
+}
+
+// Function igamc is the complemented incomplete gamma function Q(a, x).
+func igamc(a, x float64) float64 {
+	if x <= 0 || a <= 0 {
+		return 1
+	}
+	if x < a+1 {
+		return 1 - igamSeries(a, x)
+	}
+	return igamcFraction(a, x)
+}
+
+// Function igamSeries evaluates the regularized lower incomplete gamma
+// function P(a, x) by its series, suitable for x < a+1.
+func igamSeries(a, x float64) float64 {
+	lg, _ := math.Lgamma(a)
+	sum, term := 1/a, 1/a
+	for n := 1; n < 1000; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if math.Abs(term) < math.Abs(sum)*1e-15 {
+			break
+		}
+	}
+	return sum * math.Exp(-x+a*math.Log(x)-lg)
+}
+
+// Function igamcFraction evaluates Q(a, x) by its continued fraction,
+// suitable for x >= a+1, using the modified Lentz method.
+func igamcFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	lg, _ := math.Lgamma(a)
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lg) * h
+}
+
+// Function normalCDF is the standard normal cumulative distribution.
+func normalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
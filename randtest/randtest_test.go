@@ -0,0 +1,106 @@
+package randtest
+
+import (
+	"crypto/cipher"
+	"math"
+	"testing"
+
+	"github.com/JonPulfer/serpent"
+)
+
+// The examples of SP 800-22 section 2.
+const (
+	example100 = "11001001000011111101101010100010001000010110100011" +
+		"00001000110100110001001100011001100010100010111000"
+	example128 = "11001100000101010110110001001100111000000000001001" +
+		"00110101010001000100111101011010000000110101111100" +
+		"1100111001101101100010110010"
+)
+
+func parse(s string) []uint8 {
+	e := make([]uint8, len(s))
+	for i := range s {
+		e[i] = s[i] - '0'
+	}
+	return e
+}
+
+func near(t *testing.T, name string, got, want float64) {
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s: p-value %.6f, want %.6f\n", name, got, want)
+	}
+}
+
+// Function TestExamples checks the tests against the worked examples of
+// SP 800-22.
+func TestExamples(t *testing.T) {
+	e := parse(example100)
+	p, _ := Frequency(e)
+	near(t, "frequency", p, 0.109599)
+	p, _ = BlockFrequency(e, 10)
+	near(t, "block frequency", p, 0.706438)
+	p, _ = Runs(e)
+	near(t, "runs", p, 0.500798)
+	p, _ = LongestRun(parse(example128))
+	near(t, "longest run", p, 0.180609)
+	// The examples of these two tests are shorter than they require.
+	near(t, "dft", spectral(e), 0.646355)
+	near(t, "approximate entropy", approximateEntropy(e, 2), 0.235301)
+	forward, backward, _ := CumulativeSums(e)
+	near(t, "cumulative sums forward", forward, 0.219194)
+	near(t, "cumulative sums backward", backward, 0.114866)
+	// The rank example rounds the probabilities to 4 digits.
+	p, _ = rank(parse("01011001001010101101"), 3, 3)
+	if math.Abs(p-0.741948) > 1e-4 {
+		t.Errorf("rank: p-value %.6f, want 0.741948\n", p)
+	}
+}
+
+// Function TestShortSequences checks that the battery reports sequences
+// too short for a test instead of running it.
+func TestShortSequences(t *testing.T) {
+	for _, n := range []int{0, 1, 99} {
+		e := make([]uint8, n)
+		for i := range e {
+			e[i] = uint8(i % 3 % 2)
+		}
+		for _, result := range (Battery{}).Run(e) {
+			if result.Err == nil {
+				t.Errorf("%d bits: %s runs\n", n, result.Name)
+			}
+		}
+	}
+	if _, err := Spectral(make([]uint8, 999)); err == nil {
+		t.Errorf("999 bits: dft runs\n")
+	}
+	if _, err := ApproximateEntropy(make([]uint8, 1000), 4); err == nil {
+		t.Errorf("1000 bits: approximate entropy of length 4 runs\n")
+	}
+}
+
+// Function TestBattery checks that a Serpent CTR keystream passes and a
+// periodic sequence fails.
+func TestBattery(t *testing.T) {
+	b, _ := serpent.NewCipher(make([]byte, 16))
+	data := make([]byte, 1<<14)
+	cipher.NewCTR(b, make([]byte, serpent.BlockSize)).XORKeyStream(data,
+		data)
+	for _, result := range (Battery{}).Run(Bits(data)) {
+		if !result.Pass() {
+			t.Errorf("keystream fails %s: %v %v\n", result.Name,
+				result.PValues, result.Err)
+		}
+	}
+	for i := range data {
+		data[i] = 0x55
+	}
+	failed := 0
+	for _, result := range (Battery{}).Run(Bits(data)) {
+		if !result.Pass() {
+			failed++
+		}
+	}
+	if failed < 4 {
+		t.Errorf("periodic data fails only %d tests\n", failed)
+	}
+}
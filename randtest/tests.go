@@ -0,0 +1,370 @@
+package randtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/JonPulfer/serpent/gf2"
+)
+
+// Function Frequency is the frequency (monobit) test: the proportion of
+// ones should be close to 1/2. It needs at least 100 bits.
+func Frequency(e []uint8) (float64, error) {
+	if len(e) < 100 {
+		return 0, errors.New("randtest: frequency needs at least 100 bits")
+	}
+	s := 0
+	for _, b := range e {
+		s += 2*int(b) - 1
+	}
+	sObs := math.Abs(float64(s)) / math.Sqrt(float64(len(e)))
+	return math.Erfc(sObs / math.Sqrt2), nil
+}
+
+// Function BlockFrequency is the frequency test within blocks of 'm'
+// bits.
+func BlockFrequency(e []uint8, m int) (float64, error) {
+	n := len(e) / m
+	if m < 1 || n < 1 {
+		return 0, fmt.Errorf("randtest: block frequency needs at least "+
+			"one block of %d bits", m)
+	}
+	chi := 0.0
+	for i := 0; i < n; i++ {
+		ones := 0
+		for _, b := range e[i*m : (i+1)*m] {
+			ones += int(b)
+		}
+		pi := float64(ones)/float64(m) - 0.5
+		chi += pi * pi
+	}
+	chi *= 4 * float64(m)
+	return igamc(float64(n)/2, chi/2), nil
+}
+
+// Function Runs is the runs test: the number of uninterrupted runs of
+// identical bits should be as expected. It needs at least 100 bits.
+func Runs(e []uint8) (float64, error) {
+	if len(e) < 100 {
+		return 0, errors.New("randtest: runs needs at least 100 bits")
+	}
+	n := float64(len(e))
+	ones := 0
+	for _, b := range e {
+		ones += int(b)
+	}
+	pi := float64(ones) / n
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		// The frequency test fails, so this one is not run.
+		return 0, nil
+	}
+	v := 1
+	for k := 1; k < len(e); k++ {
+		if e[k] != e[k-1] {
+			v++
+		}
+	}
+	num := math.Abs(float64(v) - 2*n*pi*(1-pi))
+	return math.Erfc(num / (2 * math.Sqrt(2*n) * pi * (1 - pi))), nil
+}
+
+// The longest run parameters of SP 800-22 section 2.4 for increasing
+// sequence lengths: block length, smallest and largest class and class
+// probabilities.
+var longestRunParams = []struct {
+	minLength, m, low, high int
+	pi                      []float64
+}{
+	{750000, 10000, 10, 16,
+		[]float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}},
+	{6272, 128, 4, 9, []float64{0.1174035788, 0.242955959, 0.249363483,
+		0.17517706, 0.102701071, 0.112398847}},
+	{128, 8, 1, 4, []float64{0.21484375, 0.3671875, 0.23046875, 0.1875}},
+}
+
+// Function LongestRun is the test for the longest run of ones in a block.
+// It needs at least 128 bits.
+func LongestRun(e []uint8) (float64, error) {
+	for _, p := range longestRunParams {
+		if len(e) < p.minLength {
+			continue
+		}
+		n := len(e) / p.m
+		counts := make([]int, len(p.pi))
+		for i := 0; i < n; i++ {
+			longest, run := 0, 0
+			for _, b := range e[i*p.m : (i+1)*p.m] {
+				if b == 1 {
+					run++
+					if run > longest {
+						longest = run
+					}
+				} else {
+					run = 0
+				}
+			}
+			if longest < p.low {
+				longest = p.low
+			}
+			if longest > p.high {
+				longest = p.high
+			}
+			counts[longest-p.low]++
+		}
+		chi := 0.0
+		for i, pi := range p.pi {
+			expected := float64(n) * pi
+			d := float64(counts[i]) - expected
+			chi += d * d / expected
+		}
+		return igamc(float64(len(p.pi)-1)/2, chi/2), nil
+	}
+	return 0, errors.New("randtest: longest run needs at least 128 bits")
+}
+
+// Function Rank is the binary matrix rank test over 32x32 matrices. It
+// needs at least 38 matrices.
+func Rank(e []uint8) (float64, error) {
+	return rank(e, 32, 32)
+}
+
+// Function rank runs the rank test over m x q matrices. The rank
+// probabilities are always those of 32x32 matrices, as in the reference
+// implementation and the small example of SP 800-22.
+func rank(e []uint8, m, q int) (float64, error) {
+	n := len(e) / (m * q)
+	if n < 38 && m == 32 {
+		return 0, errors.New("randtest: rank needs at least 38 matrices " +
+			"of 32x32 bits")
+	}
+	full, minus1 := 0, 0
+	for k := 0; k < n; k++ {
+		a := gf2.New(m, q)
+		for i := 0; i < m; i++ {
+			for j := 0; j < q; j++ {
+				a.Set(i, j, int(e[k*m*q+i*q+j]))
+			}
+		}
+		switch a.Rank() {
+		case m:
+			full++
+		case m - 1:
+			minus1++
+		}
+	}
+	pFull, pMinus1 := rankProbability(32, 32, 32), rankProbability(32, 32, 31)
+	pRest := 1 - pFull - pMinus1
+	nf := float64(n)
+	chi := sq(float64(full)-pFull*nf)/(pFull*nf) +
+		sq(float64(minus1)-pMinus1*nf)/(pMinus1*nf) +
+		sq(float64(n-full-minus1)-pRest*nf)/(pRest*nf)
+	return math.Exp(-chi / 2), nil
+}
+
+// Function rankProbability returns the probability that a random m x q
+// binary matrix has rank r.
+func rankProbability(m, q, r int) float64 {
+	p := math.Pow(2, float64(r*(q+m-r)-m*q))
+	for i := 0; i < r; i++ {
+		p *= (1 - math.Pow(2, float64(i-q))) * (1 - math.Pow(2, float64(i-m))) /
+			(1 - math.Pow(2, float64(i-r)))
+	}
+	return p
+}
+
+func sq(x float64) float64 { return x * x }
+
+// Function Spectral is the discrete Fourier transform test: few peaks of
+// the spectrum should exceed the 95% threshold. It needs at least 1000
+// bits.
+func Spectral(e []uint8) (float64, error) {
+	if len(e) < 1000 {
+		return 0, errors.New("randtest: dft needs at least 1000 bits")
+	}
+	return spectral(e), nil
+}
+
+// Function spectral runs the discrete Fourier transform test over a
+// sequence of any length, as in the small example of SP 800-22.
+func spectral(e []uint8) float64 {
+	n := len(e)
+	x := make([]complex128, n)
+	for i, b := range e {
+		x[i] = complex(float64(2*int(b)-1), 0)
+	}
+	f := dft(x)
+	threshold := math.Sqrt(math.Log(1/0.05) * float64(n))
+	n1 := 0
+	for _, c := range f[:n/2] {
+		if cmplx.Abs(c) < threshold {
+			n1++
+		}
+	}
+	n0 := 0.95 * float64(n) / 2
+	d := (float64(n1) - n0) / math.Sqrt(float64(n)*0.95*0.05/4)
+	return math.Erfc(math.Abs(d) / math.Sqrt2)
+}
+
+// Function dft computes the discrete Fourier transform of 'x', of any
+// length, with Bluestein's algorithm over power of two FFTs.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	if n&(n-1) == 0 {
+		return fft(append([]complex128(nil), x...), false)
+	}
+	size := 1
+	for size < 2*n-1 {
+		size <<= 1
+	}
+	// w[k] = exp(-i pi k^2 / n), with k^2 reduced mod 2n for precision.
+	w := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		kk := (int64(k) * int64(k)) % int64(2*n)
+		w[k] = cmplx.Exp(complex(0, -math.Pi*float64(kk)/float64(n)))
+	}
+	a := make([]complex128, size)
+	b := make([]complex128, size)
+	for k := 0; k < n; k++ {
+		a[k] = x[k] * w[k]
+		b[k] = cmplx.Conj(w[k])
+		if k > 0 {
+			b[size-k] = cmplx.Conj(w[k])
+		}
+	}
+	fa, fb := fft(a, false), fft(b, false)
+	for i := range fa {
+		fa[i] *= fb[i]
+	}
+	conv := fft(fa, true)
+	result := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		result[k] = conv[k] * w[k]
+	}
+	return result
+}
+
+// Function fft transforms 'x' in place, its length being a power of two.
+// The inverse transform is scaled by 1/len(x).
+func fft(x []complex128, inverse bool) []complex128 {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for length := 2; length <= n; length <<= 1 {
+		step := cmplx.Exp(complex(0, sign*2*math.Pi/float64(length)))
+		for i := 0; i < n; i += length {
+			w := complex(1, 0)
+			for k := 0; k < length/2; k++ {
+				u, v := x[i+k], x[i+k+length/2]*w
+				x[i+k], x[i+k+length/2] = u+v, u-v
+				w *= step
+			}
+		}
+	}
+	if inverse {
+		for i := range x {
+			x[i] /= complex(float64(n), 0)
+		}
+	}
+	return x
+}
+
+// Function ApproximateEntropy compares the frequencies of overlapping
+// patterns of 'm' and m+1 bits. It needs m < log2(n)-5, that is at least
+// 2^(m+6) bits.
+func ApproximateEntropy(e []uint8, m int) (float64, error) {
+	if m < 1 || m > 24 || len(e) < 1<<uint(m+6) {
+		return 0, fmt.Errorf("randtest: approximate entropy of length %d "+
+			"needs at least %d bits", m, 1<<uint(m+6))
+	}
+	return approximateEntropy(e, m), nil
+}
+
+// Function approximateEntropy runs the approximate entropy test over a
+// sequence of any length, as in the small example of SP 800-22.
+func approximateEntropy(e []uint8, m int) float64 {
+	n := float64(len(e))
+	apEn := phi(e, m) - phi(e, m+1)
+	chi := 2 * n * (math.Log(2) - apEn)
+	return igamc(math.Pow(2, float64(m-1)), chi/2)
+}
+
+// Function phi returns the sum of C log C over the frequencies C of the
+// patterns of 'm' bits, the sequence wrapping around.
+func phi(e []uint8, m int) float64 {
+	if m == 0 {
+		return 0
+	}
+	n := len(e)
+	counts := make([]int, 1<<uint(m))
+	for i := 0; i < n; i++ {
+		pattern := 0
+		for k := 0; k < m; k++ {
+			pattern = pattern<<1 | int(e[(i+k)%n])
+		}
+		counts[pattern]++
+	}
+	sum := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(n)
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+// Function CumulativeSums is the cumulative sums test, forward and
+// backward. It needs at least 100 bits.
+func CumulativeSums(e []uint8) (forward, backward float64, err error) {
+	n := len(e)
+	if n < 100 {
+		return 0, 0, errors.New("randtest: cumulative sums needs at least " +
+			"100 bits")
+	}
+	z := func(reverse bool) int {
+		s, max := 0, 0
+		for i := 0; i < n; i++ {
+			b := e[i]
+			if reverse {
+				b = e[n-1-i]
+			}
+			s += 2*int(b) - 1
+			if s > max {
+				max = s
+			} else if -s > max {
+				max = -s
+			}
+		}
+		return max
+	}
+	return cusumP(z(false), n), cusumP(z(true), n), nil
+}
+
+func cusumP(z, n int) float64 {
+	zf, sn := float64(z), math.Sqrt(float64(n))
+	sum1 := 0.0
+	for k := (-n/z + 1) / 4; k <= (n/z-1)/4; k++ {
+		kf := float64(k)
+		sum1 += normalCDF((4*kf+1)*zf/sn) - normalCDF((4*kf-1)*zf/sn)
+	}
+	sum2 := 0.0
+	for k := (-n/z - 3) / 4; k <= (n/z-1)/4; k++ {
+		kf := float64(k)
+		sum2 += normalCDF((4*kf+3)*zf/sn) - normalCDF((4*kf+1)*zf/sn)
+	}
+	return 1 - sum1 + sum2
+}
@@ -0,0 +1,91 @@
+package serpent
+
+import (
+	"fmt"
+)
+
+// Partial encryption runs a range of rounds over an intermediate state. The
+// state B_i is the 128-bit input of round i in bitslice format, so B_0 is
+// the plaintext, and for the variant the ciphertext is B_(last+1), last
+// being the index of the final round. Round 'last' is the special final
+// round, which mixes in the extra subkey instead of applying the linear
+// transformation.
+//
+// The normal algorithm keeps its state permuted, BHat_i = IP(B_i). The
+// normal versions below take and return B_i all the same, applying IP on
+// entry and FP on exit, so that both algorithms can be mixed freely and
+// Encrypt(p) equals EncryptRounds(p, StartRound, last+1).
+
+// Method EncryptRounds applies rounds 'from' to 'to'-1 to the state B_from
+// by the normal algorithm and returns B_to. An empty range returns the
+// state unchanged.
+func (c *Cipher) EncryptRounds(state Bitstring, from, to int) (Bitstring,
+	error) {
+	if err := c.checkRounds(state, from, to); err != nil {
+		return "", err
+	}
+	KHat := c.normalKeys()
+	BHat := IP(state)
+	for i := from; i < to; i++ {
+		BHat = c.v.r(i, BHat, KHat)
+	}
+	return FP(BHat), nil
+}
+
+// Method DecryptRounds undoes rounds 'to'-1 down to 'from' on the state
+// B_to by the normal algorithm and returns B_from.
+func (c *Cipher) DecryptRounds(state Bitstring, from, to int) (Bitstring,
+	error) {
+	if err := c.checkRounds(state, from, to); err != nil {
+		return "", err
+	}
+	KHat := c.normalKeys()
+	BHat := FPInverse(state)
+	for i := to - 1; i >= from; i-- {
+		BHat = c.v.rInverse(i, BHat, KHat)
+	}
+	return IPInverse(BHat), nil
+}
+
+// Method EncryptRoundsBitslice is EncryptRounds by the bitslice algorithm.
+func (c *Cipher) EncryptRoundsBitslice(state Bitstring, from,
+	to int) (Bitstring, error) {
+	if err := c.checkRounds(state, from, to); err != nil {
+		return "", err
+	}
+	K := c.bitsliceKeys()
+	for i := from; i < to; i++ {
+		state = c.v.rBitslice(i, state, K)
+	}
+	return state, nil
+}
+
+// Method DecryptRoundsBitslice is DecryptRounds by the bitslice algorithm.
+func (c *Cipher) DecryptRoundsBitslice(state Bitstring, from,
+	to int) (Bitstring, error) {
+	if err := c.checkRounds(state, from, to); err != nil {
+		return "", err
+	}
+	K := c.bitsliceKeys()
+	for i := to - 1; i >= from; i-- {
+		state = c.v.rBitsliceInverse(i, state, K)
+	}
+	return state, nil
+}
+
+// Method checkRounds checks that 'state' is a 128-bit Bitstring and that
+// rounds 'from' to 'to'-1 exist in the variant of 'c'.
+func (c *Cipher) checkRounds(state Bitstring, from, to int) error {
+	if len(state) != 128 {
+		return fmt.Errorf("serpent: state has %d bits, want 128",
+			len(state))
+	}
+	if err := checkBits(state); err != nil {
+		return err
+	}
+	if from < c.v.StartRound || to > c.v.lastRound()+1 || from > to {
+		return fmt.Errorf("serpent: rounds %d to %d are out of range "+
+			"%d to %d", from, to-1, c.v.StartRound, c.v.lastRound())
+	}
+	return nil
+}
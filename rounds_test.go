@@ -0,0 +1,77 @@
+package serpent
+
+import (
+	"testing"
+)
+
+// Function TestEncryptRounds splits the encryption at every round boundary
+// and checks that both algorithms agree on the intermediate states, that
+// the pieces compose to Encrypt and that DecryptRounds undoes them.
+func TestEncryptRounds(t *testing.T) {
+	c, err := New(Serpent1, bs)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	want := c.Encrypt(testPlainText)
+	for split := 0; split <= round; split += 8 {
+		mid, err := c.EncryptRounds(testPlainText, 0, split)
+		if err != nil {
+			t.Fatalf("EncryptRounds failed: %v", err)
+		}
+		midBitslice, _ := c.EncryptRoundsBitslice(testPlainText, 0, split)
+		if mid != midBitslice {
+			t.Errorf("the algorithms disagree after %d rounds\n", split)
+		}
+		got, _ := c.EncryptRoundsBitslice(mid, split, round)
+		if got != want {
+			t.Errorf("rounds split at %d do not give Encrypt\n", split)
+		}
+		back, _ := c.DecryptRounds(got, split, round)
+		if back != mid {
+			t.Errorf("DecryptRounds does not undo rounds %d to %d\n",
+				split, round-1)
+		}
+		back, _ = c.DecryptRoundsBitslice(back, 0, split)
+		if back != testPlainText {
+			t.Errorf("DecryptRoundsBitslice does not undo rounds 0 to %d\n",
+				split-1)
+		}
+	}
+
+	// The final round alone mixes in both of the last two subkeys.
+	K, _ := c.Subkeys()
+	state := BitstringFromBytes(make([]byte, 16))
+	got, _ := c.EncryptRoundsBitslice(state, round-1, round)
+	if got != RBitslice(round-1, state, K) {
+		t.Errorf("the final round differs from RBitslice\n")
+	}
+}
+
+// Function TestEncryptRoundsVariant checks a reduced-round variant that
+// starts at round 2 and the range checks.
+func TestEncryptRoundsVariant(t *testing.T) {
+	p := Serpent1
+	p.StartRound, p.Rounds = 2, 4
+	c, err := New(p, bs)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	got, err := c.EncryptRounds(testPlainText, 2, 6)
+	if err != nil {
+		t.Fatalf("EncryptRounds failed: %v", err)
+	}
+	if got != c.Encrypt(testPlainText) {
+		t.Errorf("all rounds do not give Encrypt\n")
+	}
+	if got, _ := c.EncryptRounds(testPlainText, 4, 4); got != testPlainText {
+		t.Errorf("an empty range changes the state\n")
+	}
+	for _, r := range [][2]int{{1, 4}, {2, 7}, {5, 4}} {
+		if _, err := c.EncryptRounds(testPlainText, r[0], r[1]); err == nil {
+			t.Errorf("rounds %d to %d are accepted\n", r[0], r[1])
+		}
+	}
+	if _, err := c.DecryptRoundsBitslice(testPlainText[1:], 2, 6); err == nil {
+		t.Errorf("a 127-bit state is accepted\n")
+	}
+}
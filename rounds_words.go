@@ -0,0 +1,2557 @@
+//go:build !nounrolled
+
+// Code generated by "go run -tags nounrolled ./internal/gen -o rounds_words.go"; DO NOT EDIT.
+
+package serpent
+
+import "math/bits"
+
+// The unrolled rounds are available.
+const haveUnrolled = true
+
+// Function encryptWordsUnrolled runs the 32 rounds of Serpent-1 over the
+// block 'x' using the subkeys 'K'.
+func encryptWordsUnrolled(x *[4]uint32, K [][4]uint32) {
+	_ = K[32]
+	x0, x1, x2, x3 := x[0], x[1], x[2], x[3]
+
+	// Round 0
+	x0 ^= K[0][0]
+	x1 ^= K[0][1]
+	x2 ^= K[0][2]
+	x3 ^= K[0][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w0 | w3
+		w6 := w4 ^ w5
+		w7 := w0 & w1
+		w8 := w1 ^ w7
+		w9 := w0 & w2
+		w10 := w8 ^ w9
+		w11 := w7 & w2
+		w12 := w10 ^ w11
+		w13 := w12 ^ w3
+		w14 := w1 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w0 ^ w14
+		w20 := w19 | w16
+		w21 := ^w9
+		w22 := w21 | w3
+		w23 := w20 ^ w22
+		w24 := w21 & w4
+		w25 := w24 ^ w23
+		w26 := w25 ^ w15
+		x0, x1, x2, x3 = w26, w23, w18, w6
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 1
+	x0 ^= K[1][0]
+	x1 ^= K[1][1]
+	x2 ^= K[1][2]
+	x3 ^= K[1][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w3
+		w5 := ^w1
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w2 & w3
+		w14 := w12 ^ w13
+		w15 := w0 & w2
+		w16 := w15 & w3
+		w17 := w14 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w8 ^ w7
+		w22 := w1 ^ w2
+		w23 := w22 | w14
+		w24 := w21 ^ w23
+		w25 := w3 & w8
+		w26 := w25 | w21
+		w27 := w15 ^ w26
+		x0, x1, x2, x3 = w20, w27, w7, w24
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 2
+	x0 ^= K[2][0]
+	x1 ^= K[2][1]
+	x2 ^= K[2][2]
+	x3 ^= K[2][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w2 & w0
+		w6 := w5 ^ w3
+		w7 := w4 ^ w6
+		w8 := w7 ^ w0
+		w9 := ^w6
+		w10 := w9 | w1
+		w11 := w8 ^ w10
+		w12 := w2 & w9
+		w13 := w0 ^ w4
+		w14 := w13 & w10
+		w15 := w12 | w14
+		w16 := w8 | w15
+		w17 := w16 ^ w12
+		x0, x1, x2, x3 = w7, w15, w17, w11
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 3
+	x0 ^= K[3][0]
+	x1 ^= K[3][1]
+	x2 ^= K[3][2]
+	x3 ^= K[3][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w0 ^ w4
+		w6 := w5 ^ w2
+		w7 := w4 & w2
+		w8 := w6 ^ w7
+		w9 := w8 ^ w3
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := w4 & w3
+		w13 := w11 ^ w12
+		w14 := w1 ^ w2
+		w15 := w5 | w3
+		w16 := w15 & w9
+		w17 := w14 ^ w16
+		w18 := w15 ^ w4
+		w19 := w18 ^ w8
+		w20 := w19 ^ w17
+		w21 := w15 ^ w11
+		w22 := w13 | w20
+		w23 := w21 ^ w22
+		x0, x1, x2, x3 = w23, w20, w13, w17
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 4
+	x0 ^= K[4][0]
+	x1 ^= K[4][1]
+	x2 ^= K[4][2]
+	x3 ^= K[4][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 | w1
+		w5 := w4 ^ w0
+		w6 := w1 | w0
+		w7 := w6 & w3
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 & w8
+		w11 := w1 & w2
+		w12 := w11 | w5
+		w13 := w10 ^ w12
+		w14 := w8 & w1
+		w15 := w14 ^ w2
+		w16 := ^w3
+		w17 := w16 | w0
+		w18 := w15 ^ w17
+		w19 := w14 ^ w9
+		w20 := w18 & w12
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w18, w21, w13, w8
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 5
+	x0 ^= K[5][0]
+	x1 ^= K[5][1]
+	x2 ^= K[5][2]
+	x3 ^= K[5][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w6 ^ w3
+		w8 := w0 & w3
+		w9 := w7 ^ w8
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := ^w11
+		w13 := w3 ^ w0
+		w14 := w13 ^ w1
+		w15 := w3 | w12
+		w16 := w14 ^ w15
+		w17 := w16 ^ w3
+		w18 := w13 ^ w6
+		w19 := w18 | w11
+		w20 := w17 ^ w19
+		w21 := w14 | w20
+		w22 := w21 ^ w18
+		x0, x1, x2, x3 = w12, w16, w20, w22
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 6
+	x0 ^= K[6][0]
+	x1 ^= K[6][1]
+	x2 ^= K[6][2]
+	x3 ^= K[6][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := ^w1
+		w5 := w0 & w3
+		w6 := w5 ^ w2
+		w7 := w4 ^ w6
+		w8 := w0 & w1
+		w9 := w1 ^ w8
+		w10 := w9 ^ w2
+		w11 := w0 & w2
+		w12 := w10 ^ w11
+		w13 := w8 & w2
+		w14 := w12 ^ w13
+		w15 := w14 ^ w3
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w1 & w2
+		w19 := w18 & w3
+		w20 := w17 ^ w19
+		w21 := w15 ^ w0
+		w22 := w20 | w14
+		w23 := w22 | w4
+		w24 := w21 ^ w23
+		w25 := w24 ^ w22
+		w26 := w25 | w8
+		w27 := w26 ^ w2
+		x0, x1, x2, x3 = w24, w7, w27, w20
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 7
+	x0 ^= K[7][0]
+	x1 ^= K[7][1]
+	x2 ^= K[7][2]
+	x3 ^= K[7][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 ^ w1
+		w5 := w4 ^ w2
+		w6 := w0 & w2
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 & w2
+		w10 := w7 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w3 | w8
+		w14 := w13 ^ w0
+		w15 := w2 | w12
+		w16 := w14 ^ w15
+		w17 := ^w2
+		w18 := w17 | w11
+		w19 := w1 | w5
+		w20 := w19 & w13
+		w21 := w18 ^ w20
+		w22 := w11 ^ w13
+		w23 := w22 & w21
+		w24 := w23 ^ w5
+		x0, x1, x2, x3 = w21, w16, w24, w12
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 8
+	x0 ^= K[8][0]
+	x1 ^= K[8][1]
+	x2 ^= K[8][2]
+	x3 ^= K[8][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w0 | w3
+		w6 := w4 ^ w5
+		w7 := w0 & w1
+		w8 := w1 ^ w7
+		w9 := w0 & w2
+		w10 := w8 ^ w9
+		w11 := w7 & w2
+		w12 := w10 ^ w11
+		w13 := w12 ^ w3
+		w14 := w1 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w0 ^ w14
+		w20 := w19 | w16
+		w21 := ^w9
+		w22 := w21 | w3
+		w23 := w20 ^ w22
+		w24 := w21 & w4
+		w25 := w24 ^ w23
+		w26 := w25 ^ w15
+		x0, x1, x2, x3 = w26, w23, w18, w6
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 9
+	x0 ^= K[9][0]
+	x1 ^= K[9][1]
+	x2 ^= K[9][2]
+	x3 ^= K[9][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w3
+		w5 := ^w1
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w2 & w3
+		w14 := w12 ^ w13
+		w15 := w0 & w2
+		w16 := w15 & w3
+		w17 := w14 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w8 ^ w7
+		w22 := w1 ^ w2
+		w23 := w22 | w14
+		w24 := w21 ^ w23
+		w25 := w3 & w8
+		w26 := w25 | w21
+		w27 := w15 ^ w26
+		x0, x1, x2, x3 = w20, w27, w7, w24
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 10
+	x0 ^= K[10][0]
+	x1 ^= K[10][1]
+	x2 ^= K[10][2]
+	x3 ^= K[10][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w2 & w0
+		w6 := w5 ^ w3
+		w7 := w4 ^ w6
+		w8 := w7 ^ w0
+		w9 := ^w6
+		w10 := w9 | w1
+		w11 := w8 ^ w10
+		w12 := w2 & w9
+		w13 := w0 ^ w4
+		w14 := w13 & w10
+		w15 := w12 | w14
+		w16 := w8 | w15
+		w17 := w16 ^ w12
+		x0, x1, x2, x3 = w7, w15, w17, w11
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 11
+	x0 ^= K[11][0]
+	x1 ^= K[11][1]
+	x2 ^= K[11][2]
+	x3 ^= K[11][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w0 ^ w4
+		w6 := w5 ^ w2
+		w7 := w4 & w2
+		w8 := w6 ^ w7
+		w9 := w8 ^ w3
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := w4 & w3
+		w13 := w11 ^ w12
+		w14 := w1 ^ w2
+		w15 := w5 | w3
+		w16 := w15 & w9
+		w17 := w14 ^ w16
+		w18 := w15 ^ w4
+		w19 := w18 ^ w8
+		w20 := w19 ^ w17
+		w21 := w15 ^ w11
+		w22 := w13 | w20
+		w23 := w21 ^ w22
+		x0, x1, x2, x3 = w23, w20, w13, w17
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 12
+	x0 ^= K[12][0]
+	x1 ^= K[12][1]
+	x2 ^= K[12][2]
+	x3 ^= K[12][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 | w1
+		w5 := w4 ^ w0
+		w6 := w1 | w0
+		w7 := w6 & w3
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 & w8
+		w11 := w1 & w2
+		w12 := w11 | w5
+		w13 := w10 ^ w12
+		w14 := w8 & w1
+		w15 := w14 ^ w2
+		w16 := ^w3
+		w17 := w16 | w0
+		w18 := w15 ^ w17
+		w19 := w14 ^ w9
+		w20 := w18 & w12
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w18, w21, w13, w8
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 13
+	x0 ^= K[13][0]
+	x1 ^= K[13][1]
+	x2 ^= K[13][2]
+	x3 ^= K[13][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w6 ^ w3
+		w8 := w0 & w3
+		w9 := w7 ^ w8
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := ^w11
+		w13 := w3 ^ w0
+		w14 := w13 ^ w1
+		w15 := w3 | w12
+		w16 := w14 ^ w15
+		w17 := w16 ^ w3
+		w18 := w13 ^ w6
+		w19 := w18 | w11
+		w20 := w17 ^ w19
+		w21 := w14 | w20
+		w22 := w21 ^ w18
+		x0, x1, x2, x3 = w12, w16, w20, w22
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 14
+	x0 ^= K[14][0]
+	x1 ^= K[14][1]
+	x2 ^= K[14][2]
+	x3 ^= K[14][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := ^w1
+		w5 := w0 & w3
+		w6 := w5 ^ w2
+		w7 := w4 ^ w6
+		w8 := w0 & w1
+		w9 := w1 ^ w8
+		w10 := w9 ^ w2
+		w11 := w0 & w2
+		w12 := w10 ^ w11
+		w13 := w8 & w2
+		w14 := w12 ^ w13
+		w15 := w14 ^ w3
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w1 & w2
+		w19 := w18 & w3
+		w20 := w17 ^ w19
+		w21 := w15 ^ w0
+		w22 := w20 | w14
+		w23 := w22 | w4
+		w24 := w21 ^ w23
+		w25 := w24 ^ w22
+		w26 := w25 | w8
+		w27 := w26 ^ w2
+		x0, x1, x2, x3 = w24, w7, w27, w20
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 15
+	x0 ^= K[15][0]
+	x1 ^= K[15][1]
+	x2 ^= K[15][2]
+	x3 ^= K[15][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 ^ w1
+		w5 := w4 ^ w2
+		w6 := w0 & w2
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 & w2
+		w10 := w7 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w3 | w8
+		w14 := w13 ^ w0
+		w15 := w2 | w12
+		w16 := w14 ^ w15
+		w17 := ^w2
+		w18 := w17 | w11
+		w19 := w1 | w5
+		w20 := w19 & w13
+		w21 := w18 ^ w20
+		w22 := w11 ^ w13
+		w23 := w22 & w21
+		w24 := w23 ^ w5
+		x0, x1, x2, x3 = w21, w16, w24, w12
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 16
+	x0 ^= K[16][0]
+	x1 ^= K[16][1]
+	x2 ^= K[16][2]
+	x3 ^= K[16][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w0 | w3
+		w6 := w4 ^ w5
+		w7 := w0 & w1
+		w8 := w1 ^ w7
+		w9 := w0 & w2
+		w10 := w8 ^ w9
+		w11 := w7 & w2
+		w12 := w10 ^ w11
+		w13 := w12 ^ w3
+		w14 := w1 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w0 ^ w14
+		w20 := w19 | w16
+		w21 := ^w9
+		w22 := w21 | w3
+		w23 := w20 ^ w22
+		w24 := w21 & w4
+		w25 := w24 ^ w23
+		w26 := w25 ^ w15
+		x0, x1, x2, x3 = w26, w23, w18, w6
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 17
+	x0 ^= K[17][0]
+	x1 ^= K[17][1]
+	x2 ^= K[17][2]
+	x3 ^= K[17][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w3
+		w5 := ^w1
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w2 & w3
+		w14 := w12 ^ w13
+		w15 := w0 & w2
+		w16 := w15 & w3
+		w17 := w14 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w8 ^ w7
+		w22 := w1 ^ w2
+		w23 := w22 | w14
+		w24 := w21 ^ w23
+		w25 := w3 & w8
+		w26 := w25 | w21
+		w27 := w15 ^ w26
+		x0, x1, x2, x3 = w20, w27, w7, w24
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 18
+	x0 ^= K[18][0]
+	x1 ^= K[18][1]
+	x2 ^= K[18][2]
+	x3 ^= K[18][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w2 & w0
+		w6 := w5 ^ w3
+		w7 := w4 ^ w6
+		w8 := w7 ^ w0
+		w9 := ^w6
+		w10 := w9 | w1
+		w11 := w8 ^ w10
+		w12 := w2 & w9
+		w13 := w0 ^ w4
+		w14 := w13 & w10
+		w15 := w12 | w14
+		w16 := w8 | w15
+		w17 := w16 ^ w12
+		x0, x1, x2, x3 = w7, w15, w17, w11
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 19
+	x0 ^= K[19][0]
+	x1 ^= K[19][1]
+	x2 ^= K[19][2]
+	x3 ^= K[19][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w0 ^ w4
+		w6 := w5 ^ w2
+		w7 := w4 & w2
+		w8 := w6 ^ w7
+		w9 := w8 ^ w3
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := w4 & w3
+		w13 := w11 ^ w12
+		w14 := w1 ^ w2
+		w15 := w5 | w3
+		w16 := w15 & w9
+		w17 := w14 ^ w16
+		w18 := w15 ^ w4
+		w19 := w18 ^ w8
+		w20 := w19 ^ w17
+		w21 := w15 ^ w11
+		w22 := w13 | w20
+		w23 := w21 ^ w22
+		x0, x1, x2, x3 = w23, w20, w13, w17
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 20
+	x0 ^= K[20][0]
+	x1 ^= K[20][1]
+	x2 ^= K[20][2]
+	x3 ^= K[20][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 | w1
+		w5 := w4 ^ w0
+		w6 := w1 | w0
+		w7 := w6 & w3
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 & w8
+		w11 := w1 & w2
+		w12 := w11 | w5
+		w13 := w10 ^ w12
+		w14 := w8 & w1
+		w15 := w14 ^ w2
+		w16 := ^w3
+		w17 := w16 | w0
+		w18 := w15 ^ w17
+		w19 := w14 ^ w9
+		w20 := w18 & w12
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w18, w21, w13, w8
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 21
+	x0 ^= K[21][0]
+	x1 ^= K[21][1]
+	x2 ^= K[21][2]
+	x3 ^= K[21][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w6 ^ w3
+		w8 := w0 & w3
+		w9 := w7 ^ w8
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := ^w11
+		w13 := w3 ^ w0
+		w14 := w13 ^ w1
+		w15 := w3 | w12
+		w16 := w14 ^ w15
+		w17 := w16 ^ w3
+		w18 := w13 ^ w6
+		w19 := w18 | w11
+		w20 := w17 ^ w19
+		w21 := w14 | w20
+		w22 := w21 ^ w18
+		x0, x1, x2, x3 = w12, w16, w20, w22
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 22
+	x0 ^= K[22][0]
+	x1 ^= K[22][1]
+	x2 ^= K[22][2]
+	x3 ^= K[22][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := ^w1
+		w5 := w0 & w3
+		w6 := w5 ^ w2
+		w7 := w4 ^ w6
+		w8 := w0 & w1
+		w9 := w1 ^ w8
+		w10 := w9 ^ w2
+		w11 := w0 & w2
+		w12 := w10 ^ w11
+		w13 := w8 & w2
+		w14 := w12 ^ w13
+		w15 := w14 ^ w3
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w1 & w2
+		w19 := w18 & w3
+		w20 := w17 ^ w19
+		w21 := w15 ^ w0
+		w22 := w20 | w14
+		w23 := w22 | w4
+		w24 := w21 ^ w23
+		w25 := w24 ^ w22
+		w26 := w25 | w8
+		w27 := w26 ^ w2
+		x0, x1, x2, x3 = w24, w7, w27, w20
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 23
+	x0 ^= K[23][0]
+	x1 ^= K[23][1]
+	x2 ^= K[23][2]
+	x3 ^= K[23][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 ^ w1
+		w5 := w4 ^ w2
+		w6 := w0 & w2
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 & w2
+		w10 := w7 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w3 | w8
+		w14 := w13 ^ w0
+		w15 := w2 | w12
+		w16 := w14 ^ w15
+		w17 := ^w2
+		w18 := w17 | w11
+		w19 := w1 | w5
+		w20 := w19 & w13
+		w21 := w18 ^ w20
+		w22 := w11 ^ w13
+		w23 := w22 & w21
+		w24 := w23 ^ w5
+		x0, x1, x2, x3 = w21, w16, w24, w12
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 24
+	x0 ^= K[24][0]
+	x1 ^= K[24][1]
+	x2 ^= K[24][2]
+	x3 ^= K[24][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w0 | w3
+		w6 := w4 ^ w5
+		w7 := w0 & w1
+		w8 := w1 ^ w7
+		w9 := w0 & w2
+		w10 := w8 ^ w9
+		w11 := w7 & w2
+		w12 := w10 ^ w11
+		w13 := w12 ^ w3
+		w14 := w1 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w0 ^ w14
+		w20 := w19 | w16
+		w21 := ^w9
+		w22 := w21 | w3
+		w23 := w20 ^ w22
+		w24 := w21 & w4
+		w25 := w24 ^ w23
+		w26 := w25 ^ w15
+		x0, x1, x2, x3 = w26, w23, w18, w6
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 25
+	x0 ^= K[25][0]
+	x1 ^= K[25][1]
+	x2 ^= K[25][2]
+	x3 ^= K[25][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w3
+		w5 := ^w1
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w2 & w3
+		w14 := w12 ^ w13
+		w15 := w0 & w2
+		w16 := w15 & w3
+		w17 := w14 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w8 ^ w7
+		w22 := w1 ^ w2
+		w23 := w22 | w14
+		w24 := w21 ^ w23
+		w25 := w3 & w8
+		w26 := w25 | w21
+		w27 := w15 ^ w26
+		x0, x1, x2, x3 = w20, w27, w7, w24
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 26
+	x0 ^= K[26][0]
+	x1 ^= K[26][1]
+	x2 ^= K[26][2]
+	x3 ^= K[26][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w2 & w0
+		w6 := w5 ^ w3
+		w7 := w4 ^ w6
+		w8 := w7 ^ w0
+		w9 := ^w6
+		w10 := w9 | w1
+		w11 := w8 ^ w10
+		w12 := w2 & w9
+		w13 := w0 ^ w4
+		w14 := w13 & w10
+		w15 := w12 | w14
+		w16 := w8 | w15
+		w17 := w16 ^ w12
+		x0, x1, x2, x3 = w7, w15, w17, w11
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 27
+	x0 ^= K[27][0]
+	x1 ^= K[27][1]
+	x2 ^= K[27][2]
+	x3 ^= K[27][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w0 ^ w4
+		w6 := w5 ^ w2
+		w7 := w4 & w2
+		w8 := w6 ^ w7
+		w9 := w8 ^ w3
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := w4 & w3
+		w13 := w11 ^ w12
+		w14 := w1 ^ w2
+		w15 := w5 | w3
+		w16 := w15 & w9
+		w17 := w14 ^ w16
+		w18 := w15 ^ w4
+		w19 := w18 ^ w8
+		w20 := w19 ^ w17
+		w21 := w15 ^ w11
+		w22 := w13 | w20
+		w23 := w21 ^ w22
+		x0, x1, x2, x3 = w23, w20, w13, w17
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 28
+	x0 ^= K[28][0]
+	x1 ^= K[28][1]
+	x2 ^= K[28][2]
+	x3 ^= K[28][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 | w1
+		w5 := w4 ^ w0
+		w6 := w1 | w0
+		w7 := w6 & w3
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 & w8
+		w11 := w1 & w2
+		w12 := w11 | w5
+		w13 := w10 ^ w12
+		w14 := w8 & w1
+		w15 := w14 ^ w2
+		w16 := ^w3
+		w17 := w16 | w0
+		w18 := w15 ^ w17
+		w19 := w14 ^ w9
+		w20 := w18 & w12
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w18, w21, w13, w8
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 29
+	x0 ^= K[29][0]
+	x1 ^= K[29][1]
+	x2 ^= K[29][2]
+	x3 ^= K[29][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w6 ^ w3
+		w8 := w0 & w3
+		w9 := w7 ^ w8
+		w10 := w1 & w3
+		w11 := w9 ^ w10
+		w12 := ^w11
+		w13 := w3 ^ w0
+		w14 := w13 ^ w1
+		w15 := w3 | w12
+		w16 := w14 ^ w15
+		w17 := w16 ^ w3
+		w18 := w13 ^ w6
+		w19 := w18 | w11
+		w20 := w17 ^ w19
+		w21 := w14 | w20
+		w22 := w21 ^ w18
+		x0, x1, x2, x3 = w12, w16, w20, w22
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 30
+	x0 ^= K[30][0]
+	x1 ^= K[30][1]
+	x2 ^= K[30][2]
+	x3 ^= K[30][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := ^w1
+		w5 := w0 & w3
+		w6 := w5 ^ w2
+		w7 := w4 ^ w6
+		w8 := w0 & w1
+		w9 := w1 ^ w8
+		w10 := w9 ^ w2
+		w11 := w0 & w2
+		w12 := w10 ^ w11
+		w13 := w8 & w2
+		w14 := w12 ^ w13
+		w15 := w14 ^ w3
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w1 & w2
+		w19 := w18 & w3
+		w20 := w17 ^ w19
+		w21 := w15 ^ w0
+		w22 := w20 | w14
+		w23 := w22 | w4
+		w24 := w21 ^ w23
+		w25 := w24 ^ w22
+		w26 := w25 | w8
+		w27 := w26 ^ w2
+		x0, x1, x2, x3 = w24, w7, w27, w20
+	}
+	x0 = bits.RotateLeft32(x0, 13)
+	x2 = bits.RotateLeft32(x2, 3)
+	x1 ^= x0 ^ x2
+	x3 ^= x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	// Round 31
+	x0 ^= K[31][0]
+	x1 ^= K[31][1]
+	x2 ^= K[31][2]
+	x3 ^= K[31][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 ^ w1
+		w5 := w4 ^ w2
+		w6 := w0 & w2
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 & w2
+		w10 := w7 ^ w9
+		w11 := w0 & w3
+		w12 := w10 ^ w11
+		w13 := w3 | w8
+		w14 := w13 ^ w0
+		w15 := w2 | w12
+		w16 := w14 ^ w15
+		w17 := ^w2
+		w18 := w17 | w11
+		w19 := w1 | w5
+		w20 := w19 & w13
+		w21 := w18 ^ w20
+		w22 := w11 ^ w13
+		w23 := w22 & w21
+		w24 := w23 ^ w5
+		x0, x1, x2, x3 = w21, w16, w24, w12
+	}
+	x0 ^= K[32][0]
+	x1 ^= K[32][1]
+	x2 ^= K[32][2]
+	x3 ^= K[32][3]
+	x[0], x[1], x[2], x[3] = x0, x1, x2, x3
+}
+
+// Function decryptWordsUnrolled undoes encryptWordsUnrolled.
+func decryptWordsUnrolled(x *[4]uint32, K [][4]uint32) {
+	_ = K[32]
+	x0, x1, x2, x3 := x[0], x[1], x[2], x[3]
+
+	// Round 31
+	x0 ^= K[32][0]
+	x1 ^= K[32][1]
+	x2 ^= K[32][2]
+	x3 ^= K[32][3]
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w1
+		w5 := w4 & w3
+		w6 := w0 & w1
+		w7 := w6 | w2
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 | w6
+		w11 := w0 | w3
+		w12 := w11 & w2
+		w13 := w10 ^ w12
+		w14 := ^w9
+		w15 := w14 ^ w0
+		w16 := w4 & w9
+		w17 := w16 | w7
+		w18 := w15 ^ w17
+		w19 := w2 ^ w16
+		w20 := w18 | w3
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w21, w18, w13, w8
+	}
+	x0 ^= K[31][0]
+	x1 ^= K[31][1]
+	x2 ^= K[31][2]
+	x3 ^= K[31][3]
+
+	// Round 30
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w1
+		w5 := ^w2
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w1 & w3
+		w12 := w10 ^ w11
+		w13 := w0 & w1
+		w14 := w13 & w3
+		w15 := w12 ^ w14
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w2 | w13
+		w22 := w21 ^ w7
+		w23 := w11 | w10
+		w24 := w22 ^ w23
+		w25 := w5 & w22
+		w26 := w3 & w15
+		w27 := w25 | w26
+		x0, x1, x2, x3 = w24, w7, w20, w27
+	}
+	x0 ^= K[30][0]
+	x1 ^= K[30][1]
+	x2 ^= K[30][2]
+	x3 ^= K[30][3]
+
+	// Round 29
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w1 & w2
+		w5 := w0 ^ w4
+		w6 := w5 ^ w3
+		w7 := w0 & w1
+		w8 := w7 & w3
+		w9 := w6 ^ w8
+		w10 := w7 | w2
+		w11 := ^w10
+		w12 := w0 & w3
+		w13 := w12 ^ w1
+		w14 := w11 ^ w13
+		w15 := w10 & w0
+		w16 := w15 ^ w9
+		w17 := w13 ^ w7
+		w18 := w16 ^ w17
+		w19 := w5 ^ w10
+		w20 := w17 & w16
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w9, w18, w21, w14
+	}
+	x0 ^= K[29][0]
+	x1 ^= K[29][1]
+	x2 ^= K[29][2]
+	x3 ^= K[29][3]
+
+	// Round 28
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w0 & w3
+		w8 := w6 ^ w7
+		w9 := w4 & w3
+		w10 := w8 ^ w9
+		w11 := w2 & w3
+		w12 := w10 ^ w11
+		w13 := w1 ^ w8
+		w14 := w2 & w0
+		w15 := w14 | w3
+		w16 := w13 ^ w15
+		w17 := ^w0
+		w18 := w17 | w16
+		w19 := w3 ^ w12
+		w20 := w18 ^ w19
+		w21 := w10 & w15
+		w22 := w21 | w4
+		w23 := w22 ^ w20
+		x0, x1, x2, x3 = w20, w16, w23, w12
+	}
+	x0 ^= K[28][0]
+	x1 ^= K[28][1]
+	x2 ^= K[28][2]
+	x3 ^= K[28][3]
+
+	// Round 27
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w3
+		w5 := w4 ^ w2
+		w6 := w3 | w2
+		w7 := w6 & w1
+		w8 := w5 ^ w7
+		w9 := w1 ^ w4
+		w10 := w9 & w5
+		w11 := w0 ^ w3
+		w12 := w10 ^ w11
+		w13 := w6 ^ w11
+		w14 := w12 & w0
+		w15 := w14 | w1
+		w16 := w13 ^ w15
+		w17 := w14 ^ w5
+		w18 := w13 | w9
+		w19 := w18 ^ w6
+		w20 := w17 ^ w19
+		x0, x1, x2, x3 = w8, w20, w12, w16
+	}
+	x0 ^= K[27][0]
+	x1 ^= K[27][1]
+	x2 ^= K[27][2]
+	x3 ^= K[27][3]
+
+	// Round 26
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w0
+		w5 := w2 ^ w3
+		w6 := w5 | w1
+		w7 := w4 ^ w6
+		w8 := w2 | w7
+		w9 := w8 & w5
+		w10 := w7 | w3
+		w11 := w10 & w1
+		w12 := w9 ^ w11
+		w13 := w1 | w3
+		w14 := ^w13
+		w15 := w0 ^ w12
+		w16 := w15 & w8
+		w17 := w14 | w16
+		w18 := w2 & w3
+		w19 := w18 ^ w15
+		w20 := w14 ^ w19
+		x0, x1, x2, x3 = w7, w12, w20, w17
+	}
+	x0 ^= K[26][0]
+	x1 ^= K[26][1]
+	x2 ^= K[26][2]
+	x3 ^= K[26][3]
+
+	// Round 25
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w4 ^ w0
+		w6 := w1 | w3
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 | w4
+		w10 := w7 | w2
+		w11 := w10 & w3
+		w12 := w9 ^ w11
+		w13 := ^w2
+		w14 := w13 ^ w12
+		w15 := w3 | w0
+		w16 := w15 ^ w8
+		w17 := w14 ^ w16
+		w18 := w12 & w17
+		w19 := w14 ^ w7
+		w20 := w18 ^ w19
+		x0, x1, x2, x3 = w17, w12, w20, w7
+	}
+	x0 ^= K[25][0]
+	x1 ^= K[25][1]
+	x2 ^= K[25][2]
+	x3 ^= K[25][3]
+
+	// Round 24
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w2
+		w5 := w1 | w0
+		w6 := ^w5
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w8 ^ w2
+		w10 := w0 & w2
+		w11 := w9 ^ w10
+		w12 := w1 & w3
+		w13 := w11 ^ w12
+		w14 := w10 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w4 ^ w12
+		w20 := w8 ^ w7
+		w21 := w20 | w13
+		w22 := w19 ^ w21
+		w23 := w8 ^ w3
+		w24 := w23 & w0
+		w25 := w24 ^ w21
+		x0, x1, x2, x3 = w22, w18, w7, w25
+	}
+	x0 ^= K[24][0]
+	x1 ^= K[24][1]
+	x2 ^= K[24][2]
+	x3 ^= K[24][3]
+
+	// Round 23
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w1
+		w5 := w4 & w3
+		w6 := w0 & w1
+		w7 := w6 | w2
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 | w6
+		w11 := w0 | w3
+		w12 := w11 & w2
+		w13 := w10 ^ w12
+		w14 := ^w9
+		w15 := w14 ^ w0
+		w16 := w4 & w9
+		w17 := w16 | w7
+		w18 := w15 ^ w17
+		w19 := w2 ^ w16
+		w20 := w18 | w3
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w21, w18, w13, w8
+	}
+	x0 ^= K[23][0]
+	x1 ^= K[23][1]
+	x2 ^= K[23][2]
+	x3 ^= K[23][3]
+
+	// Round 22
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w1
+		w5 := ^w2
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w1 & w3
+		w12 := w10 ^ w11
+		w13 := w0 & w1
+		w14 := w13 & w3
+		w15 := w12 ^ w14
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w2 | w13
+		w22 := w21 ^ w7
+		w23 := w11 | w10
+		w24 := w22 ^ w23
+		w25 := w5 & w22
+		w26 := w3 & w15
+		w27 := w25 | w26
+		x0, x1, x2, x3 = w24, w7, w20, w27
+	}
+	x0 ^= K[22][0]
+	x1 ^= K[22][1]
+	x2 ^= K[22][2]
+	x3 ^= K[22][3]
+
+	// Round 21
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w1 & w2
+		w5 := w0 ^ w4
+		w6 := w5 ^ w3
+		w7 := w0 & w1
+		w8 := w7 & w3
+		w9 := w6 ^ w8
+		w10 := w7 | w2
+		w11 := ^w10
+		w12 := w0 & w3
+		w13 := w12 ^ w1
+		w14 := w11 ^ w13
+		w15 := w10 & w0
+		w16 := w15 ^ w9
+		w17 := w13 ^ w7
+		w18 := w16 ^ w17
+		w19 := w5 ^ w10
+		w20 := w17 & w16
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w9, w18, w21, w14
+	}
+	x0 ^= K[21][0]
+	x1 ^= K[21][1]
+	x2 ^= K[21][2]
+	x3 ^= K[21][3]
+
+	// Round 20
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w0 & w3
+		w8 := w6 ^ w7
+		w9 := w4 & w3
+		w10 := w8 ^ w9
+		w11 := w2 & w3
+		w12 := w10 ^ w11
+		w13 := w1 ^ w8
+		w14 := w2 & w0
+		w15 := w14 | w3
+		w16 := w13 ^ w15
+		w17 := ^w0
+		w18 := w17 | w16
+		w19 := w3 ^ w12
+		w20 := w18 ^ w19
+		w21 := w10 & w15
+		w22 := w21 | w4
+		w23 := w22 ^ w20
+		x0, x1, x2, x3 = w20, w16, w23, w12
+	}
+	x0 ^= K[20][0]
+	x1 ^= K[20][1]
+	x2 ^= K[20][2]
+	x3 ^= K[20][3]
+
+	// Round 19
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w3
+		w5 := w4 ^ w2
+		w6 := w3 | w2
+		w7 := w6 & w1
+		w8 := w5 ^ w7
+		w9 := w1 ^ w4
+		w10 := w9 & w5
+		w11 := w0 ^ w3
+		w12 := w10 ^ w11
+		w13 := w6 ^ w11
+		w14 := w12 & w0
+		w15 := w14 | w1
+		w16 := w13 ^ w15
+		w17 := w14 ^ w5
+		w18 := w13 | w9
+		w19 := w18 ^ w6
+		w20 := w17 ^ w19
+		x0, x1, x2, x3 = w8, w20, w12, w16
+	}
+	x0 ^= K[19][0]
+	x1 ^= K[19][1]
+	x2 ^= K[19][2]
+	x3 ^= K[19][3]
+
+	// Round 18
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w0
+		w5 := w2 ^ w3
+		w6 := w5 | w1
+		w7 := w4 ^ w6
+		w8 := w2 | w7
+		w9 := w8 & w5
+		w10 := w7 | w3
+		w11 := w10 & w1
+		w12 := w9 ^ w11
+		w13 := w1 | w3
+		w14 := ^w13
+		w15 := w0 ^ w12
+		w16 := w15 & w8
+		w17 := w14 | w16
+		w18 := w2 & w3
+		w19 := w18 ^ w15
+		w20 := w14 ^ w19
+		x0, x1, x2, x3 = w7, w12, w20, w17
+	}
+	x0 ^= K[18][0]
+	x1 ^= K[18][1]
+	x2 ^= K[18][2]
+	x3 ^= K[18][3]
+
+	// Round 17
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w4 ^ w0
+		w6 := w1 | w3
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 | w4
+		w10 := w7 | w2
+		w11 := w10 & w3
+		w12 := w9 ^ w11
+		w13 := ^w2
+		w14 := w13 ^ w12
+		w15 := w3 | w0
+		w16 := w15 ^ w8
+		w17 := w14 ^ w16
+		w18 := w12 & w17
+		w19 := w14 ^ w7
+		w20 := w18 ^ w19
+		x0, x1, x2, x3 = w17, w12, w20, w7
+	}
+	x0 ^= K[17][0]
+	x1 ^= K[17][1]
+	x2 ^= K[17][2]
+	x3 ^= K[17][3]
+
+	// Round 16
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w2
+		w5 := w1 | w0
+		w6 := ^w5
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w8 ^ w2
+		w10 := w0 & w2
+		w11 := w9 ^ w10
+		w12 := w1 & w3
+		w13 := w11 ^ w12
+		w14 := w10 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w4 ^ w12
+		w20 := w8 ^ w7
+		w21 := w20 | w13
+		w22 := w19 ^ w21
+		w23 := w8 ^ w3
+		w24 := w23 & w0
+		w25 := w24 ^ w21
+		x0, x1, x2, x3 = w22, w18, w7, w25
+	}
+	x0 ^= K[16][0]
+	x1 ^= K[16][1]
+	x2 ^= K[16][2]
+	x3 ^= K[16][3]
+
+	// Round 15
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w1
+		w5 := w4 & w3
+		w6 := w0 & w1
+		w7 := w6 | w2
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 | w6
+		w11 := w0 | w3
+		w12 := w11 & w2
+		w13 := w10 ^ w12
+		w14 := ^w9
+		w15 := w14 ^ w0
+		w16 := w4 & w9
+		w17 := w16 | w7
+		w18 := w15 ^ w17
+		w19 := w2 ^ w16
+		w20 := w18 | w3
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w21, w18, w13, w8
+	}
+	x0 ^= K[15][0]
+	x1 ^= K[15][1]
+	x2 ^= K[15][2]
+	x3 ^= K[15][3]
+
+	// Round 14
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w1
+		w5 := ^w2
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w1 & w3
+		w12 := w10 ^ w11
+		w13 := w0 & w1
+		w14 := w13 & w3
+		w15 := w12 ^ w14
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w2 | w13
+		w22 := w21 ^ w7
+		w23 := w11 | w10
+		w24 := w22 ^ w23
+		w25 := w5 & w22
+		w26 := w3 & w15
+		w27 := w25 | w26
+		x0, x1, x2, x3 = w24, w7, w20, w27
+	}
+	x0 ^= K[14][0]
+	x1 ^= K[14][1]
+	x2 ^= K[14][2]
+	x3 ^= K[14][3]
+
+	// Round 13
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w1 & w2
+		w5 := w0 ^ w4
+		w6 := w5 ^ w3
+		w7 := w0 & w1
+		w8 := w7 & w3
+		w9 := w6 ^ w8
+		w10 := w7 | w2
+		w11 := ^w10
+		w12 := w0 & w3
+		w13 := w12 ^ w1
+		w14 := w11 ^ w13
+		w15 := w10 & w0
+		w16 := w15 ^ w9
+		w17 := w13 ^ w7
+		w18 := w16 ^ w17
+		w19 := w5 ^ w10
+		w20 := w17 & w16
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w9, w18, w21, w14
+	}
+	x0 ^= K[13][0]
+	x1 ^= K[13][1]
+	x2 ^= K[13][2]
+	x3 ^= K[13][3]
+
+	// Round 12
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w0 & w3
+		w8 := w6 ^ w7
+		w9 := w4 & w3
+		w10 := w8 ^ w9
+		w11 := w2 & w3
+		w12 := w10 ^ w11
+		w13 := w1 ^ w8
+		w14 := w2 & w0
+		w15 := w14 | w3
+		w16 := w13 ^ w15
+		w17 := ^w0
+		w18 := w17 | w16
+		w19 := w3 ^ w12
+		w20 := w18 ^ w19
+		w21 := w10 & w15
+		w22 := w21 | w4
+		w23 := w22 ^ w20
+		x0, x1, x2, x3 = w20, w16, w23, w12
+	}
+	x0 ^= K[12][0]
+	x1 ^= K[12][1]
+	x2 ^= K[12][2]
+	x3 ^= K[12][3]
+
+	// Round 11
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w3
+		w5 := w4 ^ w2
+		w6 := w3 | w2
+		w7 := w6 & w1
+		w8 := w5 ^ w7
+		w9 := w1 ^ w4
+		w10 := w9 & w5
+		w11 := w0 ^ w3
+		w12 := w10 ^ w11
+		w13 := w6 ^ w11
+		w14 := w12 & w0
+		w15 := w14 | w1
+		w16 := w13 ^ w15
+		w17 := w14 ^ w5
+		w18 := w13 | w9
+		w19 := w18 ^ w6
+		w20 := w17 ^ w19
+		x0, x1, x2, x3 = w8, w20, w12, w16
+	}
+	x0 ^= K[11][0]
+	x1 ^= K[11][1]
+	x2 ^= K[11][2]
+	x3 ^= K[11][3]
+
+	// Round 10
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w0
+		w5 := w2 ^ w3
+		w6 := w5 | w1
+		w7 := w4 ^ w6
+		w8 := w2 | w7
+		w9 := w8 & w5
+		w10 := w7 | w3
+		w11 := w10 & w1
+		w12 := w9 ^ w11
+		w13 := w1 | w3
+		w14 := ^w13
+		w15 := w0 ^ w12
+		w16 := w15 & w8
+		w17 := w14 | w16
+		w18 := w2 & w3
+		w19 := w18 ^ w15
+		w20 := w14 ^ w19
+		x0, x1, x2, x3 = w7, w12, w20, w17
+	}
+	x0 ^= K[10][0]
+	x1 ^= K[10][1]
+	x2 ^= K[10][2]
+	x3 ^= K[10][3]
+
+	// Round 9
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w4 ^ w0
+		w6 := w1 | w3
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 | w4
+		w10 := w7 | w2
+		w11 := w10 & w3
+		w12 := w9 ^ w11
+		w13 := ^w2
+		w14 := w13 ^ w12
+		w15 := w3 | w0
+		w16 := w15 ^ w8
+		w17 := w14 ^ w16
+		w18 := w12 & w17
+		w19 := w14 ^ w7
+		w20 := w18 ^ w19
+		x0, x1, x2, x3 = w17, w12, w20, w7
+	}
+	x0 ^= K[9][0]
+	x1 ^= K[9][1]
+	x2 ^= K[9][2]
+	x3 ^= K[9][3]
+
+	// Round 8
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w2
+		w5 := w1 | w0
+		w6 := ^w5
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w8 ^ w2
+		w10 := w0 & w2
+		w11 := w9 ^ w10
+		w12 := w1 & w3
+		w13 := w11 ^ w12
+		w14 := w10 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w4 ^ w12
+		w20 := w8 ^ w7
+		w21 := w20 | w13
+		w22 := w19 ^ w21
+		w23 := w8 ^ w3
+		w24 := w23 & w0
+		w25 := w24 ^ w21
+		x0, x1, x2, x3 = w22, w18, w7, w25
+	}
+	x0 ^= K[8][0]
+	x1 ^= K[8][1]
+	x2 ^= K[8][2]
+	x3 ^= K[8][3]
+
+	// Round 7
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w1
+		w5 := w4 & w3
+		w6 := w0 & w1
+		w7 := w6 | w2
+		w8 := w5 ^ w7
+		w9 := w1 ^ w3
+		w10 := w9 | w6
+		w11 := w0 | w3
+		w12 := w11 & w2
+		w13 := w10 ^ w12
+		w14 := ^w9
+		w15 := w14 ^ w0
+		w16 := w4 & w9
+		w17 := w16 | w7
+		w18 := w15 ^ w17
+		w19 := w2 ^ w16
+		w20 := w18 | w3
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w21, w18, w13, w8
+	}
+	x0 ^= K[7][0]
+	x1 ^= K[7][1]
+	x2 ^= K[7][2]
+	x3 ^= K[7][3]
+
+	// Round 6
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w1
+		w5 := ^w2
+		w6 := w5 | w0
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w1 & w2
+		w10 := w8 ^ w9
+		w11 := w1 & w3
+		w12 := w10 ^ w11
+		w13 := w0 & w1
+		w14 := w13 & w3
+		w15 := w12 ^ w14
+		w16 := w2 & w3
+		w17 := w15 ^ w16
+		w18 := w9 & w3
+		w19 := w17 ^ w18
+		w20 := ^w19
+		w21 := w2 | w13
+		w22 := w21 ^ w7
+		w23 := w11 | w10
+		w24 := w22 ^ w23
+		w25 := w5 & w22
+		w26 := w3 & w15
+		w27 := w25 | w26
+		x0, x1, x2, x3 = w24, w7, w20, w27
+	}
+	x0 ^= K[6][0]
+	x1 ^= K[6][1]
+	x2 ^= K[6][2]
+	x3 ^= K[6][3]
+
+	// Round 5
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w1 & w2
+		w5 := w0 ^ w4
+		w6 := w5 ^ w3
+		w7 := w0 & w1
+		w8 := w7 & w3
+		w9 := w6 ^ w8
+		w10 := w7 | w2
+		w11 := ^w10
+		w12 := w0 & w3
+		w13 := w12 ^ w1
+		w14 := w11 ^ w13
+		w15 := w10 & w0
+		w16 := w15 ^ w9
+		w17 := w13 ^ w7
+		w18 := w16 ^ w17
+		w19 := w5 ^ w10
+		w20 := w17 & w16
+		w21 := w19 ^ w20
+		x0, x1, x2, x3 = w9, w18, w21, w14
+	}
+	x0 ^= K[5][0]
+	x1 ^= K[5][1]
+	x2 ^= K[5][2]
+	x3 ^= K[5][3]
+
+	// Round 4
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 & w1
+		w5 := w1 ^ w4
+		w6 := w5 ^ w2
+		w7 := w0 & w3
+		w8 := w6 ^ w7
+		w9 := w4 & w3
+		w10 := w8 ^ w9
+		w11 := w2 & w3
+		w12 := w10 ^ w11
+		w13 := w1 ^ w8
+		w14 := w2 & w0
+		w15 := w14 | w3
+		w16 := w13 ^ w15
+		w17 := ^w0
+		w18 := w17 | w16
+		w19 := w3 ^ w12
+		w20 := w18 ^ w19
+		w21 := w10 & w15
+		w22 := w21 | w4
+		w23 := w22 ^ w20
+		x0, x1, x2, x3 = w20, w16, w23, w12
+	}
+	x0 ^= K[4][0]
+	x1 ^= K[4][1]
+	x2 ^= K[4][2]
+	x3 ^= K[4][3]
+
+	// Round 3
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w0 | w3
+		w5 := w4 ^ w2
+		w6 := w3 | w2
+		w7 := w6 & w1
+		w8 := w5 ^ w7
+		w9 := w1 ^ w4
+		w10 := w9 & w5
+		w11 := w0 ^ w3
+		w12 := w10 ^ w11
+		w13 := w6 ^ w11
+		w14 := w12 & w0
+		w15 := w14 | w1
+		w16 := w13 ^ w15
+		w17 := w14 ^ w5
+		w18 := w13 | w9
+		w19 := w18 ^ w6
+		w20 := w17 ^ w19
+		x0, x1, x2, x3 = w8, w20, w12, w16
+	}
+	x0 ^= K[3][0]
+	x1 ^= K[3][1]
+	x2 ^= K[3][2]
+	x3 ^= K[3][3]
+
+	// Round 2
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w0
+		w5 := w2 ^ w3
+		w6 := w5 | w1
+		w7 := w4 ^ w6
+		w8 := w2 | w7
+		w9 := w8 & w5
+		w10 := w7 | w3
+		w11 := w10 & w1
+		w12 := w9 ^ w11
+		w13 := w1 | w3
+		w14 := ^w13
+		w15 := w0 ^ w12
+		w16 := w15 & w8
+		w17 := w14 | w16
+		w18 := w2 & w3
+		w19 := w18 ^ w15
+		w20 := w14 ^ w19
+		x0, x1, x2, x3 = w7, w12, w20, w17
+	}
+	x0 ^= K[2][0]
+	x1 ^= K[2][1]
+	x2 ^= K[2][2]
+	x3 ^= K[2][3]
+
+	// Round 1
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w2 ^ w1
+		w5 := w4 ^ w0
+		w6 := w1 | w3
+		w7 := w5 ^ w6
+		w8 := w0 & w1
+		w9 := w8 | w4
+		w10 := w7 | w2
+		w11 := w10 & w3
+		w12 := w9 ^ w11
+		w13 := ^w2
+		w14 := w13 ^ w12
+		w15 := w3 | w0
+		w16 := w15 ^ w8
+		w17 := w14 ^ w16
+		w18 := w12 & w17
+		w19 := w14 ^ w7
+		w20 := w18 ^ w19
+		x0, x1, x2, x3 = w17, w12, w20, w7
+	}
+	x0 ^= K[1][0]
+	x1 ^= K[1][1]
+	x2 ^= K[1][2]
+	x3 ^= K[1][3]
+
+	// Round 0
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+	{
+		w0, w1, w2, w3 := x0, x1, x2, x3
+		w4 := w3 ^ w2
+		w5 := w1 | w0
+		w6 := ^w5
+		w7 := w4 ^ w6
+		w8 := w0 ^ w1
+		w9 := w8 ^ w2
+		w10 := w0 & w2
+		w11 := w9 ^ w10
+		w12 := w1 & w3
+		w13 := w11 ^ w12
+		w14 := w10 & w3
+		w15 := w13 ^ w14
+		w16 := w1 & w2
+		w17 := w16 & w3
+		w18 := w15 ^ w17
+		w19 := w4 ^ w12
+		w20 := w8 ^ w7
+		w21 := w20 | w13
+		w22 := w19 ^ w21
+		w23 := w8 ^ w3
+		w24 := w23 & w0
+		w25 := w24 ^ w21
+		x0, x1, x2, x3 = w22, w18, w7, w25
+	}
+	x0 ^= K[0][0]
+	x1 ^= K[0][1]
+	x2 ^= K[0][2]
+	x3 ^= K[0][3]
+	x[0], x[1], x[2], x[3] = x0, x1, x2, x3
+}
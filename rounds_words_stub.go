@@ -0,0 +1,22 @@
+//go:build nounrolled
+
+package serpent
+
+// Without rounds_words.go the word core runs its rounds in a loop. The
+// generator of rounds_words.go builds the package this way so that it
+// does not depend on its own output.
+
+// The unrolled rounds are not available.
+const haveUnrolled = false
+
+// Function encryptWordsUnrolled is never called without the unrolled
+// rounds.
+func encryptWordsUnrolled(x *[4]uint32, K [][4]uint32) {
+	panic("serpent: built without the unrolled rounds")
+}
+
+// Function decryptWordsUnrolled is never called without the unrolled
+// rounds.
+func decryptWordsUnrolled(x *[4]uint32, K [][4]uint32) {
+	panic("serpent: built without the unrolled rounds")
+}
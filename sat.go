@@ -0,0 +1,401 @@
+package serpent
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Model encodes reduced-round Serpent as boolean constraints for SAT and
+// SMT solvers. It follows the normal algorithm for the rounds and the key
+// schedule described by Params, so the variables of a solution satisfy
+//
+//	New(p, key).Encrypt(plaintext) == ciphertext
+//
+// Variables are numbered from 1 as in DIMACS. Bit i of the plaintext,
+// ciphertext and 256-bit user key Bitstrings is held by variable
+// Plaintext[i], Ciphertext[i] and Key[i].
+type Model struct {
+	Params     Params
+	Plaintext  [128]int
+	Ciphertext [128]int
+	Key        [256]int
+
+	vars   int
+	units  []int
+	xors   []xorConstraint
+	sboxes []sboxConstraint
+}
+
+// A xorConstraint requires the xor of its variables to equal value.
+type xorConstraint struct {
+	vars  []int
+	value int
+}
+
+// A sboxConstraint requires out to be the image of in by box, bit j of
+// the S-Box input and output being in[j] and out[j].
+type sboxConstraint struct {
+	box     SBox
+	in, out [4]int
+}
+
+// Function NewModel encodes the rounds and key schedule of 'p'.
+func NewModel(p Params) (*Model, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	m := &Model{Params: p}
+	v := newVariant(p)
+	for i := range m.Plaintext {
+		m.Plaintext[i] = m.newVar()
+	}
+	for i := range m.Key {
+		m.Key[i] = m.newVar()
+	}
+	KHat := m.subkeys(v)
+
+	state := permuteVars(IPTable, m.Plaintext[:])
+	for i := p.StartRound; i <= v.lastRound(); i++ {
+		x := m.xorVars(state, KHat[i])
+		s := make([]int, 128)
+		for j := 0; j < 32; j++ {
+			m.sbox(p.SBoxes[v.box(i)], x[4*j:4*j+4], s[4*j:4*j+4])
+		}
+		if i < v.lastRound() {
+			state = m.linear(p.LTTable, s)
+		} else {
+			state = m.xorVars(s, KHat[i+1])
+		}
+	}
+	copy(m.Ciphertext[:], permuteVars(FPTable, state))
+	return m, nil
+}
+
+// Method subkeys encodes the key schedule and returns the variables of
+// the subkeys KHat used by the rounds.
+func (m *Model) subkeys(v *variant) [][]int {
+	n := v.subkeyCount()
+
+	// The prekey recurrence, the user key being w[-8] ... w[-1].
+	w := make([][]int, 4*n+8)
+	for i := 0; i < 8; i++ {
+		w[i] = m.Key[32*i : 32*i+32]
+	}
+	for i := 0; i < 4*n; i++ {
+		word := make([]int, 32)
+		constant := uint32(v.Phi) ^ uint32(i)
+		for b := 0; b < 32; b++ {
+			// Rotating left by 11 moves bit b-11 into bit b.
+			src := (b + 32 - 11) % 32
+			word[b] = m.newVar()
+			m.addXor([]int{w[i][src], w[i+3][src], w[i+5][src],
+				w[i+7][src], word[b]}, int(constant>>uint(src))&1)
+		}
+		w[i+8] = word
+	}
+
+	KHat := make([][]int, n)
+	for i := v.StartRound; i < n; i++ {
+		whichS := v.keyScheduleBox(i)
+		K := make([]int, 128)
+		for j := 0; j < 32; j++ {
+			var in, out [4]int
+			for l := 0; l < 4; l++ {
+				in[l] = w[4*i+l+8][j]
+				out[l] = m.newVar()
+				K[32*l+j] = out[l]
+			}
+			m.sbox(v.SBoxes[v.box(whichS)], in[:], out[:])
+		}
+		KHat[i] = permuteVars(IPTable, K)
+	}
+	return KHat
+}
+
+func (m *Model) newVar() int {
+	m.vars++
+	return m.vars
+}
+
+// Method addXor records that the xor of 'vars' equals 'value'. Long
+// constraints are split with intermediate variables to keep the number of
+// clauses small.
+func (m *Model) addXor(vars []int, value int) {
+	for len(vars) > 4 {
+		t := m.newVar()
+		m.xors = append(m.xors, xorConstraint{
+			vars: []int{vars[0], vars[1], vars[2], t}})
+		vars = append([]int{t}, vars[3:]...)
+	}
+	m.xors = append(m.xors, xorConstraint{vars: vars, value: value})
+}
+
+// Method xorVars returns new variables holding the bitwise xor of 'a' and
+// 'b'.
+func (m *Model) xorVars(a, b []int) []int {
+	result := make([]int, len(a))
+	for i := range a {
+		result[i] = m.newVar()
+		m.addXor([]int{a[i], b[i], result[i]}, 0)
+	}
+	return result
+}
+
+// Method sbox fills 'out' with new variables holding the image of 'in'.
+func (m *Model) sbox(box SBox, in, out []int) {
+	var c sboxConstraint
+	c.box = box
+	for l := 0; l < 4; l++ {
+		if out[l] == 0 {
+			out[l] = m.newVar()
+		}
+		c.in[l], c.out[l] = in[l], out[l]
+	}
+	m.sboxes = append(m.sboxes, c)
+}
+
+// Method linear returns new variables holding the linear transformation
+// of 's' by 'table'.
+func (m *Model) linear(table []Ttable, s []int) []int {
+	result := make([]int, 128)
+	for i, positions := range table {
+		result[i] = m.newVar()
+		vars := []int{result[i]}
+		for _, j := range positions {
+			vars = append(vars, s[j])
+		}
+		m.addXor(vars, 0)
+	}
+	return result
+}
+
+// Function permuteVars applies a permutation table to a list of
+// variables, as Permutation.Apply does to a Bitstring.
+func permuteVars(ptable []int, vars []int) []int {
+	result := make([]int, len(ptable))
+	for i, j := range ptable {
+		result[i] = vars[j]
+	}
+	return result
+}
+
+// Method FixPlaintext fixes the plaintext bits given as 0 or 1 in the
+// 128-bit 'bits'; any other character, such as x, leaves a bit free.
+func (m *Model) FixPlaintext(bits Bitstring) error {
+	return m.fix("plaintext", m.Plaintext[:], bits)
+}
+
+// Method FixCiphertext fixes the ciphertext bits given as 0 or 1 in the
+// 128-bit 'bits'.
+func (m *Model) FixCiphertext(bits Bitstring) error {
+	return m.fix("ciphertext", m.Ciphertext[:], bits)
+}
+
+// Method FixKey fixes the user key bits given as 0 or 1 in 'bits'. Keys
+// shorter than 256 bits are lengthened as by New, which fixes the padding
+// bits too.
+func (m *Model) FixKey(bits Bitstring) error {
+	lk := len(bits)
+	if lk%32 != 0 || lk < 64 || lk > 256 {
+		return fmt.Errorf("serpent: invalid key length (%d bits)", lk)
+	}
+	return m.fix("key", m.Key[:], makeLongkey(bits))
+}
+
+func (m *Model) fix(name string, vars []int, bits Bitstring) error {
+	if len(bits) != len(vars) {
+		return fmt.Errorf("serpent: %s has %d bits, want %d", name,
+			len(bits), len(vars))
+	}
+	for i := 0; i < len(bits); i++ {
+		switch bits[i] {
+		case '0':
+			m.units = append(m.units, -vars[i])
+		case '1':
+			m.units = append(m.units, vars[i])
+		}
+	}
+	return nil
+}
+
+// Method Vars returns the number of variables of the model.
+func (m *Model) Vars() int {
+	return m.vars
+}
+
+// Method clauses calls 'f' with every clause of the CNF encoding.
+func (m *Model) clauses(f func([]int)) {
+	for _, u := range m.units {
+		f([]int{u})
+	}
+	for _, c := range m.xors {
+		// Forbid every assignment of the wrong parity.
+		n := len(c.vars)
+		for a := 0; a < 1<<uint(n); a++ {
+			if parity(a) == c.value {
+				continue
+			}
+			clause := make([]int, n)
+			for k, v := range c.vars {
+				if a&(1<<uint(k)) != 0 {
+					clause[k] = -v
+				} else {
+					clause[k] = v
+				}
+			}
+			f(clause)
+		}
+	}
+	for _, c := range m.sboxes {
+		// For each input its output, and for each output its input.
+		inverse := c.box.Inverse()
+		for x := 0; x < 16; x++ {
+			for l := 0; l < 4; l++ {
+				f(sboxClause(c.in, x, c.out[l], c.box[x]>>uint(l)))
+				f(sboxClause(c.out, x, c.in[l], inverse[x]>>uint(l)))
+			}
+		}
+	}
+}
+
+// Function sboxClause returns the clause "'vars' differ from 'x' or
+// variable 'v' equals the low bit of 'b'".
+func sboxClause(vars [4]int, x, v, b int) []int {
+	clause := make([]int, 0, 5)
+	for j := 0; j < 4; j++ {
+		if x&(1<<uint(j)) != 0 {
+			clause = append(clause, -vars[j])
+		} else {
+			clause = append(clause, vars[j])
+		}
+	}
+	if b&1 == 1 {
+		return append(clause, v)
+	}
+	return append(clause, -v)
+}
+
+// Method WriteDIMACS writes the model in DIMACS CNF format. Comment lines
+// name the variables of the plaintext, ciphertext and key.
+func (m *Model) WriteDIMACS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := 0
+	m.clauses(func([]int) { n++ })
+	fmt.Fprintf(bw, "c Serpent rounds %d to %d\n", m.Params.StartRound,
+		m.Params.StartRound+m.Params.Rounds-1)
+	for _, named := range []struct {
+		name string
+		vars []int
+	}{
+		{"plaintext", m.Plaintext[:]},
+		{"ciphertext", m.Ciphertext[:]},
+		{"key", m.Key[:]},
+	} {
+		for i, v := range named.vars {
+			fmt.Fprintf(bw, "c %s %d %d\n", named.name, i, v)
+		}
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", m.vars, n)
+	m.clauses(func(clause []int) {
+		for _, l := range clause {
+			fmt.Fprintf(bw, "%d ", l)
+		}
+		fmt.Fprintf(bw, "0\n")
+	})
+	return bw.Flush()
+}
+
+// Method WriteSMTLIB writes the model as an SMT-LIB2 script over bit
+// vectors. The plaintext, ciphertext and key are declared as bit vectors
+// whose bit i is bit i of the corresponding Bitstring, so their values
+// read as Hexstrings; every other variable is a 1-bit vector.
+func (m *Model) WriteSMTLIB(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "; Serpent rounds %d to %d\n", m.Params.StartRound,
+		m.Params.StartRound+m.Params.Rounds-1)
+	fmt.Fprintf(bw, "(set-logic QF_BV)\n")
+	fmt.Fprintf(bw, "(declare-const plaintext (_ BitVec 128))\n")
+	fmt.Fprintf(bw, "(declare-const ciphertext (_ BitVec 128))\n")
+	fmt.Fprintf(bw, "(declare-const key (_ BitVec 256))\n")
+	for v := 1; v <= m.vars; v++ {
+		fmt.Fprintf(bw, "(declare-const v%d (_ BitVec 1))\n", v)
+	}
+	for _, named := range []struct {
+		name string
+		vars []int
+	}{
+		{"plaintext", m.Plaintext[:]},
+		{"ciphertext", m.Ciphertext[:]},
+		{"key", m.Key[:]},
+	} {
+		for i, v := range named.vars {
+			fmt.Fprintf(bw, "(assert (= v%d ((_ extract %d %d) %s)))\n",
+				v, i, i, named.name)
+		}
+	}
+	for _, u := range m.units {
+		if u > 0 {
+			fmt.Fprintf(bw, "(assert (= v%d #b1))\n", u)
+		} else {
+			fmt.Fprintf(bw, "(assert (= v%d #b0))\n", -u)
+		}
+	}
+	for _, c := range m.xors {
+		terms := make([]string, len(c.vars))
+		for k, v := range c.vars {
+			terms[k] = fmt.Sprintf("v%d", v)
+		}
+		fmt.Fprintf(bw, "(assert (= %s #b%d))\n", smtXor(terms), c.value)
+	}
+	for _, c := range m.sboxes {
+		// Each output bit is given by its algebraic normal form.
+		for l, f := range c.box.ANF() {
+			var terms []string
+			for mono, coefficient := range f {
+				if coefficient == 0 {
+					continue
+				}
+				var factors []string
+				for j := 0; j < 4; j++ {
+					if mono&(1<<uint(j)) != 0 {
+						factors = append(factors,
+							fmt.Sprintf("v%d", c.in[j]))
+					}
+				}
+				switch len(factors) {
+				case 0:
+					terms = append(terms, "#b1")
+				case 1:
+					terms = append(terms, factors[0])
+				default:
+					terms = append(terms, smtApply("bvand", factors))
+				}
+			}
+			fmt.Fprintf(bw, "(assert (= v%d %s))\n", c.out[l],
+				smtXor(terms))
+		}
+	}
+	fmt.Fprintf(bw, "(check-sat)\n")
+	fmt.Fprintf(bw, "(get-value (plaintext ciphertext key))\n")
+	return bw.Flush()
+}
+
+// Function smtXor returns the SMT-LIB2 xor of 'terms'.
+func smtXor(terms []string) string {
+	switch len(terms) {
+	case 0:
+		return "#b0"
+	case 1:
+		return terms[0]
+	}
+	return smtApply("bvxor", terms)
+}
+
+func smtApply(op string, args []string) string {
+	s := "(" + op
+	for _, a := range args {
+		s += " " + a
+	}
+	return s + ")"
+}
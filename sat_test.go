@@ -0,0 +1,294 @@
+package serpent
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Function TestModelEncrypts checks that the model, with the plaintext and
+// key fixed, forces the ciphertext that Cipher computes.
+func TestModelEncrypts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, rounds := range []struct{ start, n int }{{0, 1}, {5, 2}} {
+		p := Serpent1
+		p.StartRound, p.Rounds = rounds.start, rounds.n
+		key := BitstringFromBytes(randomBytes(r, 16))
+		plainText := BitstringFromBytes(randomBytes(r, 16))
+		c, _ := New(p, key)
+
+		m, err := NewModel(p)
+		if err != nil {
+			t.Fatalf("NewModel failed: %v\n", err)
+		}
+		m.FixKey(key)
+		m.FixPlaintext(plainText)
+		solution := solveModel(t, m)
+		if solution == nil {
+			t.Fatalf("rounds %v: no solution\n", rounds)
+		}
+		if got := modelBits(solution, m.Ciphertext[:]); got !=
+			c.Encrypt(plainText) {
+			t.Errorf("rounds %v: model gives %s, want %s\n", rounds,
+				got.ToHexstring(), c.Encrypt(plainText).ToHexstring())
+		}
+	}
+}
+
+// Function TestModelRecoversKey leaves a few key bits free and checks
+// that the key found encrypts the plaintext to the ciphertext, and that a
+// wrong ciphertext has no solution.
+func TestModelRecoversKey(t *testing.T) {
+	p := Serpent1
+	p.Rounds = 1
+	r := rand.New(rand.NewSource(2))
+	key := BitstringFromBytes(randomBytes(r, 32))
+	c, _ := New(p, key)
+	cipherText := c.Encrypt(testPlainText)
+
+	partial := []byte(key)
+	for _, i := range []int{3, 40, 77, 130, 201, 255} {
+		partial[i] = 'x'
+	}
+	m, _ := NewModel(p)
+	m.FixKey(Bitstring(partial))
+	m.FixPlaintext(testPlainText)
+	m.FixCiphertext(cipherText)
+	solution := solveModel(t, m)
+	if solution == nil {
+		t.Fatalf("no solution\n")
+	}
+	found, _ := New(p, modelBits(solution, m.Key[:]))
+	if found.Encrypt(testPlainText) != cipherText {
+		t.Errorf("recovered key does not encrypt to the ciphertext\n")
+	}
+
+	wrong := []byte(cipherText)
+	wrong[0] ^= 1
+	m, _ = NewModel(p)
+	m.FixKey(key)
+	m.FixPlaintext(testPlainText)
+	m.FixCiphertext(Bitstring(wrong))
+	if solveModel(t, m) != nil {
+		t.Errorf("wrong ciphertext is satisfiable\n")
+	}
+}
+
+// Function TestModelSMTLIB checks the shape of the SMT-LIB2 output.
+func TestModelSMTLIB(t *testing.T) {
+	p := Serpent1
+	p.Rounds = 1
+	m, _ := NewModel(p)
+	m.FixPlaintext(testPlainText)
+	var buf bytes.Buffer
+	if err := m.WriteSMTLIB(&buf); err != nil {
+		t.Fatalf("WriteSMTLIB failed: %v\n", err)
+	}
+	s := buf.String()
+	if strings.Count(s, "(") != strings.Count(s, ")") {
+		t.Errorf("unbalanced parentheses\n")
+	}
+	if n := strings.Count(s, "(declare-const v"); n != m.Vars() {
+		t.Errorf("%d variables declared, want %d\n", n, m.Vars())
+	}
+	if !strings.Contains(s, "(check-sat)") {
+		t.Errorf("missing check-sat\n")
+	}
+}
+
+// Function solveModel writes 'm' as DIMACS, reads it back and solves it,
+// returning nil when it is unsatisfiable.
+func solveModel(t *testing.T, m *Model) []bool {
+	var buf bytes.Buffer
+	if err := m.WriteDIMACS(&buf); err != nil {
+		t.Fatalf("WriteDIMACS failed: %v\n", err)
+	}
+	vars, clauses := parseDIMACS(t, &buf)
+	return newTestSolver(vars, clauses).solve()
+}
+
+// Function modelBits reads the variables 'vars' of a solution as a
+// Bitstring.
+func modelBits(solution []bool, vars []int) Bitstring {
+	b := make([]byte, len(vars))
+	for i, v := range vars {
+		b[i] = '0'
+		if solution[v] {
+			b[i] = '1'
+		}
+	}
+	return Bitstring(b)
+}
+
+func parseDIMACS(t *testing.T, buf *bytes.Buffer) (int, [][]int) {
+	var vars int
+	var clauses [][]int
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if fields[0] == "p" {
+			vars, _ = strconv.Atoi(fields[2])
+			continue
+		}
+		var clause []int
+		for _, f := range fields {
+			l, err := strconv.Atoi(f)
+			if err != nil {
+				t.Fatalf("bad literal %q\n", f)
+			}
+			if l == 0 {
+				break
+			}
+			clause = append(clause, l)
+		}
+		clauses = append(clauses, clause)
+	}
+	return vars, clauses
+}
+
+// A testSolver is a small DPLL solver with two watched literals per
+// clause, enough for the tiny instances of the tests.
+type testSolver struct {
+	clauses [][]int
+	watches map[int][]int
+	value   []int8
+	trail   []int
+}
+
+func newTestSolver(vars int, clauses [][]int) *testSolver {
+	s := &testSolver{clauses: clauses, watches: map[int][]int{},
+		value: make([]int8, vars+1)}
+	for i, c := range clauses {
+		s.watches[c[0]] = append(s.watches[c[0]], i)
+		if len(c) > 1 {
+			s.watches[c[1]] = append(s.watches[c[1]], i)
+		}
+	}
+	return s
+}
+
+func (s *testSolver) lit(l int) int8 {
+	if l > 0 {
+		return s.value[l]
+	}
+	return -s.value[-l]
+}
+
+func (s *testSolver) assign(l int) {
+	if l > 0 {
+		s.value[l] = 1
+	} else {
+		s.value[-l] = -1
+	}
+	s.trail = append(s.trail, l)
+}
+
+// Method propagate assigns the literals implied by the trail from 'head'
+// onwards and reports whether no clause became false.
+func (s *testSolver) propagate(head int) bool {
+	for ; head < len(s.trail); head++ {
+		falsified := -s.trail[head]
+		watching := s.watches[falsified]
+		kept := watching[:0]
+		ok := true
+		for k, ci := range watching {
+			if !ok {
+				kept = append(kept, watching[k:]...)
+				break
+			}
+			c := s.clauses[ci]
+			if len(c) == 1 {
+				kept = append(kept, ci)
+				ok = false
+				continue
+			}
+			if c[0] == falsified {
+				c[0], c[1] = c[1], c[0]
+			}
+			if s.lit(c[0]) == 1 {
+				kept = append(kept, ci)
+				continue
+			}
+			moved := false
+			for j := 2; j < len(c); j++ {
+				if s.lit(c[j]) != -1 {
+					c[1], c[j] = c[j], c[1]
+					s.watches[c[1]] = append(s.watches[c[1]], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			kept = append(kept, ci)
+			if s.lit(c[0]) == -1 {
+				ok = false
+			} else {
+				s.assign(c[0])
+			}
+		}
+		s.watches[falsified] = kept
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *testSolver) undo(n int) {
+	for _, l := range s.trail[n:] {
+		if l > 0 {
+			s.value[l] = 0
+		} else {
+			s.value[-l] = 0
+		}
+	}
+	s.trail = s.trail[:n]
+}
+
+func (s *testSolver) solve() []bool {
+	for _, c := range s.clauses {
+		if len(c) == 1 {
+			switch s.lit(c[0]) {
+			case -1:
+				return nil
+			case 0:
+				s.assign(c[0])
+			}
+		}
+	}
+	if !s.propagate(0) || !s.search() {
+		return nil
+	}
+	solution := make([]bool, len(s.value))
+	for v := range s.value {
+		solution[v] = s.value[v] == 1
+	}
+	return solution
+}
+
+func (s *testSolver) search() bool {
+	v := 1
+	for v < len(s.value) && s.value[v] != 0 {
+		v++
+	}
+	if v == len(s.value) {
+		return true
+	}
+	for _, l := range []int{-v, v} {
+		n := len(s.trail)
+		s.assign(l)
+		if s.propagate(n) && s.search() {
+			return true
+		}
+		s.undo(n)
+	}
+	return false
+}
@@ -0,0 +1,297 @@
+package serpent
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A SBoxTable is a 16x16 table indexed by input and output patterns of a
+// 4-bit S-Box, such as its difference distribution table.
+type SBoxTable [16][16]int
+
+// An ANF is the algebraic normal form of a boolean function of the 4 input
+// bits of an S-Box. Element m is 1 when the monomial made of the input
+// bits set in m (bit j standing for x_j) is present, element 0 being the
+// constant term.
+type ANF [16]int
+
+// SBoxReport collects the cryptographic properties of one S-Box.
+type SBoxReport struct {
+	SBox                   SBox      `json:"sbox"`
+	DDT                    SBoxTable `json:"ddt"`
+	LAT                    SBoxTable `json:"lat"`
+	BCT                    SBoxTable `json:"bct"`
+	ANF                    []string  `json:"anf"`
+	AlgebraicDegree        int       `json:"algebraic_degree"`
+	DifferentialUniformity int       `json:"differential_uniformity"`
+	Nonlinearity           int       `json:"nonlinearity"`
+	BoomerangUniformity    int       `json:"boomerang_uniformity"`
+	FixedPoints            []int     `json:"fixed_points"`
+}
+
+// The analysis methods below require a valid S-Box, a permutation of
+// 0..15, and panic on anything else. Validate checks an S-Box taken from
+// outside the package before it is analysed.
+
+// Method Validate checks that the S-Box has 16 entries forming a
+// permutation of 0..15.
+func (sbox SBox) Validate() error {
+	if len(sbox) != 16 {
+		return fmt.Errorf("serpent: S-Box has %d entries, want 16",
+			len(sbox))
+	}
+	var seen [16]bool
+	for x, y := range sbox {
+		if y < 0 || y > 15 {
+			return fmt.Errorf("serpent: S-Box maps %d to %d, outside "+
+				"0..15", x, y)
+		}
+		if seen[y] {
+			return fmt.Errorf("serpent: S-Box maps more than one input "+
+				"to %d", y)
+		}
+		seen[y] = true
+	}
+	return nil
+}
+
+// Method Inverse returns the inverse S-Box. The S-Box must be a
+// permutation.
+func (sbox SBox) Inverse() SBox {
+	return SBox(Permutation(sbox).Inverse())
+}
+
+// Method DDT returns the difference distribution table: entry [a][b]
+// counts the inputs x for which S(x) xor S(x xor a) equals b.
+func (sbox SBox) DDT() (t SBoxTable) {
+	for a := 0; a < 16; a++ {
+		for x := 0; x < 16; x++ {
+			t[a][sbox[x]^sbox[x^a]]++
+		}
+	}
+	return
+}
+
+// Method LAT returns the linear approximation table: entry [a][b] is the
+// number of inputs x for which a.x equals b.S(x), minus 8.
+func (sbox SBox) LAT() (t SBoxTable) {
+	for a := 0; a < 16; a++ {
+		for b := 0; b < 16; b++ {
+			t[a][b] = -8
+			for x := 0; x < 16; x++ {
+				if parity(a&x) == parity(b&sbox[x]) {
+					t[a][b]++
+				}
+			}
+		}
+	}
+	return
+}
+
+// Method BCT returns the boomerang connectivity table: entry [a][b]
+// counts the inputs x for which
+// S^-1(S(x) xor b) xor S^-1(S(x xor a) xor b) equals a.
+func (sbox SBox) BCT() (t SBoxTable) {
+	inverse := sbox.Inverse()
+	for a := 0; a < 16; a++ {
+		for b := 0; b < 16; b++ {
+			for x := 0; x < 16; x++ {
+				if inverse[sbox[x]^b]^inverse[sbox[x^a]^b] == a {
+					t[a][b]++
+				}
+			}
+		}
+	}
+	return
+}
+
+// Method ANF returns the algebraic normal form of each of the 4 output
+// bits, least significant bit first.
+func (sbox SBox) ANF() (result [4]ANF) {
+	for j := 0; j < 4; j++ {
+		// The Moebius transform of the truth table of output bit j.
+		for x := 0; x < 16; x++ {
+			result[j][x] = (sbox[x] >> uint(j)) & 1
+		}
+		for step := 1; step < 16; step <<= 1 {
+			for x := 0; x < 16; x++ {
+				if x&step != 0 {
+					result[j][x] ^= result[j][x^step]
+				}
+			}
+		}
+	}
+	return
+}
+
+// Method AlgebraicDegree returns the highest algebraic degree of the
+// output bits.
+func (sbox SBox) AlgebraicDegree() (degree int) {
+	for _, f := range sbox.ANF() {
+		if d := f.Degree(); d > degree {
+			degree = d
+		}
+	}
+	return
+}
+
+// Method DifferentialUniformity returns the largest entry of the DDT for
+// a non-zero input difference.
+func (sbox SBox) DifferentialUniformity() int {
+	return sbox.DDT().max(1, 0)
+}
+
+// Method Nonlinearity returns the smallest distance between a non-zero
+// linear combination of the output bits and the affine functions.
+func (sbox SBox) Nonlinearity() int {
+	lat := sbox.LAT()
+	linearity := 0
+	for a := 0; a < 16; a++ {
+		for b := 1; b < 16; b++ {
+			if l := abs(lat[a][b]); l > linearity {
+				linearity = l
+			}
+		}
+	}
+	return 8 - linearity
+}
+
+// Method BoomerangUniformity returns the largest entry of the BCT outside
+// of the first row and column, which are always 16.
+func (sbox SBox) BoomerangUniformity() int {
+	return sbox.BCT().max(1, 1)
+}
+
+// Method FixedPoints returns the inputs that the S-Box maps onto
+// themselves.
+func (sbox SBox) FixedPoints() []int {
+	result := []int{}
+	for x, y := range sbox {
+		if x == y {
+			result = append(result, x)
+		}
+	}
+	return result
+}
+
+// Method Report computes every property of the S-Box.
+func (sbox SBox) Report() SBoxReport {
+	r := SBoxReport{
+		SBox:                   sbox,
+		DDT:                    sbox.DDT(),
+		LAT:                    sbox.LAT(),
+		BCT:                    sbox.BCT(),
+		AlgebraicDegree:        sbox.AlgebraicDegree(),
+		DifferentialUniformity: sbox.DifferentialUniformity(),
+		Nonlinearity:           sbox.Nonlinearity(),
+		BoomerangUniformity:    sbox.BoomerangUniformity(),
+		FixedPoints:            sbox.FixedPoints(),
+	}
+	for _, f := range sbox.ANF() {
+		r.ANF = append(r.ANF, f.String())
+	}
+	return r
+}
+
+// Method max returns the largest entry of the table from row 'a' and
+// column 'b' onwards.
+func (t SBoxTable) max(a, b int) (m int) {
+	for i := a; i < 16; i++ {
+		for j := b; j < 16; j++ {
+			if t[i][j] > m {
+				m = t[i][j]
+			}
+		}
+	}
+	return
+}
+
+// Method WriteCSV writes the table as 16 rows of 16 comma separated values.
+func (t SBoxTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	for _, row := range t {
+		record := make([]string, len(row))
+		for j, v := range row {
+			record[j] = strconv.Itoa(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Method Degree returns the algebraic degree of the function, -1 for the
+// constant zero function.
+func (f ANF) Degree() int {
+	degree := -1
+	for m, c := range f {
+		if c == 1 {
+			if d := hammingWeight(m); d > degree {
+				degree = d
+			}
+		}
+	}
+	return degree
+}
+
+// Method Eval evaluates the function on the 4-bit input 'x'.
+func (f ANF) Eval(x int) (result int) {
+	for m, c := range f {
+		if c == 1 && x&m == m {
+			result ^= 1
+		}
+	}
+	return
+}
+
+// Method String formats the function as a sum of monomials such as
+// "x0x1 + x2 + 1".
+func (f ANF) String() string {
+	var terms []string
+	for m := 15; m >= 0; m-- {
+		if f[m] == 0 {
+			continue
+		}
+		if m == 0 {
+			terms = append(terms, "1")
+			continue
+		}
+		term := ""
+		for j := 0; j < 4; j++ {
+			if m&(1<<uint(j)) != 0 {
+				term += "x" + strconv.Itoa(j)
+			}
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " + ")
+}
+
+// Function parity returns the xor of the bits of 'x'.
+func parity(x int) int {
+	return hammingWeight(x) & 1
+}
+
+// Function hammingWeight returns the number of bits set in the
+// non-negative 'x'.
+func hammingWeight(x int) (n int) {
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
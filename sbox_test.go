@@ -0,0 +1,120 @@
+package serpent
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Function TestSBoxProperties checks the published properties of the
+// Serpent S-Boxes: differential uniformity 4, nonlinearity 4 and algebraic
+// degree 3.
+func TestSBoxProperties(t *testing.T) {
+	for i, sbox := range SBoxDecimalTable {
+		if d := sbox.DifferentialUniformity(); d != 4 {
+			t.Errorf("S%d: differential uniformity %d, want 4\n", i, d)
+		}
+		if n := sbox.Nonlinearity(); n != 4 {
+			t.Errorf("S%d: nonlinearity %d, want 4\n", i, n)
+		}
+		if d := sbox.AlgebraicDegree(); d != 3 {
+			t.Errorf("S%d: algebraic degree %d, want 3\n", i, d)
+		}
+	}
+}
+
+// Function TestSBoxTables checks structural properties of the DDT, LAT
+// and BCT that hold for any permutation.
+func TestSBoxTables(t *testing.T) {
+	for i, sbox := range SBoxDecimalTable {
+		ddt, lat, bct := sbox.DDT(), sbox.LAT(), sbox.BCT()
+		if ddt[0][0] != 16 || lat[0][0] != 8 || bct[0][0] != 16 {
+			t.Errorf("S%d: wrong trivial entries\n", i)
+		}
+		for a := 0; a < 16; a++ {
+			sum := 0
+			for b := 0; b < 16; b++ {
+				sum += ddt[a][b]
+				if ddt[a][b]%2 != 0 {
+					t.Errorf("S%d: odd DDT entry at [%d][%d]\n", i, a, b)
+				}
+				if bct[a][b] < ddt[a][b] {
+					t.Errorf("S%d: BCT below DDT at [%d][%d]\n", i, a, b)
+				}
+			}
+			if sum != 16 {
+				t.Errorf("S%d: DDT row %d sums to %d\n", i, a, sum)
+			}
+		}
+	}
+}
+
+// Function TestSBoxValidate checks that Validate accepts the Serpent
+// S-Boxes and rejects tables the analysis methods cannot handle.
+func TestSBoxValidate(t *testing.T) {
+	for i, sbox := range SBoxDecimalTable {
+		if err := sbox.Validate(); err != nil {
+			t.Errorf("S%d: %v\n", i, err)
+		}
+	}
+	for _, sbox := range []SBox{
+		nil,
+		{0, 1, 2},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 16},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, -1},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 14},
+	} {
+		if sbox.Validate() == nil {
+			t.Errorf("%v is accepted\n", sbox)
+		}
+	}
+}
+
+// Function TestANF checks that the algebraic normal form evaluates back to
+// the S-Box.
+func TestANF(t *testing.T) {
+	for i, sbox := range SBoxDecimalTable {
+		anf := sbox.ANF()
+		for x := 0; x < 16; x++ {
+			y := 0
+			for j := 0; j < 4; j++ {
+				y |= anf[j].Eval(x) << uint(j)
+			}
+			if y != sbox[x] {
+				t.Errorf("S%d: ANF gives %d for %d, want %d\n", i, y, x,
+					sbox[x])
+			}
+		}
+	}
+	var f ANF
+	f[1] = 1
+	f[6] = 1
+	if s := f.String(); s != "x1x2 + x0" {
+		t.Errorf("ANF formats as %q\n", s)
+	}
+}
+
+// Function TestSBoxReportExport checks the CSV and JSON exports.
+func TestSBoxReportExport(t *testing.T) {
+	r := SBoxDecimalTable[0].Report()
+	var buf bytes.Buffer
+	if err := r.DDT.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v\n", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 16 || !strings.HasPrefix(lines[0], "16,0,0") {
+		t.Errorf("unexpected CSV output\n%s", buf.String())
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %v\n", err)
+	}
+	var back SBoxReport
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v\n", err)
+	}
+	if back.LAT != r.LAT || back.Nonlinearity != r.Nonlinearity {
+		t.Errorf("JSON round trip lost data\n")
+	}
+}
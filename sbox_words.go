@@ -0,0 +1,857 @@
+// Code generated by "serpent sboxcircuit -o sbox_words.go"; DO NOT EDIT.
+
+package serpent
+
+// Function sbox0Words applies S0 in bitslice form.
+// 23 gates (7 AND, 3 OR, 12 XOR, 1 INV), depth 7
+func sbox0Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w2 ^ w1
+	w5 := w0 | w3
+	w6 := w4 ^ w5
+	w7 := w0 & w1
+	w8 := w1 ^ w7
+	w9 := w0 & w2
+	w10 := w8 ^ w9
+	w11 := w7 & w2
+	w12 := w10 ^ w11
+	w13 := w12 ^ w3
+	w14 := w1 & w3
+	w15 := w13 ^ w14
+	w16 := w1 & w2
+	w17 := w16 & w3
+	w18 := w15 ^ w17
+	w19 := w0 ^ w14
+	w20 := w19 | w16
+	w21 := ^w9
+	w22 := w21 | w3
+	w23 := w20 ^ w22
+	w24 := w21 & w4
+	w25 := w24 ^ w23
+	w26 := w25 ^ w15
+	x[0], x[1], x[2], x[3] = w26, w23, w18, w6
+}
+
+// Function sboxInverse0Words applies the inverse of S0 in bitslice form.
+// 22 gates (6 AND, 2 OR, 13 XOR, 1 INV), depth 6
+func sboxInverse0Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w3 ^ w2
+	w5 := w1 | w0
+	w6 := ^w5
+	w7 := w4 ^ w6
+	w8 := w0 ^ w1
+	w9 := w8 ^ w2
+	w10 := w0 & w2
+	w11 := w9 ^ w10
+	w12 := w1 & w3
+	w13 := w11 ^ w12
+	w14 := w10 & w3
+	w15 := w13 ^ w14
+	w16 := w1 & w2
+	w17 := w16 & w3
+	w18 := w15 ^ w17
+	w19 := w4 ^ w12
+	w20 := w8 ^ w7
+	w21 := w20 | w13
+	w22 := w19 ^ w21
+	w23 := w8 ^ w3
+	w24 := w23 & w0
+	w25 := w24 ^ w21
+	x[0], x[1], x[2], x[3] = w22, w18, w7, w25
+}
+
+// Function sbox1Words applies S1 in bitslice form.
+// 24 gates (7 AND, 3 OR, 12 XOR, 2 INV), depth 7
+func sbox1Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w2 ^ w3
+	w5 := ^w1
+	w6 := w5 | w0
+	w7 := w4 ^ w6
+	w8 := w0 ^ w1
+	w9 := w1 & w2
+	w10 := w8 ^ w9
+	w11 := w0 & w3
+	w12 := w10 ^ w11
+	w13 := w2 & w3
+	w14 := w12 ^ w13
+	w15 := w0 & w2
+	w16 := w15 & w3
+	w17 := w14 ^ w16
+	w18 := w9 & w3
+	w19 := w17 ^ w18
+	w20 := ^w19
+	w21 := w8 ^ w7
+	w22 := w1 ^ w2
+	w23 := w22 | w14
+	w24 := w21 ^ w23
+	w25 := w3 & w8
+	w26 := w25 | w21
+	w27 := w15 ^ w26
+	x[0], x[1], x[2], x[3] = w20, w27, w7, w24
+}
+
+// Function sboxInverse1Words applies the inverse of S1 in bitslice form.
+// 17 gates (3 AND, 4 OR, 9 XOR, 1 INV), depth 10
+func sboxInverse1Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w2 ^ w1
+	w5 := w4 ^ w0
+	w6 := w1 | w3
+	w7 := w5 ^ w6
+	w8 := w0 & w1
+	w9 := w8 | w4
+	w10 := w7 | w2
+	w11 := w10 & w3
+	w12 := w9 ^ w11
+	w13 := ^w2
+	w14 := w13 ^ w12
+	w15 := w3 | w0
+	w16 := w15 ^ w8
+	w17 := w14 ^ w16
+	w18 := w12 & w17
+	w19 := w14 ^ w7
+	w20 := w18 ^ w19
+	x[0], x[1], x[2], x[3] = w17, w12, w20, w7
+}
+
+// Function sbox2Words applies S2 in bitslice form.
+// 14 gates (3 AND, 3 OR, 7 XOR, 1 INV), depth 8
+func sbox2Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w2 ^ w1
+	w5 := w2 & w0
+	w6 := w5 ^ w3
+	w7 := w4 ^ w6
+	w8 := w7 ^ w0
+	w9 := ^w6
+	w10 := w9 | w1
+	w11 := w8 ^ w10
+	w12 := w2 & w9
+	w13 := w0 ^ w4
+	w14 := w13 & w10
+	w15 := w12 | w14
+	w16 := w8 | w15
+	w17 := w16 ^ w12
+	x[0], x[1], x[2], x[3] = w7, w15, w17, w11
+}
+
+// Function sboxInverse2Words applies the inverse of S2 in bitslice form.
+// 17 gates (4 AND, 5 OR, 7 XOR, 1 INV), depth 9
+func sboxInverse2Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w3 ^ w0
+	w5 := w2 ^ w3
+	w6 := w5 | w1
+	w7 := w4 ^ w6
+	w8 := w2 | w7
+	w9 := w8 & w5
+	w10 := w7 | w3
+	w11 := w10 & w1
+	w12 := w9 ^ w11
+	w13 := w1 | w3
+	w14 := ^w13
+	w15 := w0 ^ w12
+	w16 := w15 & w8
+	w17 := w14 | w16
+	w18 := w2 & w3
+	w19 := w18 ^ w15
+	w20 := w14 ^ w19
+	x[0], x[1], x[2], x[3] = w7, w12, w20, w17
+}
+
+// Function sbox3Words applies S3 in bitslice form.
+// 20 gates (5 AND, 2 OR, 13 XOR, 0 INV), depth 10
+func sbox3Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 & w1
+	w5 := w0 ^ w4
+	w6 := w5 ^ w2
+	w7 := w4 & w2
+	w8 := w6 ^ w7
+	w9 := w8 ^ w3
+	w10 := w1 & w3
+	w11 := w9 ^ w10
+	w12 := w4 & w3
+	w13 := w11 ^ w12
+	w14 := w1 ^ w2
+	w15 := w5 | w3
+	w16 := w15 & w9
+	w17 := w14 ^ w16
+	w18 := w15 ^ w4
+	w19 := w18 ^ w8
+	w20 := w19 ^ w17
+	w21 := w15 ^ w11
+	w22 := w13 | w20
+	w23 := w21 ^ w22
+	x[0], x[1], x[2], x[3] = w23, w20, w13, w17
+}
+
+// Function sboxInverse3Words applies the inverse of S3 in bitslice form.
+// 17 gates (3 AND, 4 OR, 10 XOR, 0 INV), depth 7
+func sboxInverse3Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 | w3
+	w5 := w4 ^ w2
+	w6 := w3 | w2
+	w7 := w6 & w1
+	w8 := w5 ^ w7
+	w9 := w1 ^ w4
+	w10 := w9 & w5
+	w11 := w0 ^ w3
+	w12 := w10 ^ w11
+	w13 := w6 ^ w11
+	w14 := w12 & w0
+	w15 := w14 | w1
+	w16 := w13 ^ w15
+	w17 := w14 ^ w5
+	w18 := w13 | w9
+	w19 := w18 ^ w6
+	w20 := w17 ^ w19
+	x[0], x[1], x[2], x[3] = w8, w20, w12, w16
+}
+
+// Function sbox4Words applies S4 in bitslice form.
+// 18 gates (5 AND, 4 OR, 8 XOR, 1 INV), depth 8
+func sbox4Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w2 | w1
+	w5 := w4 ^ w0
+	w6 := w1 | w0
+	w7 := w6 & w3
+	w8 := w5 ^ w7
+	w9 := w1 ^ w3
+	w10 := w9 & w8
+	w11 := w1 & w2
+	w12 := w11 | w5
+	w13 := w10 ^ w12
+	w14 := w8 & w1
+	w15 := w14 ^ w2
+	w16 := ^w3
+	w17 := w16 | w0
+	w18 := w15 ^ w17
+	w19 := w14 ^ w9
+	w20 := w18 & w12
+	w21 := w19 ^ w20
+	x[0], x[1], x[2], x[3] = w18, w21, w13, w8
+}
+
+// Function sboxInverse4Words applies the inverse of S4 in bitslice form.
+// 20 gates (6 AND, 3 OR, 10 XOR, 1 INV), depth 9
+func sboxInverse4Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 & w1
+	w5 := w1 ^ w4
+	w6 := w5 ^ w2
+	w7 := w0 & w3
+	w8 := w6 ^ w7
+	w9 := w4 & w3
+	w10 := w8 ^ w9
+	w11 := w2 & w3
+	w12 := w10 ^ w11
+	w13 := w1 ^ w8
+	w14 := w2 & w0
+	w15 := w14 | w3
+	w16 := w13 ^ w15
+	w17 := ^w0
+	w18 := w17 | w16
+	w19 := w3 ^ w12
+	w20 := w18 ^ w19
+	w21 := w10 & w15
+	w22 := w21 | w4
+	w23 := w22 ^ w20
+	x[0], x[1], x[2], x[3] = w20, w16, w23, w12
+}
+
+// Function sbox5Words applies S5 in bitslice form.
+// 19 gates (3 AND, 3 OR, 12 XOR, 1 INV), depth 13
+func sbox5Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 & w1
+	w5 := w1 ^ w4
+	w6 := w5 ^ w2
+	w7 := w6 ^ w3
+	w8 := w0 & w3
+	w9 := w7 ^ w8
+	w10 := w1 & w3
+	w11 := w9 ^ w10
+	w12 := ^w11
+	w13 := w3 ^ w0
+	w14 := w13 ^ w1
+	w15 := w3 | w12
+	w16 := w14 ^ w15
+	w17 := w16 ^ w3
+	w18 := w13 ^ w6
+	w19 := w18 | w11
+	w20 := w17 ^ w19
+	w21 := w14 | w20
+	w22 := w21 ^ w18
+	x[0], x[1], x[2], x[3] = w12, w16, w20, w22
+}
+
+// Function sboxInverse5Words applies the inverse of S5 in bitslice form.
+// 18 gates (6 AND, 1 OR, 10 XOR, 1 INV), depth 7
+func sboxInverse5Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w1 & w2
+	w5 := w0 ^ w4
+	w6 := w5 ^ w3
+	w7 := w0 & w1
+	w8 := w7 & w3
+	w9 := w6 ^ w8
+	w10 := w7 | w2
+	w11 := ^w10
+	w12 := w0 & w3
+	w13 := w12 ^ w1
+	w14 := w11 ^ w13
+	w15 := w10 & w0
+	w16 := w15 ^ w9
+	w17 := w13 ^ w7
+	w18 := w16 ^ w17
+	w19 := w5 ^ w10
+	w20 := w17 & w16
+	w21 := w19 ^ w20
+	x[0], x[1], x[2], x[3] = w9, w18, w21, w14
+}
+
+// Function sbox6Words applies S6 in bitslice form.
+// 24 gates (7 AND, 3 OR, 13 XOR, 1 INV), depth 14
+func sbox6Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := ^w1
+	w5 := w0 & w3
+	w6 := w5 ^ w2
+	w7 := w4 ^ w6
+	w8 := w0 & w1
+	w9 := w1 ^ w8
+	w10 := w9 ^ w2
+	w11 := w0 & w2
+	w12 := w10 ^ w11
+	w13 := w8 & w2
+	w14 := w12 ^ w13
+	w15 := w14 ^ w3
+	w16 := w2 & w3
+	w17 := w15 ^ w16
+	w18 := w1 & w2
+	w19 := w18 & w3
+	w20 := w17 ^ w19
+	w21 := w15 ^ w0
+	w22 := w20 | w14
+	w23 := w22 | w4
+	w24 := w21 ^ w23
+	w25 := w24 ^ w22
+	w26 := w25 | w8
+	w27 := w26 ^ w2
+	x[0], x[1], x[2], x[3] = w24, w7, w27, w20
+}
+
+// Function sboxInverse6Words applies the inverse of S6 in bitslice form.
+// 24 gates (8 AND, 4 OR, 10 XOR, 2 INV), depth 7
+func sboxInverse6Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w3 ^ w1
+	w5 := ^w2
+	w6 := w5 | w0
+	w7 := w4 ^ w6
+	w8 := w0 ^ w1
+	w9 := w1 & w2
+	w10 := w8 ^ w9
+	w11 := w1 & w3
+	w12 := w10 ^ w11
+	w13 := w0 & w1
+	w14 := w13 & w3
+	w15 := w12 ^ w14
+	w16 := w2 & w3
+	w17 := w15 ^ w16
+	w18 := w9 & w3
+	w19 := w17 ^ w18
+	w20 := ^w19
+	w21 := w2 | w13
+	w22 := w21 ^ w7
+	w23 := w11 | w10
+	w24 := w22 ^ w23
+	w25 := w5 & w22
+	w26 := w3 & w15
+	w27 := w25 | w26
+	x[0], x[1], x[2], x[3] = w24, w7, w20, w27
+}
+
+// Function sbox7Words applies S7 in bitslice form.
+// 21 gates (6 AND, 4 OR, 10 XOR, 1 INV), depth 7
+func sbox7Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 ^ w1
+	w5 := w4 ^ w2
+	w6 := w0 & w2
+	w7 := w5 ^ w6
+	w8 := w0 & w1
+	w9 := w8 & w2
+	w10 := w7 ^ w9
+	w11 := w0 & w3
+	w12 := w10 ^ w11
+	w13 := w3 | w8
+	w14 := w13 ^ w0
+	w15 := w2 | w12
+	w16 := w14 ^ w15
+	w17 := ^w2
+	w18 := w17 | w11
+	w19 := w1 | w5
+	w20 := w19 & w13
+	w21 := w18 ^ w20
+	w22 := w11 ^ w13
+	w23 := w22 & w21
+	w24 := w23 ^ w5
+	x[0], x[1], x[2], x[3] = w21, w16, w24, w12
+}
+
+// Function sboxInverse7Words applies the inverse of S7 in bitslice form.
+// 18 gates (4 AND, 6 OR, 7 XOR, 1 INV), depth 6
+func sboxInverse7Words(x *[4]uint32) {
+	w0, w1, w2, w3 := x[0], x[1], x[2], x[3]
+	w4 := w0 | w1
+	w5 := w4 & w3
+	w6 := w0 & w1
+	w7 := w6 | w2
+	w8 := w5 ^ w7
+	w9 := w1 ^ w3
+	w10 := w9 | w6
+	w11 := w0 | w3
+	w12 := w11 & w2
+	w13 := w10 ^ w12
+	w14 := ^w9
+	w15 := w14 ^ w0
+	w16 := w4 & w9
+	w17 := w16 | w7
+	w18 := w15 ^ w17
+	w19 := w2 ^ w16
+	w20 := w18 | w3
+	w21 := w19 ^ w20
+	x[0], x[1], x[2], x[3] = w21, w18, w13, w8
+}
+
+var wordSBoxes = [8]func(*[4]uint32){
+	sbox0Words,
+	sbox1Words,
+	sbox2Words,
+	sbox3Words,
+	sbox4Words,
+	sbox5Words,
+	sbox6Words,
+	sbox7Words,
+}
+
+var wordSBoxesInverse = [8]func(*[4]uint32){
+	sboxInverse0Words,
+	sboxInverse1Words,
+	sboxInverse2Words,
+	sboxInverse3Words,
+	sboxInverse4Words,
+	sboxInverse5Words,
+	sboxInverse6Words,
+	sboxInverse7Words,
+}
+
+// The circuits of sbox0Words to sbox7Words as data.
+var wordSBoxCircuits = [8]*SBoxCircuit{
+	{
+		Gates: []Gate{
+			{XOR, [2]int{2, 1}, 4},
+			{OR, [2]int{0, 3}, 5},
+			{XOR, [2]int{4, 5}, 6},
+			{AND, [2]int{0, 1}, 7},
+			{XOR, [2]int{1, 7}, 8},
+			{AND, [2]int{0, 2}, 9},
+			{XOR, [2]int{8, 9}, 10},
+			{AND, [2]int{7, 2}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{XOR, [2]int{12, 3}, 13},
+			{AND, [2]int{1, 3}, 14},
+			{XOR, [2]int{13, 14}, 15},
+			{AND, [2]int{1, 2}, 16},
+			{AND, [2]int{16, 3}, 17},
+			{XOR, [2]int{15, 17}, 18},
+			{XOR, [2]int{0, 14}, 19},
+			{OR, [2]int{19, 16}, 20},
+			{INV, [2]int{9, 0}, 21},
+			{OR, [2]int{21, 3}, 22},
+			{XOR, [2]int{20, 22}, 23},
+			{AND, [2]int{21, 4}, 24},
+			{XOR, [2]int{24, 23}, 25},
+			{XOR, [2]int{25, 15}, 26},
+		},
+		Outputs: [4]int{26, 23, 18, 6},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{2, 3}, 4},
+			{INV, [2]int{1, 0}, 5},
+			{OR, [2]int{5, 0}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{XOR, [2]int{0, 1}, 8},
+			{AND, [2]int{1, 2}, 9},
+			{XOR, [2]int{8, 9}, 10},
+			{AND, [2]int{0, 3}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{AND, [2]int{2, 3}, 13},
+			{XOR, [2]int{12, 13}, 14},
+			{AND, [2]int{0, 2}, 15},
+			{AND, [2]int{15, 3}, 16},
+			{XOR, [2]int{14, 16}, 17},
+			{AND, [2]int{9, 3}, 18},
+			{XOR, [2]int{17, 18}, 19},
+			{INV, [2]int{19, 0}, 20},
+			{XOR, [2]int{8, 7}, 21},
+			{XOR, [2]int{1, 2}, 22},
+			{OR, [2]int{22, 14}, 23},
+			{XOR, [2]int{21, 23}, 24},
+			{AND, [2]int{3, 8}, 25},
+			{OR, [2]int{25, 21}, 26},
+			{XOR, [2]int{15, 26}, 27},
+		},
+		Outputs: [4]int{20, 27, 7, 24},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{2, 1}, 4},
+			{AND, [2]int{2, 0}, 5},
+			{XOR, [2]int{5, 3}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{XOR, [2]int{7, 0}, 8},
+			{INV, [2]int{6, 0}, 9},
+			{OR, [2]int{9, 1}, 10},
+			{XOR, [2]int{8, 10}, 11},
+			{AND, [2]int{2, 9}, 12},
+			{XOR, [2]int{0, 4}, 13},
+			{AND, [2]int{13, 10}, 14},
+			{OR, [2]int{12, 14}, 15},
+			{OR, [2]int{8, 15}, 16},
+			{XOR, [2]int{16, 12}, 17},
+		},
+		Outputs: [4]int{7, 15, 17, 11},
+	},
+	{
+		Gates: []Gate{
+			{AND, [2]int{0, 1}, 4},
+			{XOR, [2]int{0, 4}, 5},
+			{XOR, [2]int{5, 2}, 6},
+			{AND, [2]int{4, 2}, 7},
+			{XOR, [2]int{6, 7}, 8},
+			{XOR, [2]int{8, 3}, 9},
+			{AND, [2]int{1, 3}, 10},
+			{XOR, [2]int{9, 10}, 11},
+			{AND, [2]int{4, 3}, 12},
+			{XOR, [2]int{11, 12}, 13},
+			{XOR, [2]int{1, 2}, 14},
+			{OR, [2]int{5, 3}, 15},
+			{AND, [2]int{15, 9}, 16},
+			{XOR, [2]int{14, 16}, 17},
+			{XOR, [2]int{15, 4}, 18},
+			{XOR, [2]int{18, 8}, 19},
+			{XOR, [2]int{19, 17}, 20},
+			{XOR, [2]int{15, 11}, 21},
+			{OR, [2]int{13, 20}, 22},
+			{XOR, [2]int{21, 22}, 23},
+		},
+		Outputs: [4]int{23, 20, 13, 17},
+	},
+	{
+		Gates: []Gate{
+			{OR, [2]int{2, 1}, 4},
+			{XOR, [2]int{4, 0}, 5},
+			{OR, [2]int{1, 0}, 6},
+			{AND, [2]int{6, 3}, 7},
+			{XOR, [2]int{5, 7}, 8},
+			{XOR, [2]int{1, 3}, 9},
+			{AND, [2]int{9, 8}, 10},
+			{AND, [2]int{1, 2}, 11},
+			{OR, [2]int{11, 5}, 12},
+			{XOR, [2]int{10, 12}, 13},
+			{AND, [2]int{8, 1}, 14},
+			{XOR, [2]int{14, 2}, 15},
+			{INV, [2]int{3, 0}, 16},
+			{OR, [2]int{16, 0}, 17},
+			{XOR, [2]int{15, 17}, 18},
+			{XOR, [2]int{14, 9}, 19},
+			{AND, [2]int{18, 12}, 20},
+			{XOR, [2]int{19, 20}, 21},
+		},
+		Outputs: [4]int{18, 21, 13, 8},
+	},
+	{
+		Gates: []Gate{
+			{AND, [2]int{0, 1}, 4},
+			{XOR, [2]int{1, 4}, 5},
+			{XOR, [2]int{5, 2}, 6},
+			{XOR, [2]int{6, 3}, 7},
+			{AND, [2]int{0, 3}, 8},
+			{XOR, [2]int{7, 8}, 9},
+			{AND, [2]int{1, 3}, 10},
+			{XOR, [2]int{9, 10}, 11},
+			{INV, [2]int{11, 0}, 12},
+			{XOR, [2]int{3, 0}, 13},
+			{XOR, [2]int{13, 1}, 14},
+			{OR, [2]int{3, 12}, 15},
+			{XOR, [2]int{14, 15}, 16},
+			{XOR, [2]int{16, 3}, 17},
+			{XOR, [2]int{13, 6}, 18},
+			{OR, [2]int{18, 11}, 19},
+			{XOR, [2]int{17, 19}, 20},
+			{OR, [2]int{14, 20}, 21},
+			{XOR, [2]int{21, 18}, 22},
+		},
+		Outputs: [4]int{12, 16, 20, 22},
+	},
+	{
+		Gates: []Gate{
+			{INV, [2]int{1, 0}, 4},
+			{AND, [2]int{0, 3}, 5},
+			{XOR, [2]int{5, 2}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{AND, [2]int{0, 1}, 8},
+			{XOR, [2]int{1, 8}, 9},
+			{XOR, [2]int{9, 2}, 10},
+			{AND, [2]int{0, 2}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{AND, [2]int{8, 2}, 13},
+			{XOR, [2]int{12, 13}, 14},
+			{XOR, [2]int{14, 3}, 15},
+			{AND, [2]int{2, 3}, 16},
+			{XOR, [2]int{15, 16}, 17},
+			{AND, [2]int{1, 2}, 18},
+			{AND, [2]int{18, 3}, 19},
+			{XOR, [2]int{17, 19}, 20},
+			{XOR, [2]int{15, 0}, 21},
+			{OR, [2]int{20, 14}, 22},
+			{OR, [2]int{22, 4}, 23},
+			{XOR, [2]int{21, 23}, 24},
+			{XOR, [2]int{24, 22}, 25},
+			{OR, [2]int{25, 8}, 26},
+			{XOR, [2]int{26, 2}, 27},
+		},
+		Outputs: [4]int{24, 7, 27, 20},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{0, 1}, 4},
+			{XOR, [2]int{4, 2}, 5},
+			{AND, [2]int{0, 2}, 6},
+			{XOR, [2]int{5, 6}, 7},
+			{AND, [2]int{0, 1}, 8},
+			{AND, [2]int{8, 2}, 9},
+			{XOR, [2]int{7, 9}, 10},
+			{AND, [2]int{0, 3}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{OR, [2]int{3, 8}, 13},
+			{XOR, [2]int{13, 0}, 14},
+			{OR, [2]int{2, 12}, 15},
+			{XOR, [2]int{14, 15}, 16},
+			{INV, [2]int{2, 0}, 17},
+			{OR, [2]int{17, 11}, 18},
+			{OR, [2]int{1, 5}, 19},
+			{AND, [2]int{19, 13}, 20},
+			{XOR, [2]int{18, 20}, 21},
+			{XOR, [2]int{11, 13}, 22},
+			{AND, [2]int{22, 21}, 23},
+			{XOR, [2]int{23, 5}, 24},
+		},
+		Outputs: [4]int{21, 16, 24, 12},
+	},
+}
+
+// The circuits of sboxInverse0Words to sboxInverse7Words as data.
+var wordSBoxCircuitsInverse = [8]*SBoxCircuit{
+	{
+		Gates: []Gate{
+			{XOR, [2]int{3, 2}, 4},
+			{OR, [2]int{1, 0}, 5},
+			{INV, [2]int{5, 0}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{XOR, [2]int{0, 1}, 8},
+			{XOR, [2]int{8, 2}, 9},
+			{AND, [2]int{0, 2}, 10},
+			{XOR, [2]int{9, 10}, 11},
+			{AND, [2]int{1, 3}, 12},
+			{XOR, [2]int{11, 12}, 13},
+			{AND, [2]int{10, 3}, 14},
+			{XOR, [2]int{13, 14}, 15},
+			{AND, [2]int{1, 2}, 16},
+			{AND, [2]int{16, 3}, 17},
+			{XOR, [2]int{15, 17}, 18},
+			{XOR, [2]int{4, 12}, 19},
+			{XOR, [2]int{8, 7}, 20},
+			{OR, [2]int{20, 13}, 21},
+			{XOR, [2]int{19, 21}, 22},
+			{XOR, [2]int{8, 3}, 23},
+			{AND, [2]int{23, 0}, 24},
+			{XOR, [2]int{24, 21}, 25},
+		},
+		Outputs: [4]int{22, 18, 7, 25},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{2, 1}, 4},
+			{XOR, [2]int{4, 0}, 5},
+			{OR, [2]int{1, 3}, 6},
+			{XOR, [2]int{5, 6}, 7},
+			{AND, [2]int{0, 1}, 8},
+			{OR, [2]int{8, 4}, 9},
+			{OR, [2]int{7, 2}, 10},
+			{AND, [2]int{10, 3}, 11},
+			{XOR, [2]int{9, 11}, 12},
+			{INV, [2]int{2, 0}, 13},
+			{XOR, [2]int{13, 12}, 14},
+			{OR, [2]int{3, 0}, 15},
+			{XOR, [2]int{15, 8}, 16},
+			{XOR, [2]int{14, 16}, 17},
+			{AND, [2]int{12, 17}, 18},
+			{XOR, [2]int{14, 7}, 19},
+			{XOR, [2]int{18, 19}, 20},
+		},
+		Outputs: [4]int{17, 12, 20, 7},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{3, 0}, 4},
+			{XOR, [2]int{2, 3}, 5},
+			{OR, [2]int{5, 1}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{OR, [2]int{2, 7}, 8},
+			{AND, [2]int{8, 5}, 9},
+			{OR, [2]int{7, 3}, 10},
+			{AND, [2]int{10, 1}, 11},
+			{XOR, [2]int{9, 11}, 12},
+			{OR, [2]int{1, 3}, 13},
+			{INV, [2]int{13, 0}, 14},
+			{XOR, [2]int{0, 12}, 15},
+			{AND, [2]int{15, 8}, 16},
+			{OR, [2]int{14, 16}, 17},
+			{AND, [2]int{2, 3}, 18},
+			{XOR, [2]int{18, 15}, 19},
+			{XOR, [2]int{14, 19}, 20},
+		},
+		Outputs: [4]int{7, 12, 20, 17},
+	},
+	{
+		Gates: []Gate{
+			{OR, [2]int{0, 3}, 4},
+			{XOR, [2]int{4, 2}, 5},
+			{OR, [2]int{3, 2}, 6},
+			{AND, [2]int{6, 1}, 7},
+			{XOR, [2]int{5, 7}, 8},
+			{XOR, [2]int{1, 4}, 9},
+			{AND, [2]int{9, 5}, 10},
+			{XOR, [2]int{0, 3}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{XOR, [2]int{6, 11}, 13},
+			{AND, [2]int{12, 0}, 14},
+			{OR, [2]int{14, 1}, 15},
+			{XOR, [2]int{13, 15}, 16},
+			{XOR, [2]int{14, 5}, 17},
+			{OR, [2]int{13, 9}, 18},
+			{XOR, [2]int{18, 6}, 19},
+			{XOR, [2]int{17, 19}, 20},
+		},
+		Outputs: [4]int{8, 20, 12, 16},
+	},
+	{
+		Gates: []Gate{
+			{AND, [2]int{0, 1}, 4},
+			{XOR, [2]int{1, 4}, 5},
+			{XOR, [2]int{5, 2}, 6},
+			{AND, [2]int{0, 3}, 7},
+			{XOR, [2]int{6, 7}, 8},
+			{AND, [2]int{4, 3}, 9},
+			{XOR, [2]int{8, 9}, 10},
+			{AND, [2]int{2, 3}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{XOR, [2]int{1, 8}, 13},
+			{AND, [2]int{2, 0}, 14},
+			{OR, [2]int{14, 3}, 15},
+			{XOR, [2]int{13, 15}, 16},
+			{INV, [2]int{0, 0}, 17},
+			{OR, [2]int{17, 16}, 18},
+			{XOR, [2]int{3, 12}, 19},
+			{XOR, [2]int{18, 19}, 20},
+			{AND, [2]int{10, 15}, 21},
+			{OR, [2]int{21, 4}, 22},
+			{XOR, [2]int{22, 20}, 23},
+		},
+		Outputs: [4]int{20, 16, 23, 12},
+	},
+	{
+		Gates: []Gate{
+			{AND, [2]int{1, 2}, 4},
+			{XOR, [2]int{0, 4}, 5},
+			{XOR, [2]int{5, 3}, 6},
+			{AND, [2]int{0, 1}, 7},
+			{AND, [2]int{7, 3}, 8},
+			{XOR, [2]int{6, 8}, 9},
+			{OR, [2]int{7, 2}, 10},
+			{INV, [2]int{10, 0}, 11},
+			{AND, [2]int{0, 3}, 12},
+			{XOR, [2]int{12, 1}, 13},
+			{XOR, [2]int{11, 13}, 14},
+			{AND, [2]int{10, 0}, 15},
+			{XOR, [2]int{15, 9}, 16},
+			{XOR, [2]int{13, 7}, 17},
+			{XOR, [2]int{16, 17}, 18},
+			{XOR, [2]int{5, 10}, 19},
+			{AND, [2]int{17, 16}, 20},
+			{XOR, [2]int{19, 20}, 21},
+		},
+		Outputs: [4]int{9, 18, 21, 14},
+	},
+	{
+		Gates: []Gate{
+			{XOR, [2]int{3, 1}, 4},
+			{INV, [2]int{2, 0}, 5},
+			{OR, [2]int{5, 0}, 6},
+			{XOR, [2]int{4, 6}, 7},
+			{XOR, [2]int{0, 1}, 8},
+			{AND, [2]int{1, 2}, 9},
+			{XOR, [2]int{8, 9}, 10},
+			{AND, [2]int{1, 3}, 11},
+			{XOR, [2]int{10, 11}, 12},
+			{AND, [2]int{0, 1}, 13},
+			{AND, [2]int{13, 3}, 14},
+			{XOR, [2]int{12, 14}, 15},
+			{AND, [2]int{2, 3}, 16},
+			{XOR, [2]int{15, 16}, 17},
+			{AND, [2]int{9, 3}, 18},
+			{XOR, [2]int{17, 18}, 19},
+			{INV, [2]int{19, 0}, 20},
+			{OR, [2]int{2, 13}, 21},
+			{XOR, [2]int{21, 7}, 22},
+			{OR, [2]int{11, 10}, 23},
+			{XOR, [2]int{22, 23}, 24},
+			{AND, [2]int{5, 22}, 25},
+			{AND, [2]int{3, 15}, 26},
+			{OR, [2]int{25, 26}, 27},
+		},
+		Outputs: [4]int{24, 7, 20, 27},
+	},
+	{
+		Gates: []Gate{
+			{OR, [2]int{0, 1}, 4},
+			{AND, [2]int{4, 3}, 5},
+			{AND, [2]int{0, 1}, 6},
+			{OR, [2]int{6, 2}, 7},
+			{XOR, [2]int{5, 7}, 8},
+			{XOR, [2]int{1, 3}, 9},
+			{OR, [2]int{9, 6}, 10},
+			{OR, [2]int{0, 3}, 11},
+			{AND, [2]int{11, 2}, 12},
+			{XOR, [2]int{10, 12}, 13},
+			{INV, [2]int{9, 0}, 14},
+			{XOR, [2]int{14, 0}, 15},
+			{AND, [2]int{4, 9}, 16},
+			{OR, [2]int{16, 7}, 17},
+			{XOR, [2]int{15, 17}, 18},
+			{XOR, [2]int{2, 16}, 19},
+			{OR, [2]int{18, 3}, 20},
+			{XOR, [2]int{19, 20}, 21},
+		},
+		Outputs: [4]int{21, 18, 13, 8},
+	},
+}
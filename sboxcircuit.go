@@ -0,0 +1,516 @@
+package serpent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// SBoxCircuit is a bitsliced implementation of a 4-bit S-Box as XOR, AND,
+// OR and INV gates. Wires 0 to 3 hold input bits 0 to 3 and gate i writes
+// wire 4+i; Outputs lists the wires of output bits 0 to 3.
+type SBoxCircuit struct {
+	Gates   []Gate
+	Outputs [4]int
+}
+
+// SBoxCircuitObjective selects what a SBoxCircuitSearch minimizes first.
+type SBoxCircuitObjective int
+
+const (
+	// FewestGates minimizes the number of gates, then the depth.
+	FewestGates SBoxCircuitObjective = iota
+	// LowestDepth minimizes the depth, then the number of gates.
+	LowestDepth
+)
+
+// SBoxCircuitSearch configures a heuristic search for small S-Box
+// circuits.
+//
+// Each attempt starts from the input bits and repeatedly adds the
+// cheapest gates, found by exhaustive search up to three gates deep, that
+// complete one of the missing output bits, falling back to the algebraic
+// normal form when none does. Ties are broken at random so that attempts
+// differ, and the best circuit over all attempts is kept.
+type SBoxCircuitSearch struct {
+	SBox      SBox
+	Objective SBoxCircuitObjective
+	// Attempts is the number of randomized attempts; zero means 200.
+	Attempts int
+	// Seed seeds the random choices, making the search repeatable.
+	Seed int64
+}
+
+// Method Best runs the search and returns the best circuit found, checked
+// against the S-Box.
+func (s SBoxCircuitSearch) Best() (*SBoxCircuit, error) {
+	if len(s.SBox) != 16 || !s.SBox.isPermutation() {
+		return nil, errors.New("serpent: S-Box is not a 4-bit permutation")
+	}
+	attempts := s.Attempts
+	if attempts == 0 {
+		attempts = 200
+	}
+	r := rand.New(rand.NewSource(s.Seed))
+	reach := &reachable{}
+	for i := range reach.cost {
+		reach.cost[i] = unreached
+	}
+	var best *SBoxCircuit
+	for a := 0; a < attempts; a++ {
+		c := newCircuitAttempt(s.SBox, r, reach).run()
+		if best == nil || s.better(c, best) {
+			best = c
+		}
+	}
+	if err := best.Verify(s.SBox); err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+func (s SBoxCircuitSearch) better(c, than *SBoxCircuit) bool {
+	g, d := len(c.Gates), c.Depth()
+	tg, td := len(than.Gates), than.Depth()
+	if s.Objective == LowestDepth {
+		return d < td || d == td && g < tg
+	}
+	return g < tg || g == tg && d < td
+}
+
+// The truth tables of the input bits: bit x of inputTables[j] is bit j of
+// x.
+var inputTables = [4]uint16{0xaaaa, 0xcccc, 0xf0f0, 0xff00}
+
+// Method tables evaluates the circuit on all 16 inputs at once and returns
+// the truth table of every wire.
+func (c *SBoxCircuit) tables() []uint16 {
+	t := append([]uint16(nil), inputTables[:]...)
+	for _, g := range c.Gates {
+		t = append(t, gateTable(g.Op, t[g.In[0]], t[g.In[1]]))
+	}
+	return t
+}
+
+func gateTable(op GateOp, a, b uint16) uint16 {
+	switch op {
+	case XOR:
+		return a ^ b
+	case AND:
+		return a & b
+	case OR:
+		return a | b
+	case INV:
+		return ^a
+	}
+	return a
+}
+
+// Function WordSBoxCircuits returns copies of the circuits the word core
+// uses for the S-Boxes of SBoxDecimalTable and for their inverses.
+func WordSBoxCircuits() (circuits, inverses [8]*SBoxCircuit) {
+	for i := range circuits {
+		circuits[i] = wordSBoxCircuits[i].copy()
+		inverses[i] = wordSBoxCircuitsInverse[i].copy()
+	}
+	return
+}
+
+// Method copy returns a copy of 'c' that shares no memory with it.
+func (c *SBoxCircuit) copy() *SBoxCircuit {
+	return &SBoxCircuit{Gates: append([]Gate(nil), c.Gates...),
+		Outputs: c.Outputs}
+}
+
+// Method Verify proves the circuit equivalent to 'sbox' by evaluating it
+// on every input.
+func (c *SBoxCircuit) Verify(sbox SBox) error {
+	t := c.tables()
+	for x := 0; x < 16; x++ {
+		for l := 0; l < 4; l++ {
+			if int(t[c.Outputs[l]]>>uint(x))&1 != (sbox[x]>>uint(l))&1 {
+				return fmt.Errorf("serpent: circuit output bit %d "+
+					"differs from the S-Box for input %d", l, x)
+			}
+		}
+	}
+	return nil
+}
+
+// Method Eval applies the circuit to the 4-bit input 'x'.
+func (c *SBoxCircuit) Eval(x int) (y int) {
+	t := c.tables()
+	for l := 0; l < 4; l++ {
+		y |= int(t[c.Outputs[l]]>>uint(x)) & 1 << uint(l)
+	}
+	return
+}
+
+// Method Depth returns the largest number of gates on a path from an
+// input to an output.
+func (c *SBoxCircuit) Depth() (depth int) {
+	d := make([]int, 4+len(c.Gates))
+	for i, g := range c.Gates {
+		d[4+i] = d[g.In[0]] + 1
+		if g.Op != INV && d[g.In[1]]+1 > d[4+i] {
+			d[4+i] = d[g.In[1]] + 1
+		}
+	}
+	for _, w := range c.Outputs {
+		if d[w] > depth {
+			depth = d[w]
+		}
+	}
+	return
+}
+
+// Method Count returns the number of gates of operation 'op'.
+func (c *SBoxCircuit) Count(op GateOp) (n int) {
+	for _, g := range c.Gates {
+		if g.Op == op {
+			n++
+		}
+	}
+	return
+}
+
+// Method WriteGo writes the circuit as a Go function 'name' transforming
+// 32 nibbles in bitslice form, bit j of x[0] to x[3] being input bits 0 to
+// 3 of nibble j.
+func (c *SBoxCircuit) WriteGo(w io.Writer, name string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "func %s(x *[4]uint32) {\n", name)
+	fmt.Fprintf(bw, "\tw0, w1, w2, w3 := x[0], x[1], x[2], x[3]\n")
+	for i, g := range c.Gates {
+		var expr string
+		switch g.Op {
+		case XOR:
+			expr = fmt.Sprintf("w%d ^ w%d", g.In[0], g.In[1])
+		case AND:
+			expr = fmt.Sprintf("w%d & w%d", g.In[0], g.In[1])
+		case OR:
+			expr = fmt.Sprintf("w%d | w%d", g.In[0], g.In[1])
+		case INV:
+			expr = fmt.Sprintf("^w%d", g.In[0])
+		default:
+			expr = fmt.Sprintf("w%d", g.In[0])
+		}
+		fmt.Fprintf(bw, "\tw%d := %s\n", 4+i, expr)
+	}
+	fmt.Fprintf(bw, "\tx[0], x[1], x[2], x[3] = w%d, w%d, w%d, w%d\n}\n",
+		c.Outputs[0], c.Outputs[1], c.Outputs[2], c.Outputs[3])
+	return bw.Flush()
+}
+
+// A circuitAttempt builds one circuit, keeping the truth table of every
+// wire.
+type circuitAttempt struct {
+	r       *rand.Rand
+	c       *SBoxCircuit
+	tables  []uint16
+	wire    map[uint16]int
+	targets [4]uint16
+	done    [4]bool
+	reach   *reachable
+}
+
+// reachable records the functions at most two gates away from the wires
+// of an attempt, with a way to compute each.
+type reachable struct {
+	cost [1 << 16]uint8
+	op   [1 << 16]GateOp
+	a, b [1 << 16]uint16
+	// list holds the functions reached, cheapest first.
+	list []uint16
+}
+
+const unreached = 0xff
+
+// A plan computes a target by the gate op applied to the functions a and
+// b, themselves reachable, or directly when op is EQW.
+type plan struct {
+	target int
+	op     GateOp
+	a, b   uint16
+}
+
+func newCircuitAttempt(sbox SBox, r *rand.Rand, reach *reachable) *circuitAttempt {
+	at := &circuitAttempt{r: r, c: &SBoxCircuit{}, wire: map[uint16]int{},
+		reach: reach}
+	for j, t := range inputTables {
+		at.tables = append(at.tables, t)
+		at.wire[t] = j
+	}
+	for x := 0; x < 16; x++ {
+		for l := 0; l < 4; l++ {
+			if (sbox[x]>>uint(l))&1 == 1 {
+				at.targets[l] |= 1 << uint(x)
+			}
+		}
+	}
+	return at
+}
+
+func (at *circuitAttempt) run() *SBoxCircuit {
+	for {
+		remaining := at.collect()
+		if len(remaining) == 0 {
+			break
+		}
+		at.explore()
+		best, plans := maxPlanCost+1, []plan(nil)
+		for _, l := range remaining {
+			cost, options := at.plans(at.targets[l], best)
+			if cost < best {
+				best, plans = cost, nil
+			}
+			if cost == best {
+				for _, p := range options {
+					p.target = l
+					plans = append(plans, p)
+				}
+			}
+		}
+		if len(plans) == 0 {
+			l := remaining[at.r.Intn(len(remaining))]
+			at.fromANF(at.targets[l])
+			continue
+		}
+		p := plans[at.r.Intn(len(plans))]
+		if p.op == EQW {
+			at.build(p.a)
+		} else {
+			at.gate(p.op, at.build(p.a), at.build(p.b))
+		}
+	}
+	return prune(at.c)
+}
+
+// Method collect records the outputs already available and returns the
+// missing ones.
+func (at *circuitAttempt) collect() (remaining []int) {
+	for l, t := range at.targets {
+		if w, ok := at.wire[t]; ok {
+			at.c.Outputs[l] = w
+			at.done[l] = true
+		}
+		if !at.done[l] {
+			remaining = append(remaining, l)
+		}
+	}
+	return
+}
+
+// Method explore fills reach with the functions one and two gates away
+// from the wires, visiting them in a random order.
+func (at *circuitAttempt) explore() {
+	rc := at.reach
+	for _, f := range rc.list {
+		rc.cost[f] = unreached
+	}
+	rc.list = rc.list[:0]
+	for _, w := range at.r.Perm(len(at.tables)) {
+		rc.cost[at.tables[w]] = 0
+		rc.list = append(rc.list, at.tables[w])
+	}
+	try := func(op GateOp, a, b uint16, cost uint8) {
+		t := gateTable(op, a, b)
+		if rc.cost[t] != unreached {
+			return
+		}
+		rc.cost[t], rc.op[t], rc.a[t], rc.b[t] = cost, op, a, b
+		rc.list = append(rc.list, t)
+	}
+	for cost := uint8(1); cost <= 2; cost++ {
+		n := len(rc.list)
+		for i := 0; i < n; i++ {
+			a := rc.list[i]
+			if rc.cost[a] != cost-1 {
+				continue
+			}
+			try(INV, a, a, cost)
+			for j := 0; j < n; j++ {
+				b := rc.list[j]
+				if j == i || rc.cost[a]+rc.cost[b] != cost-1 {
+					continue
+				}
+				if j > i || rc.cost[b] != rc.cost[a] {
+					try(XOR, a, b, cost)
+					try(AND, a, b, cost)
+					try(OR, a, b, cost)
+				}
+			}
+		}
+	}
+}
+
+// The most gates a plan may cost before falling back to the algebraic
+// normal form.
+const maxPlanCost = 5
+
+// Method plans returns the cheapest plans for 't', under 'limit', and
+// their estimated cost, which does not account for gates shared between
+// the operands.
+func (at *circuitAttempt) plans(t uint16, limit int) (int, []plan) {
+	rc := at.reach
+	best := limit
+	var result []plan
+	offer := func(cost int, p plan) {
+		if cost < best {
+			best, result = cost, nil
+		}
+		if cost == best {
+			result = append(result, p)
+		}
+	}
+	if c := rc.cost[t]; c != unreached {
+		offer(int(c), plan{op: EQW, a: t})
+		return best, result
+	}
+	if c := rc.cost[^t]; c != unreached {
+		offer(int(c)+1, plan{op: INV, a: ^t, b: ^t})
+	}
+	var supersets, subsets []uint16
+	for _, x := range rc.list {
+		if y := t ^ x; rc.cost[y] != unreached && x < y {
+			offer(int(rc.cost[x])+int(rc.cost[y])+1, plan{op: XOR, a: x, b: y})
+		}
+		if x&t == t {
+			supersets = append(supersets, x)
+		}
+		if x|t == t {
+			subsets = append(subsets, x)
+		}
+	}
+	for _, pair := range []struct {
+		op   GateOp
+		list []uint16
+	}{{AND, supersets}, {OR, subsets}} {
+		// The list is ordered by cost, so later pairs only cost more.
+		for i, x := range pair.list {
+			if int(rc.cost[x])*2+1 > best {
+				break
+			}
+			for _, y := range pair.list[i+1:] {
+				cost := int(rc.cost[x]) + int(rc.cost[y]) + 1
+				if cost > best {
+					break
+				}
+				if gateTable(pair.op, x, y) == t {
+					offer(cost, plan{op: pair.op, a: x, b: y})
+				}
+			}
+		}
+	}
+	return best, result
+}
+
+// Method build adds the gates computing the reachable function 'f' and
+// returns its wire.
+func (at *circuitAttempt) build(f uint16) int {
+	if w, ok := at.wire[f]; ok {
+		return w
+	}
+	rc := at.reach
+	op, a, b := rc.op[f], rc.a[f], rc.b[f]
+	wa := at.build(a)
+	wb := wa
+	if op != INV {
+		wb = at.build(b)
+	}
+	return at.gate(op, wa, wb)
+}
+
+func (at *circuitAttempt) gate(op GateOp, a, b int) int {
+	t := gateTable(op, at.tables[a], at.tables[b])
+	if w, ok := at.wire[t]; ok {
+		return w
+	}
+	if op == INV {
+		b = 0
+	}
+	w := len(at.tables)
+	at.c.Gates = append(at.c.Gates, Gate{Op: op, In: [2]int{a, b}, Out: w})
+	at.tables = append(at.tables, t)
+	at.wire[t] = w
+	return w
+}
+
+// Method fromANF computes 't' as a sum of products of the input bits.
+func (at *circuitAttempt) fromANF(t uint16) {
+	var f ANF
+	for x := 0; x < 16; x++ {
+		f[x] = int(t>>uint(x)) & 1
+	}
+	for step := 1; step < 16; step <<= 1 {
+		for x := 0; x < 16; x++ {
+			if x&step != 0 {
+				f[x] ^= f[x^step]
+			}
+		}
+	}
+	sum := -1
+	for m := 1; m < 16; m++ {
+		if f[m] == 0 {
+			continue
+		}
+		product := -1
+		for j := 0; j < 4; j++ {
+			if m&(1<<uint(j)) == 0 {
+				continue
+			}
+			if product < 0 {
+				product = j
+			} else {
+				product = at.gate(AND, product, j)
+			}
+		}
+		if sum < 0 {
+			sum = product
+		} else {
+			sum = at.gate(XOR, sum, product)
+		}
+	}
+	if f[0] == 1 {
+		at.gate(INV, sum, 0)
+	}
+}
+
+// Function prune removes the gates that no output depends on and
+// renumbers the wires.
+func prune(c *SBoxCircuit) *SBoxCircuit {
+	live := make([]bool, 4+len(c.Gates))
+	for _, w := range c.Outputs {
+		live[w] = true
+	}
+	for i := len(c.Gates) - 1; i >= 0; i-- {
+		if live[4+i] {
+			live[c.Gates[i].In[0]] = true
+			if c.Gates[i].Op != INV {
+				live[c.Gates[i].In[1]] = true
+			}
+		}
+	}
+	renumber := []int{0, 1, 2, 3}
+	result := &SBoxCircuit{}
+	for i, g := range c.Gates {
+		if !live[4+i] {
+			renumber = append(renumber, -1)
+			continue
+		}
+		w := 4 + len(result.Gates)
+		renumber = append(renumber, w)
+		g.In[0] = renumber[g.In[0]]
+		if g.Op != INV {
+			g.In[1] = renumber[g.In[1]]
+		}
+		g.Out = w
+		result.Gates = append(result.Gates, g)
+	}
+	for l, w := range c.Outputs {
+		result.Outputs[l] = renumber[w]
+	}
+	return result
+}
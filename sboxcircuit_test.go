@@ -0,0 +1,98 @@
+package serpent
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// The gates and depth of the circuits found by TestSBoxCircuitSearch for
+// S0, its inverse, S1 and so on, with each objective.
+var sboxCircuitSizes = [2][16][2]int{
+	{{24, 10}, {22, 9}, {24, 8}, {17, 12}, {16, 9}, {17, 9}, {20, 12},
+		{17, 7}, {18, 8}, {20, 11}, {21, 13}, {19, 8}, {25, 10}, {25, 7},
+		{21, 13}, {19, 6}},
+	{{24, 10}, {23, 8}, {25, 7}, {26, 7}, {17, 6}, {23, 7}, {22, 8},
+		{17, 7}, {19, 6}, {21, 9}, {27, 11}, {22, 7}, {25, 10}, {25, 7},
+		{23, 10}, {19, 6}},
+}
+
+// Function TestSBoxCircuitSearch checks that short searches find circuits
+// for every S-Box and its inverse, for both objectives, of the sizes they
+// found so far.
+func TestSBoxCircuitSearch(t *testing.T) {
+	for o, objective := range []SBoxCircuitObjective{FewestGates,
+		LowestDepth} {
+		for i, sbox := range SBoxDecimalTable {
+			for k, s := range []SBox{sbox, sbox.Inverse()} {
+				c, err := SBoxCircuitSearch{SBox: s, Objective: objective,
+					Attempts: 10}.Best()
+				if err != nil {
+					t.Fatalf("S%d: search failed: %v\n", i, err)
+				}
+				for x := 0; x < 16; x++ {
+					if c.Eval(x) != s[x] {
+						t.Errorf("S%d: circuit gives %d for %d\n", i,
+							c.Eval(x), x)
+					}
+				}
+				want := sboxCircuitSizes[o][2*i+k]
+				if got := [2]int{len(c.Gates), c.Depth()}; got != want {
+					t.Errorf("S%d, inverse %v, objective %d: %d gates "+
+						"of depth %d, want %d of depth %d\n", i, k == 1,
+						o, got[0], got[1], want[0], want[1])
+				}
+			}
+		}
+	}
+}
+
+// Function TestSBoxCircuitVerify checks that Verify rejects a circuit
+// that differs from the S-Box.
+func TestSBoxCircuitVerify(t *testing.T) {
+	c, _ := SBoxCircuitSearch{SBox: SBoxDecimalTable[3], Attempts: 1}.Best()
+	if err := c.Verify(SBoxDecimalTable[4]); err == nil {
+		t.Errorf("circuit for S3 verified as S4\n")
+	}
+	c.Outputs[0], c.Outputs[1] = c.Outputs[1], c.Outputs[0]
+	if err := c.Verify(SBoxDecimalTable[3]); err == nil {
+		t.Errorf("circuit with swapped outputs verified\n")
+	}
+
+	var buf bytes.Buffer
+	c.WriteGo(&buf, "f")
+	if !strings.HasPrefix(buf.String(), "func f(x *[4]uint32) {") {
+		t.Errorf("unexpected Go output\n%s", buf.String())
+	}
+}
+
+// Function TestWordSBoxes proves the generated S-Box functions equal to
+// the tables by applying them to all 16 inputs at once, input x in bit x
+// of the words.
+func TestWordSBoxes(t *testing.T) {
+	for i, sbox := range SBoxDecimalTable {
+		for k, s := range []SBox{sbox, sbox.Inverse()} {
+			var x [4]uint32
+			for in := uint(0); in < 16; in++ {
+				for l := uint(0); l < 4; l++ {
+					x[l] |= uint32(in>>l&1) << in
+				}
+			}
+			if k == 0 {
+				wordSBoxes[i](&x)
+			} else {
+				wordSBoxesInverse[i](&x)
+			}
+			for in := uint(0); in < 16; in++ {
+				out := 0
+				for l := uint(0); l < 4; l++ {
+					out |= int(x[l]>>in&1) << l
+				}
+				if out != s[in] {
+					t.Errorf("S%d (inverse %v): %d gives %d, want %d\n",
+						i, k == 1, in, out, s[in])
+				}
+			}
+		}
+	}
+}
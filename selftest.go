@@ -0,0 +1,346 @@
+package serpent
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// The self-test checks the tables of the package and every registered
+// implementation before use. Once a self-test has failed the constructors
+// of this package refuse service: New, NewWithSubkeys, NewWithSubkeysHat,
+// NewCipher, NewCipherWithParams, NewCipherWithSubkeys and the
+// constructors built on them return the SelfTestError. The package level
+// Bitstring functions such as Encrypt, which cannot return an error, are
+// not guarded. An implementation added by Register that fails is only
+// refused by name.
+
+// A SelfTestFailure is one failed check of SelfTest.
+type SelfTestFailure struct {
+	// Check is the kind of check: "S-Box", "permutation", "linear
+	// transformation" or "known answer".
+	Check string
+	// Name identifies what was checked, such as "S3" or "word encrypt 1".
+	Name   string
+	Detail string
+}
+
+func (f SelfTestFailure) String() string {
+	return f.Check + " " + f.Name + ": " + f.Detail
+}
+
+// SelfTestError is returned by SelfTest, and by the constructors after it,
+// when a check fails.
+type SelfTestError struct {
+	Failures []SelfTestFailure
+}
+
+func (e *SelfTestError) Error() string {
+	s := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		s[i] = f.String()
+	}
+	return "serpent: self-test failed: " + strings.Join(s, "; ")
+}
+
+// Known answers in the byte order of the byte API, see blockTests.
+var selfTestVectors = []struct {
+	key, plain, cipher string
+}{
+	{
+		"80000000000000000000000000000000",
+		"00000000000000000000000000000000",
+		"264e5481eff42a4606abda06c0bfda3d",
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f" +
+			"101112131415161718191a1b1c1d1e1f",
+		"00112233445566778899aabbccddeeff",
+		"2868b7a2d28ecd5e4fdefac3c4330074",
+	},
+}
+
+const (
+	selfTestPending = iota
+	selfTestRunning
+	selfTestPassed
+	selfTestFailed
+)
+
+var (
+	selfTestMu    sync.Mutex
+	selfTestState int
+	selfTestErr   error
+	selfTestAuto  bool
+	// selfTestImplErrs holds the failures of implementations added by
+	// Register, by name.
+	selfTestImplErrs = map[string]error{}
+	// selfTestDone is signalled when a self-test leaves selfTestRunning.
+	selfTestDone = sync.NewCond(&selfTestMu)
+)
+
+// Function SelfTestOnFirstUse makes the first constructor call run
+// SelfTest when 'on' is set and no self-test has run yet. Constructor
+// calls made while a self-test runs wait for its result.
+func SelfTestOnFirstUse(on bool) {
+	selfTestMu.Lock()
+	defer selfTestMu.Unlock()
+	selfTestAuto = on
+}
+
+// Function SelfTest checks that the S-Box tables are permutations and
+// agree with their inverses and with the word S-Boxes, that IPTable and
+// FPTable and that LTTable and LTTableInverse are the inverse of each
+// other, and runs the known answers through every registered
+// implementation in both directions. It returns a *SelfTestError listing
+// every failure. A failure of the tables or of a built-in implementation
+// is final: the constructors refuse service from then on, so later
+// self-tests fail as well. A check that panics counts as a failure.
+//
+// The built-in implementations are checked without the self-test guard
+// while the constructors of this package wait. Implementations added by
+// Register are checked through their own constructors once the built-in
+// ones have passed, as those constructors are usually guarded. Their
+// failures are reported against that implementation only:
+// LookupImplementation and NewImplementationCipher refuse it, while the
+// other constructors keep working.
+func SelfTest() error {
+	selfTestMu.Lock()
+	if selfTestState != selfTestFailed {
+		selfTestState = selfTestRunning
+	}
+	selfTestMu.Unlock()
+	return runSelfTest()
+}
+
+// Function runSelfTest runs the checks of SelfTest, the state being set
+// to selfTestRunning or selfTestFailed by the caller. A check that panics
+// fails the self-test instead of leaving the state running.
+func runSelfTest() (err error) {
+	var failures []SelfTestFailure
+	var fail selfTestFail = func(check, name, format string,
+		args ...interface{}) {
+		failures = append(failures, SelfTestFailure{check, name,
+			fmt.Sprintf(format, args...)})
+	}
+	finished := false
+	defer func() {
+		if !finished {
+			fail("panic", "self-test", "%v", recover())
+			err = finishSelfTest(failures)
+		}
+	}()
+	selfTestSBoxes(fail)
+	selfTestTables(fail)
+	var registered []Implementation
+	for _, impl := range Implementations() {
+		if newc, ok := uncheckedImplementations[impl.Name]; ok {
+			selfTestKnownAnswers(fail, impl.Name, newc)
+		} else {
+			registered = append(registered, impl)
+		}
+	}
+	finished = true
+	if err := finishSelfTest(failures); err != nil {
+		return err
+	}
+	for _, impl := range registered {
+		failures = append(failures, selfTestRegistered(impl)...)
+	}
+	if len(failures) > 0 {
+		return &SelfTestError{Failures: failures}
+	}
+	return nil
+}
+
+// Function selfTestRegistered runs the known answers through an
+// implementation added by Register and returns its failures. A failure,
+// or a panic, is recorded against that implementation only, so that
+// LookupImplementation refuses it while the constructors of this package
+// keep working.
+func selfTestRegistered(impl Implementation) (failures []SelfTestFailure) {
+	var fail selfTestFail = func(check, name, format string,
+		args ...interface{}) {
+		failures = append(failures, SelfTestFailure{check, name,
+			fmt.Sprintf(format, args...)})
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fail("panic", impl.Name, "%v", r)
+		}
+		if len(failures) > 0 {
+			selfTestMu.Lock()
+			defer selfTestMu.Unlock()
+			selfTestImplErrs[impl.Name] = &SelfTestError{
+				Failures: failures}
+		}
+	}()
+	selfTestKnownAnswers(fail, impl.Name, impl.New)
+	return failures
+}
+
+// Function implementationSelfTest returns the error of a failed self-test
+// of the implementation called 'name'.
+func implementationSelfTest(name string) error {
+	selfTestMu.Lock()
+	defer selfTestMu.Unlock()
+	if err, ok := selfTestImplErrs[name]; ok {
+		return err
+	}
+	return nil
+}
+
+// Function finishSelfTest records the outcome of 'failures', wakes the
+// callers waiting for it and returns the error of a failed self-test.
+func finishSelfTest(failures []SelfTestFailure) error {
+	selfTestMu.Lock()
+	defer selfTestMu.Unlock()
+	defer selfTestDone.Broadcast()
+	if len(failures) > 0 {
+		err := &SelfTestError{Failures: failures}
+		if selfTestState != selfTestFailed {
+			selfTestState, selfTestErr = selfTestFailed, err
+		}
+		return err
+	}
+	if selfTestState == selfTestFailed {
+		return selfTestErr
+	}
+	selfTestState = selfTestPassed
+	return nil
+}
+
+// Function checkSelfTest returns the error of a failed self-test, running
+// the self-test first if SelfTestOnFirstUse asks for it and waiting for a
+// self-test that is running.
+func checkSelfTest() error {
+	selfTestMu.Lock()
+	defer selfTestMu.Unlock()
+	if selfTestAuto && selfTestState == selfTestPending {
+		selfTestState = selfTestRunning
+		selfTestMu.Unlock()
+		runSelfTest()
+		selfTestMu.Lock()
+	}
+	for selfTestState == selfTestRunning {
+		selfTestDone.Wait()
+	}
+	if selfTestState == selfTestFailed {
+		return selfTestErr
+	}
+	return nil
+}
+
+// A selfTestFail records a failure.
+type selfTestFail func(check, name, format string, args ...interface{})
+
+// Function selfTestSBoxes checks the S-Box tables.
+func selfTestSBoxes(fail selfTestFail) {
+	if len(SBoxDecimalTable) != 8 {
+		fail("S-Box", "SBoxDecimalTable", "%d S-Boxes, want 8",
+			len(SBoxDecimalTable))
+		return
+	}
+	var bs Bitstring
+	for i, sbox := range SBoxDecimalTable {
+		name := fmt.Sprintf("S%d", i)
+		if !sbox.isPermutation() {
+			fail("S-Box", name, "not a permutation of 0..15")
+			continue
+		}
+		inverse := sbox.Inverse()
+		var x [4]uint32
+		for j := uint(0); j < 32; j++ {
+			for l := uint(0); l < 4; l++ {
+				x[l] |= uint32(j%16>>l&1) << j
+			}
+		}
+		y, z := x, x
+		wordSBoxes[i](&y)
+		applyWords(sbox, &z)
+		if y != z {
+			fail("S-Box", name, "the word S-Box differs from the table")
+		}
+		wordSBoxesInverse[i](&y)
+		if y != x {
+			fail("S-Box", name, "the inverse word S-Box does not invert")
+		}
+		for in, out := range sbox {
+			if inverse[out] != in {
+				fail("S-Box", name, "Inverse does not invert")
+				break
+			}
+			b, c := bs.FromInt(in, 4), bs.FromInt(out, 4)
+			if SBoxBitstring[i][b] != c || SBoxBitstringInverse[i][c] != b {
+				fail("S-Box", name, "SBoxBitstring differs from the "+
+					"table at %d", in)
+				break
+			}
+		}
+	}
+}
+
+// Function selfTestTables checks the permutation and linear
+// transformation tables.
+func selfTestTables(fail selfTestFail) {
+	valid := true
+	for _, t := range []struct {
+		name  string
+		table []int
+	}{{"IPTable", IPTable}, {"FPTable", FPTable}} {
+		if len(t.table) != 128 {
+			fail("permutation", t.name, "%d entries, want 128",
+				len(t.table))
+			valid = false
+		} else if err := Permutation(t.table).Validate(); err != nil {
+			fail("permutation", t.name, "%v", err)
+			valid = false
+		}
+	}
+	if valid && !Permutation(IPTable).Compose(FPTable).IsIdentity() {
+		fail("permutation", "IPTable", "FPTable is not its inverse")
+	}
+	for _, t := range []struct {
+		name  string
+		table []Ttable
+	}{{"LTTable", LTTable}, {"LTTableInverse", LTTableInverse}} {
+		if err := checkTtable(t.name, t.table); err != nil {
+			fail("linear transformation", t.name, "%v", err)
+			return
+		}
+	}
+	if !ttablesInverse(LTTable, LTTableInverse) {
+		fail("linear transformation", "LTTable",
+			"LTTableInverse is not its inverse")
+	}
+}
+
+// Function selfTestKnownAnswers runs the known answers through the
+// implementation called 'name', built by 'newc'.
+func selfTestKnownAnswers(fail selfTestFail, name string,
+	newc func(Params, []byte) (cipher.Block, error)) {
+	for i, v := range selfTestVectors {
+		key, _ := hex.DecodeString(v.key)
+		plain, _ := hex.DecodeString(v.plain)
+		want, _ := hex.DecodeString(v.cipher)
+		b, err := newc(Serpent1, key)
+		if err != nil {
+			fail("known answer", name, "%v", err)
+			return
+		}
+		got := make([]byte, BlockSize)
+		b.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			fail("known answer", fmt.Sprintf("%s encrypt %d", name, i),
+				"got %x, want %x", got, want)
+		}
+		b.Decrypt(got, want)
+		if !bytes.Equal(got, plain) {
+			fail("known answer", fmt.Sprintf("%s decrypt %d", name, i),
+				"got %x, want %x", got, plain)
+		}
+	}
+}
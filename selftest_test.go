@@ -0,0 +1,190 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Function resetSelfTest forgets the result of any self-test.
+func resetSelfTest() {
+	selfTestMu.Lock()
+	defer selfTestMu.Unlock()
+	selfTestState, selfTestErr, selfTestAuto = selfTestPending, nil, false
+	selfTestImplErrs = map[string]error{}
+}
+
+// Function TestSelfTest checks that the self-test passes and that a first
+// use check runs it.
+func TestSelfTest(t *testing.T) {
+	defer resetSelfTest()
+	if err := SelfTest(); err != nil {
+		t.Fatalf("SelfTest failed: %v", err)
+	}
+	resetSelfTest()
+	SelfTestOnFirstUse(true)
+	if _, err := NewCipher(make([]byte, 16)); err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	if selfTestState != selfTestPassed {
+		t.Errorf("the first use did not run the self-test\n")
+	}
+}
+
+// Function TestSelfTestFailure breaks an S-Box and the final permutation
+// and checks the failures reported and the refusal of service.
+func TestSelfTestFailure(t *testing.T) {
+	defer resetSelfTest()
+	sbox := SBoxDecimalTable[2][0]
+	SBoxDecimalTable[2][0] = SBoxDecimalTable[2][1]
+	FPTable[5], FPTable[6] = FPTable[6], FPTable[5]
+	err := SelfTest()
+	SBoxDecimalTable[2][0] = sbox
+	FPTable[5], FPTable[6] = FPTable[6], FPTable[5]
+
+	e, ok := err.(*SelfTestError)
+	if !ok {
+		t.Fatalf("SelfTest gives %v", err)
+	}
+	// Serpent1 shares the broken S-Box, so the known answers fail too.
+	want := []SelfTestFailure{
+		{"S-Box", "S2", "not a permutation of 0..15"},
+		{"permutation", "IPTable", "FPTable is not its inverse"},
+	}
+	if len(e.Failures) <= len(want) {
+		t.Fatalf("SelfTest gives %v", err)
+	}
+	for i, f := range e.Failures {
+		if i < len(want) && f != want[i] ||
+			i >= len(want) && f.Check != "known answer" {
+			t.Errorf("unexpected failure %d: %v\n", i, f)
+		}
+	}
+
+	// The failure is final.
+	if SelfTest() == nil {
+		t.Errorf("SelfTest passes after a failure\n")
+	}
+	if _, err := NewCipher(make([]byte, 16)); err != error(e) {
+		t.Errorf("NewCipher gives %v after a failure\n", err)
+	}
+	if _, err := New(Serpent1, bs); err != error(e) {
+		t.Errorf("New gives %v after a failure\n", err)
+	}
+}
+
+// Function TestSelfTestConcurrentUse checks that a constructor call made
+// while a self-test runs waits for its result.
+func TestSelfTestConcurrentUse(t *testing.T) {
+	defer resetSelfTest()
+	selfTestMu.Lock()
+	selfTestState = selfTestRunning
+	selfTestMu.Unlock()
+
+	errs := make(chan error)
+	go func() {
+		_, err := NewCipher(make([]byte, 16))
+		errs <- err
+	}()
+	select {
+	case err := <-errs:
+		t.Fatalf("NewCipher returned %v during the self-test", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	failure := SelfTestFailure{"S-Box", "S2", "broken"}
+	want := finishSelfTest([]SelfTestFailure{failure})
+	if err := <-errs; err != want {
+		t.Errorf("NewCipher gives %v after the self-test, want %v\n",
+			err, want)
+	}
+}
+
+// Function TestSelfTestPanic checks that a built-in implementation
+// panicking during the self-test fails it instead of leaving later
+// constructor calls waiting.
+func TestSelfTestPanic(t *testing.T) {
+	defer resetSelfTest()
+	newc := uncheckedImplementations["word"]
+	uncheckedImplementations["word"] = func(Params, []byte) (cipher.Block,
+		error) {
+		panic("broken")
+	}
+	err := SelfTest()
+	uncheckedImplementations["word"] = newc
+
+	want := []SelfTestFailure{{"panic", "self-test", "broken"}}
+	e, ok := err.(*SelfTestError)
+	if !ok || len(e.Failures) != 1 || e.Failures[0] != want[0] {
+		t.Fatalf("SelfTest gives %v", err)
+	}
+	errs := make(chan error)
+	go func() {
+		_, err := NewCipher(make([]byte, 16))
+		errs <- err
+	}()
+	select {
+	case err := <-errs:
+		if err != error(e) {
+			t.Errorf("NewCipher gives %v after a panic\n", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("NewCipher blocks after a panic")
+	}
+}
+
+// Function TestSelfTestRegistered checks that a failing implementation
+// added by Register is refused by name while the other constructors keep
+// working.
+func TestSelfTestRegistered(t *testing.T) {
+	defer resetSelfTest()
+	broken := func(p Params, key []byte) (cipher.Block, error) {
+		// The key of every known answer differs from its complement.
+		complement := make([]byte, len(key))
+		for i := range key {
+			complement[i] = ^key[i]
+		}
+		return NewCipherWithParams(p, complement)
+	}
+	panicking := func(Params, []byte) (cipher.Block, error) {
+		panic("broken")
+	}
+	for _, impl := range []Implementation{
+		{Name: "broken", New: broken},
+		{Name: "panicking", New: panicking},
+	} {
+		if err := Register(impl); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+	}
+	defer func() {
+		implementationsMu.Lock()
+		defer implementationsMu.Unlock()
+		delete(implementations, "broken")
+		delete(implementations, "panicking")
+	}()
+
+	err := SelfTest()
+	e, ok := err.(*SelfTestError)
+	if !ok || len(e.Failures) == 0 {
+		t.Fatalf("SelfTest gives %v", err)
+	}
+	for i, f := range e.Failures {
+		if !strings.HasPrefix(f.Name, "broken") && f.Name != "panicking" {
+			t.Errorf("unexpected failure %d: %v\n", i, f)
+		}
+	}
+	for _, name := range []string{"broken", "panicking"} {
+		if _, err := NewImplementationCipher(name, Serpent1,
+			make([]byte, 16)); err == nil {
+			t.Errorf("%s accepted after a failure\n", name)
+		}
+	}
+	if _, err := NewCipher(make([]byte, 16)); err != nil {
+		t.Errorf("NewCipher gives %v after a failing registration\n", err)
+	}
+	if _, err := NewImplementationCipher("bitstring", Serpent1,
+		make([]byte, 16)); err != nil {
+		t.Errorf("bitstring gives %v after a failing registration\n", err)
+	}
+}
@@ -11,6 +11,7 @@ package serpent
 
 import (
 	"fmt"
+	"strings"
 )
 
 type SBox []int
@@ -49,22 +50,8 @@ var SBoxBitstringInverse []map[Bitstring]Bitstring
 
 // Initialise variables when this package is imported.
 func init() {
-	var bs Bitstring
-	for _, sbox := range SBoxDecimalTable {
-		var dict map[Bitstring]Bitstring = make(
-			map[Bitstring]Bitstring, len(sbox))
-		var inverseDict map[Bitstring]Bitstring = make(
-			map[Bitstring]Bitstring, len(sbox))
-
-		for boxindex, box := range sbox {
-			index := bs.FromInt(boxindex, 4)
-			value := bs.FromInt(box, 4)
-			dict[index] = value
-			inverseDict[value] = index
-		}
-		SBoxBitstring = append(SBoxBitstring, dict)
-		SBoxBitstringInverse = append(SBoxBitstringInverse, inverseDict)
-	}
+	SBoxBitstring = serpent1.sbox
+	SBoxBitstringInverse = serpent1.sboxInverse
 }
 
 // Methods for Bitslice
@@ -124,67 +111,91 @@ func (s Bitstring) ByteSlice() (result []byte) {
 }
 
 // ToHex returns a 1-char hexstring of a 4 char bitstring
-func (s Bitstring) ToHex() (h Hexstring) {
-	if len(s) > 4 {
-		fmt.Printf("Bitstring is more than 4 chars, " +
-			"cannot be converted to hex char\n")
+//
+// Deprecated: ToHex returns an empty Hexstring for anything but 4 binary
+// digits. Use ParseBitstring to check input and ToHexstring to convert it.
+func (s Bitstring) ToHex() Hexstring {
+	h, err := hexDigit(s)
+	if err != nil {
+		fmt.Printf("%v, cannot be converted to hex char\n", err)
+	}
+	return h
+}
+
+// FromHex returns a 4-char bitstring of a 1-char hexstring
+//
+// Deprecated: FromHex returns an empty Bitstring for anything but one hex
+// digit. Use ParseHexstring to check input and ToBitstring to convert it.
+func (s Bitstring) FromHex(h Hexstring) Bitstring {
+	b, err := hexDigitBits(h)
+	if err != nil {
+		fmt.Printf("%v, cannot be converted to bitstring\n", err)
 	}
+	return b
+}
+
+// Function hexDigit returns the hex digit of the 4 binary digits 's', or
+// an error.
+func hexDigit(s Bitstring) (Hexstring, error) {
 	var bin2hex = map[Bitstring]Hexstring{
 		"0000": "0", "1000": "1", "0100": "2", "1100": "3",
 		"0010": "4", "1010": "5", "0110": "6", "1110": "7",
 		"0001": "8", "1001": "9", "0101": "a", "1101": "b",
 		"0011": "c", "1011": "d", "0111": "e", "1111": "f",
 	}
-	return bin2hex[s]
+	h, ok := bin2hex[s]
+	if !ok {
+		return "", fmt.Errorf("serpent: bitstring %q is not 4 binary "+
+			"digits", string(s))
+	}
+	return h, nil
 }
 
-// FromHex returns a 4-char bitstring of a 1-char hexstring
-func (s Bitstring) FromHex(h Hexstring) Bitstring {
-	if len(h) > 1 {
-		fmt.Printf("Hex string is more than 1 char, " +
-			"cannot be converted to bitstring\n")
-	}
+// Function hexDigitBits returns the 4 binary digits of the hex digit 'h',
+// or an error.
+func hexDigitBits(h Hexstring) (Bitstring, error) {
 	var hex2bin = map[Hexstring]Bitstring{
 		"0": "0000", "1": "1000", "2": "0100", "3": "1100",
 		"4": "0010", "5": "1010", "6": "0110", "7": "1110",
 		"8": "0001", "9": "1001", "a": "0101", "b": "1101",
 		"c": "0011", "d": "1011", "e": "0111", "f": "1111",
 	}
-	return hex2bin[h]
+	b, ok := hex2bin[Hexstring(strings.ToLower(string(h)))]
+	if !ok {
+		return "", fmt.Errorf("serpent: hex string %q is not a hex digit",
+			string(h))
+	}
+	return b, nil
 }
 
 // ToHexstring returns the hexstring representation of the
-// bitstring
+// bitstring. It panics if 's' is not made of groups of 4 binary digits;
+// ParseBitstring checks input that may not be.
 func (s Bitstring) ToHexstring() (result Hexstring) {
-	ln := len(s)
-	var b Bitslice
-	var tb []byte = make([]byte, 4)
-
-	for i := 0; i < ln; i = i + 4 {
-		tb[0] = s[i]
-		tb[1] = s[i+1]
-		tb[2] = s[i+2]
-		tb[3] = s[i+3]
-		b = append(b, Bitstring(tb))
-	}
-	for _, nbs := range b {
-		result = nbs.ToHex() + result
+	if len(s)%4 != 0 {
+		panic(fmt.Sprintf("serpent: bitstring of %d bits cannot be "+
+			"converted to hex", len(s)))
+	}
+	for i := 0; i < len(s); i += 4 {
+		h, err := hexDigit(s[i : i+4])
+		if err != nil {
+			panic(err.Error())
+		}
+		result = h + result
 	}
 	return
 }
 
 // ToBistring returns the bitstring representation of the
-// hexstring
+// hexstring. It panics if 'h' holds anything but hex digits;
+// ParseHexstring checks input that may not.
 func (h Hexstring) ToBitstring() (result Bitstring) {
-	ln := len(h)
-	var rh []byte = make([]byte, ln)
-	var n int = 0
-	for i := ln - 1; i >= 0; i-- {
-		rh[i] = h[n]
-		n++
-	}
-	for j := 0; j < ln; j++ {
-		result = result + result.FromHex(Hexstring(rh[j]))
+	for j := len(h) - 1; j >= 0; j-- {
+		b, err := hexDigitBits(h[j : j+1])
+		if err != nil {
+			panic(err.Error())
+		}
+		result = result + b
 	}
 	return
 }
@@ -296,9 +307,7 @@ func (s Bitstring) ShiftRight(places int) Bitstring {
 	lw := len(wc)
 	var nc []byte = make([]byte, lw)
 	for i := 0; i < lw; i++ {
-		if i <= places {
-			nc[i] = wc[places+i]
-		} else if i+places < lw {
+		if i+places < lw {
 			nc[i] = wc[i+places]
 		} else {
 			nc[i] = '0'
@@ -336,35 +345,27 @@ func (s Bitstring) QuadJoin(bs Bitslice) Bitstring {
 // Function S applies S-Box number 'box' to 4-bit bitstring 'input'
 // and return a 4-bit bitstring.
 func S(box int, input Bitstring) Bitstring {
-	return SBoxBitstring[box%8][input]
+	return serpent1.s(box, input)
 }
 
 // Function SInverse applies S-Box number box in reverse to 4-bit bitstring
 // 'output' and return a 4-bit bitstring 'input' as the result
 func SInverse(box int, output Bitstring) Bitstring {
-	return SBoxBitstringInverse[box%8][output]
+	return serpent1.sInverse(box, output)
 }
 
 // Function SHat applies a parallel array of 32 copies of S-Box number 'box'
 // to the 128-bit bitstring 'input' and return a 128-bit bitstring as the
 // result
 func SHat(box int, input Bitstring) Bitstring {
-	var bs Bitstring
-	for i := 0; i < 32; i++ {
-		bs = bs + S(box, input[4*i:4*(i+1)])
-	}
-	return bs
+	return serpent1.sHat(box, input)
 }
 
 // Function SHatInverse applies in reverse, a parallel array of 32 copies of
 // S-Box number 'box' to the 128-bit bitstring 'output' and return a 128-bit
 // bitstring (the input) as the result
 func SHatInverse(box int, output Bitstring) Bitstring {
-	var bs Bitstring
-	for i := 0; i < 32; i++ {
-		bs = bs + SInverse(box, output[4*i:4*(i+1)])
-	}
-	return bs
+	return serpent1.sHatInverse(box, output)
 }
 
 // Function SBitslice takes 'words', a list of 4 32-bit bitstring, least
@@ -376,18 +377,7 @@ func SHatInverse(box int, output Bitstring) Bitstring {
 // put the 4 output bits in the corresponding positions in the output
 // words.
 func SBitslice(box int, words Bitslice) Bitslice {
-	result := make(Bitslice, 4)
-	for i := 0; i < 32; i++ {
-		var c0 Bitstring = Bitstring(int(words[0][i]))
-		var c1 Bitstring = Bitstring(int(words[1][i]))
-		var c2 Bitstring = Bitstring(int(words[2][i]))
-		var c3 Bitstring = Bitstring(int(words[3][i]))
-		quad := S(box, Bitstring(c0+c1+c2+c3))
-		for j := 0; j < 4; j++ {
-			result[j] = result[j] + Bitstring(int(quad[j]))
-		}
-	}
-	return result
+	return serpent1.sBitslice(box, words)
 }
 
 // Function SBitsliceInverse takes 'words', a list of 4 32-bit bitstring, least
@@ -399,18 +389,7 @@ func SBitslice(box int, words Bitslice) Bitslice {
 // in 'words' and put the 4 output bits in the corresponding positions in the
 // output words.
 func SBitsliceInverse(box int, words Bitslice) Bitslice {
-	result := make(Bitslice, 4)
-	for i := 0; i < 32; i++ {
-		var c0 Bitstring = Bitstring(int(words[0][i]))
-		var c1 Bitstring = Bitstring(int(words[1][i]))
-		var c2 Bitstring = Bitstring(int(words[2][i]))
-		var c3 Bitstring = Bitstring(int(words[3][i]))
-		quad := SInverse(box, Bitstring(c0+c1+c2+c3))
-		for j := 0; j < 4; j++ {
-			result[j] = result[j] + Bitstring(int(quad[j]))
-		}
-	}
-	return result
+	return serpent1.sBitsliceInverse(box, words)
 }
 
 // Function R applies round 'i' to the 128-bit Bitstring 'BHati', returning
@@ -418,20 +397,7 @@ func SBitsliceInverse(box int, words Bitslice) Bitslice {
 // appropriately numbered subkey(s) from the 'KHat' list of 33 128-bit
 // Bitstrings.
 func R(i int, BHati Bitstring, KHat Bitslice) Bitstring {
-	var xored Bitstring
-	var BHatiPlus1 Bitstring
-	xored = xored.Xor(Bitslice{BHati, KHat[i]})
-	SHati := SHat(i, xored)
-
-	if 0 <= i && i <= round-2 {
-		BHatiPlus1 = LT(SHati)
-	} else if i == round-1 {
-		BHatiPlus1 = BHatiPlus1.Xor(Bitslice{SHati, KHat[round]})
-	} else {
-		fmt.Printf("Round is out of range\n")
-	}
-
-	return BHatiPlus1
+	return serpent1.r(i, BHati, KHat)
 }
 
 // Function RInverse applies the round 'i' in reverse to the 128-bit Bitstring
@@ -439,22 +405,7 @@ func R(i int, BHati Bitstring, KHat Bitslice) Bitstring {
 // this using the appropriately numbered subkey(s) from the 'KHat' list of 33
 // 128-bit Bitstrings.
 func RInverse(i int, BHatiPlus1 Bitstring, KHat Bitslice) Bitstring {
-	var xored Bitstring
-	var BHati Bitstring
-	var SHati Bitstring
-
-	if 0 <= i && i <= round-2 {
-		SHati = LTInverse(BHatiPlus1)
-	} else if i == round-1 {
-		SHati = xored.Xor(Bitslice{BHatiPlus1, KHat[round]})
-	} else {
-		fmt.Printf("Round is out of range\n")
-	}
-
-	xored = SHatInverse(i, SHati)
-	BHati = xored.Xor(Bitslice{xored, KHat[i]})
-
-	return BHati
+	return serpent1.rInverse(i, BHatiPlus1, KHat)
 }
 
 // Function RBitslice applies round 'i' (Bitslice version) to the 128-bit
@@ -462,24 +413,7 @@ func RInverse(i int, BHatiPlus1 Bitstring, KHat Bitslice) Bitstring {
 // Use the appropriately numbered subkey(s) from the 'K' list of 33 128-bit
 // Bitstrings.
 func RBitslice(i int, Bi Bitstring, K Bitslice) Bitstring {
-	var xored Bitstring
-	var BiPlus1 Bitstring
-
-	// 1. Key mixing
-	xored = xored.Xor(Bitslice{Bi, K[i]})
-
-	// 2. S Boxes
-	Si := SBitslice(i, xored.QuadSplit())
-
-	// 3. Linear Transformation
-	if i == round-1 {
-		// In the last round, replaced by an additional key mixing
-		BiPlus1 = xored.Xor(Bitslice{xored.QuadJoin(Si), K[round]})
-	} else {
-		BiPlus1 = xored.QuadJoin(LTBitslice(Si))
-	}
-
-	return BiPlus1
+	return serpent1.rBitslice(i, Bi, K)
 }
 
 // Function RBitsliceInverse applies the inverse of round 'i' (bitslice
@@ -487,85 +421,13 @@ func RBitslice(i int, Bi Bitstring, K Bitslice) Bitstring {
 // Bitstring (conceptually B i). Use the appropriately numbered subkey(s) from
 // the 'K' list of 33 128-bit Bitstrings.
 func RBitsliceInverse(i int, BiPlus1 Bitstring, K Bitslice) Bitstring {
-	var xoredbitslice Bitslice
-	var Bi Bitstring
-	var SiTemp Bitstring
-	var Si Bitslice
-
-	// 3. Linear Transformation
-	if i == round-1 {
-		// In the last round, replaced by an additional key mixing
-		SiTemp = SiTemp.Xor(Bitslice{BiPlus1, K[round]})
-		Si = SiTemp.QuadSplit()
-	} else {
-		Si = LTBitsliceInverse(BiPlus1.QuadSplit())
-	}
-
-	// 2. S Boxes
-	xoredbitslice = SBitsliceInverse(i, Si)
-
-	// 1. Key mixing
-	Bi = Bi.Xor(Bitslice{Bi.QuadJoin(xoredbitslice), K[i]})
-
-	return Bi
+	return serpent1.rBitsliceInverse(i, BiPlus1, K)
 }
 
 // Function makeSubkeys takes the 256-bit Bitstring 'userkey' and returns two
 // lists (conceptually K and KHat) of 33 128-bit Bitstrings each.
 func makeSubkeys(userkey Bitstring) (Bitslice, Bitslice) {
-	// Convert the userkey to 8 32-bit words.
-	w := make(Bitmap, 132)
-	for i := -8; i < 0; i++ {
-		w[i] = userkey[(i+8)*32 : (i+9)*32]
-	}
-
-	// Expand the 8 words to a prekey w0 ... w131 with the affine
-	// recurrence.
-	var tempbs Bitstring
-	for i := 0; i < 132; i++ {
-		tempbsl := Bitslice{w[i-8], w[i-5], w[i-3], w[i-1],
-			tempbs.FromInt(phi, 32),
-			tempbs.FromInt(i, 32)}
-		tempbs = tempbs.Xor(tempbsl)
-		w[i] = tempbs.RotateLeft(11)
-	}
-
-	// The round keys are now calculated from the prekeys using the
-	// S-Boxes in bitslice mode. Each k[i] is a 32-bit Bitstring.
-	k := make(Bitslice, 132)
-	for i := 0; i < round+1; i++ {
-		whichS := (round + 3 - i) % round
-		k[0+4*i] = ""
-		k[1+4*i] = ""
-		k[2+4*i] = ""
-		k[3+4*i] = ""
-		var input Bitstring
-		for j := 0; j < 32; j++ {
-			input = Bitstring(w[0+4*i][j]) +
-				Bitstring(w[1+4*i][j]) +
-				Bitstring(w[2+4*i][j]) +
-				Bitstring(w[3+4*i][j])
-			output := S(whichS, input)
-			for l := 0; l < 4; l++ {
-				k[l+4*i] = k[l+4*i] + Bitstring(output[l])
-			}
-		}
-	}
-
-	// We then renumber the 32-bit values k_j as 128-bit subkeys K_i
-	K := Bitslice{}
-	for i := 0; i < 33; i++ {
-		K = append(K, k[4*i]+k[4*i+1]+k[4*i+2]+k[4*i+3])
-	}
-
-	// We now apply IP to the round key in order to place the key bits
-	// in the correct column.
-	KHat := Bitslice{}
-	for i := 0; i < 33; i++ {
-		KHat = append(KHat, IP(K[i]))
-	}
-
-	return K, KHat
+	return serpent1.makeSubkeys(userkey)
 }
 
 // Function makeLongkey takes a bitstring key 'k' and returns the long
@@ -595,13 +457,7 @@ func makeLongkey(k Bitstring) Bitstring {
 // cipher text Bitstring.
 func Encrypt(plainText Bitstring, userKey Bitstring) Bitstring {
 	_, KHat := makeSubkeys(userKey)
-	BHat := IP(plainText)
-	for i := 0; i < round; i++ {
-		BHat = R(i, BHat, KHat)
-	}
-	C := FP(BHat)
-
-	return C
+	return serpent1.encrypt(plainText, KHat)
 }
 
 // Function EncryptBitslice encrypts the 128-bit Bitstring 'plainText' with
@@ -609,12 +465,7 @@ func Encrypt(plainText Bitstring, userKey Bitstring) Bitstring {
 // 128-bit cipher text Bitstring.
 func EncryptBitslice(plainText Bitstring, userKey Bitstring) Bitstring {
 	K, _ := makeSubkeys(userKey)
-	B := plainText
-	for i := 0; i < round; i++ {
-		B = RBitslice(i, B, K)
-	}
-
-	return B
+	return serpent1.encryptBitslice(plainText, K)
 }
 
 // Function Decrypt uses the 256-bit Bitstring 'userKey' to decrypt the
@@ -622,13 +473,7 @@ func EncryptBitslice(plainText Bitstring, userKey Bitstring) Bitstring {
 // 128-bit Bitstring which is the plain text.
 func Decrypt(cipherText Bitstring, userKey Bitstring) Bitstring {
 	_, KHat := makeSubkeys(userKey)
-	BHat := FPInverse(cipherText)
-	for i := round - 1; i >= 0; i-- {
-		BHat = RInverse(i, BHat, KHat)
-	}
-	plainText := IPInverse(BHat)
-
-	return plainText
+	return serpent1.decrypt(cipherText, KHat)
 }
 
 // Function DecryptBitslice decrypts the 128-bit Bitstring 'cipherText' with
@@ -636,10 +481,5 @@ func Decrypt(cipherText Bitstring, userKey Bitstring) Bitstring {
 // 128-bit Bitstring which is the plain text.
 func DecryptBitslice(cipherText Bitstring, userKey Bitstring) Bitstring {
 	K, _ := makeSubkeys(userKey)
-	B := cipherText
-	for i := round - 1; i >= 0; i-- {
-		B = RBitsliceInverse(i, B, K)
-	}
-
-	return B
+	return serpent1.decryptBitslice(cipherText, K)
 }
@@ -79,14 +79,15 @@ func TestXor(t *testing.T) {
 	}
 }
 
-// Function TestXorNew checks xorNew returns the correct result
+// Function TestXorNew checks Uint128le.Xor returns the correct result
 func TestXorNew(t *testing.T) {
-	var bstarget Bitstring = "0000"
-	target := fromBitstring(bstarget)
-	bs1 := fromBitstring("0000")
-	bs2 := fromBitstring("0000")
-	bs1.binaryXorNEW(bs2)
-	if bs1 != target {
+	target := Uint128leFromUint64(0x0f)
+	bs1 := Uint128leFromUint64(0x05)
+	bs2 := Uint128leFromUint64(0x0a)
+	if bs1.Xor(bs2) != target {
+		t.Fail()
+	}
+	if XorUint128le(bs1, bs2, target) != (Uint128le{}) {
 		t.Fail()
 	}
 }
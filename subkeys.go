@@ -0,0 +1,103 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// Independent round keys replace the key schedule: the caller supplies the
+// subkeys directly, one 128-bit key per round up to the last plus one for
+// the final key mixing, 33 for Serpent-1. They may come in either of the
+// forms makeSubkeys returns, K for the bitslice algorithm or KHat = IP(K)
+// for the normal algorithm; the other form is derived from it.
+
+// Function NewWithSubkeys creates a Cipher for the parameter set 'p' that
+// uses the subkeys 'K', in bitslice format, instead of a key schedule.
+func NewWithSubkeys(p Params, K Bitslice) (*Cipher, error) {
+	v, err := checkSubkeys(p, K)
+	if err != nil {
+		return nil, err
+	}
+	return newBitstringCipher(v, K), nil
+}
+
+// Function NewWithSubkeysHat is NewWithSubkeys for subkeys 'KHat' in the
+// format of the normal algorithm.
+func NewWithSubkeysHat(p Params, KHat Bitslice) (*Cipher, error) {
+	v, err := checkSubkeys(p, KHat)
+	if err != nil {
+		return nil, err
+	}
+	K := make(Bitslice, len(KHat))
+	for i, k := range KHat {
+		K[i] = IPInverse(k)
+	}
+	return newBitstringCipher(v, K), nil
+}
+
+// Function checkSubkeys validates 'p' and checks that 'K' holds one
+// 128-bit subkey for each the parameter set requires.
+func checkSubkeys(p Params, K Bitslice) (*variant, error) {
+	if err := checkSelfTest(); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	v := newVariant(p)
+	if len(K) != v.subkeyCount() {
+		return nil, fmt.Errorf("serpent: %d subkeys, want %d", len(K),
+			v.subkeyCount())
+	}
+	for i, k := range K {
+		if len(k) != 128 {
+			return nil, fmt.Errorf("serpent: subkey %d has %d bits, "+
+				"want 128", i, len(k))
+		}
+		if err := checkBits(k); err != nil {
+			return nil, fmt.Errorf("serpent: subkey %d: %v", i, err)
+		}
+	}
+	return v, nil
+}
+
+// Method Subkeys returns copies of the subkeys of 'c' in both formats. It
+// is the one exception to Destroy: the copies are Bitstrings, which
+// cannot be wiped, so they stay in memory until the garbage collector
+// reclaims them.
+func (c *Cipher) Subkeys() (K, KHat Bitslice) {
+	c.keys.check()
+	K, KHat = make(Bitslice, len(c.keys.k)), make(Bitslice, len(c.keys.k))
+	for i := range K {
+		K[i] = wordsToBitstring(&c.keys.k[i])
+		KHat[i] = IP(K[i])
+	}
+	return K, KHat
+}
+
+// Function NewCipherWithSubkeys creates a cipher.Block for the parameter
+// set 'p' that uses the subkeys 'K', in bitslice format, instead of a key
+// schedule. MakeSubkeysUint128le returns the subkeys of a user key in this
+// form.
+func NewCipherWithSubkeys(p Params, K []Uint128le) (cipher.Block, error) {
+	if err := checkSelfTest(); err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	v := newVariant(p)
+	if len(K) != v.subkeyCount() {
+		return nil, fmt.Errorf("serpent: %d subkeys, want %d", len(K),
+			v.subkeyCount())
+	}
+	k := make([][4]uint32, len(K))
+	for i := range K {
+		k[i] = loadWords(K[i][:])
+	}
+	b, err := newBlock(v, k, false)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,109 @@
+package serpent
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// Function TestNewWithSubkeys checks that ciphers built from the subkeys of
+// a key schedule, in either format, agree with the cipher built from the
+// key, and that independent random subkeys give an invertible cipher.
+func TestNewWithSubkeys(t *testing.T) {
+	key := makeLongkey(bs)
+	c, err := New(Serpent1, key)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	K, KHat := c.Subkeys()
+	fromK, err := NewWithSubkeys(Serpent1, K)
+	if err != nil {
+		t.Fatalf("NewWithSubkeys failed: %v", err)
+	}
+	fromKHat, err := NewWithSubkeysHat(Serpent1, KHat)
+	if err != nil {
+		t.Fatalf("NewWithSubkeysHat failed: %v", err)
+	}
+	for _, d := range []*Cipher{fromK, fromKHat} {
+		gotK, gotKHat := d.Subkeys()
+		for i := range K {
+			if gotK[i] != K[i] || gotKHat[i] != KHat[i] {
+				t.Fatalf("subkey %d differs\n", i)
+			}
+		}
+		if d.Encrypt(testPlainText) != Encrypt(testPlainText, key) {
+			t.Errorf("Encrypt does not match the key schedule\n")
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := make(Bitslice, 33)
+	for i := range random {
+		random[i] = BitstringFromBytes(randomBytes(r, 16))
+	}
+	d, err := NewWithSubkeys(Serpent1, random)
+	if err != nil {
+		t.Fatalf("NewWithSubkeys failed: %v", err)
+	}
+	ct := d.Encrypt(testPlainText)
+	if d.EncryptBitslice(testPlainText) != ct {
+		t.Errorf("the algorithms disagree with independent subkeys\n")
+	}
+	if d.Decrypt(ct) != testPlainText {
+		t.Errorf("Decrypt does not invert Encrypt\n")
+	}
+}
+
+// Function TestNewWithSubkeysErrors checks the validation of the subkeys.
+func TestNewWithSubkeysErrors(t *testing.T) {
+	c, _ := New(Serpent1, bs)
+	K, _ := c.Subkeys()
+	for name, bad := range map[string]Bitslice{
+		"too few":    K[:32],
+		"too many":   append(K[:33:33], K[0]),
+		"short":      append(Bitslice{K[0][:127]}, K[1:]...),
+		"characters": append(Bitslice{K[0][:127] + "2"}, K[1:]...),
+	} {
+		if _, err := NewWithSubkeys(Serpent1, bad); err == nil {
+			t.Errorf("NewWithSubkeys accepts %s subkeys\n", name)
+		}
+		if _, err := NewWithSubkeysHat(Serpent1, bad); err == nil {
+			t.Errorf("NewWithSubkeysHat accepts %s subkeys\n", name)
+		}
+	}
+	p := Serpent1
+	p.Rounds = 4
+	if _, err := NewWithSubkeys(p, K[:5]); err != nil {
+		t.Errorf("NewWithSubkeys rejects 5 subkeys for 4 rounds: %v", err)
+	}
+	_, err := NewCipherWithSubkeys(Serpent1, make([]Uint128le, 32))
+	if err == nil {
+		t.Errorf("NewCipherWithSubkeys accepts 32 subkeys\n")
+	}
+}
+
+// Function TestNewCipherWithSubkeys checks the byte API against NewCipher
+// with the subkeys of random keys.
+func TestNewCipherWithSubkeys(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 4; i++ {
+		var key Uint256le
+		copy(key[:], randomBytes(r, 32))
+		want, _ := NewCipher(key[:])
+		b, err := NewCipherWithSubkeys(Serpent1, MakeSubkeysUint128le(key))
+		if err != nil {
+			t.Fatalf("NewCipherWithSubkeys failed: %v", err)
+		}
+		plain := randomBytes(r, BlockSize)
+		got, wantCT := make([]byte, BlockSize), make([]byte, BlockSize)
+		b.Encrypt(got, plain)
+		want.Encrypt(wantCT, plain)
+		if !bytes.Equal(got, wantCT) {
+			t.Errorf("Encrypt = %x, want %x\n", got, wantCT)
+		}
+		b.Decrypt(got, got)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("Decrypt does not invert Encrypt\n")
+		}
+	}
+}
@@ -0,0 +1,1017 @@
+package serpent
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// TrailKind selects between differential characteristics and linear
+// approximations.
+type TrailKind int
+
+const (
+	// DifferentialTrail searches for characteristics through the DDT.
+	// Weights are -log2 of the probability.
+	DifferentialTrail TrailKind = iota
+	// LinearTrail searches for approximations through the LAT. Weights
+	// are -log2 of the absolute correlation.
+	LinearTrail
+)
+
+func (k TrailKind) String() string {
+	switch k {
+	case DifferentialTrail:
+		return "differential"
+	case LinearTrail:
+		return "linear"
+	}
+	return fmt.Sprintf("TrailKind(%d)", int(k))
+}
+
+// Method MarshalText lets trails name their kind in JSON.
+func (k TrailKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// ErrNoTrail is returned by TrailSearch.Best when no trail is within the
+// weight bound.
+var ErrNoTrail = errors.New("serpent: no trail within the weight bound")
+
+// TrailSearch configures a branch-and-bound search, in the style of
+// Matsui's algorithm, for the best trail over the rounds described by
+// Params. Rounds StartRound to StartRound+Rounds-1 are covered, with the
+// linear transformation applied between consecutive S-Box layers.
+//
+// Trails are expressed in the normal (IP) domain where S-Box i acts on
+// bits 4i..4i+3, and state values are written as Hexstrings, the last hex
+// digit belonging to S-Box 0.
+type TrailSearch struct {
+	Params Params
+	Kind   TrailKind
+	// MaxWeight bounds the weight of the trails considered; the search
+	// gives up with ErrNoTrail beyond it. Zero means 128.
+	MaxWeight float64
+	// MaxActive, when positive, discards trails with more than this many
+	// active S-Boxes in any round.
+	MaxActive int
+}
+
+// TrailSBox is one active S-Box of a trail.
+type TrailSBox struct {
+	Position int     `json:"position"`
+	In       int     `json:"in"`
+	Out      int     `json:"out"`
+	Weight   float64 `json:"weight"`
+}
+
+// TrailRound is the part of a trail crossing the S-Box layer of one round.
+type TrailRound struct {
+	Round  int         `json:"round"`
+	SBox   int         `json:"sbox"`
+	Input  Hexstring   `json:"input"`
+	Output Hexstring   `json:"output"`
+	Active []TrailSBox `json:"active"`
+	Weight float64     `json:"weight"`
+}
+
+// Trail is a differential characteristic or linear approximation.
+type Trail struct {
+	Kind   TrailKind    `json:"kind"`
+	Rounds []TrailRound `json:"rounds"`
+	Weight float64      `json:"weight"`
+}
+
+// A state holds one nibble per S-Box, nibble i at bits 4i..4i+3.
+type trailState [2]uint64
+
+func (s trailState) nibble(i int) int {
+	return int(s[i/16]>>uint(4*(i%16))) & 0xf
+}
+
+func (s *trailState) setNibble(i, v int) {
+	shift := uint(4 * (i % 16))
+	s[i/16] = s[i/16]&^(0xf<<shift) | uint64(v)<<shift
+}
+
+// Method active appends the positions of the non-zero nibbles to 'buf'.
+func (s trailState) active(buf []int) []int {
+	for w := 0; w < 2; w++ {
+		x := s[w]
+		x = (x | x>>1 | x>>2 | x>>3) & 0x1111111111111111
+		for ; x != 0; x &= x - 1 {
+			buf = append(buf, 16*w+bits.TrailingZeros64(x)/4)
+		}
+	}
+	return buf
+}
+
+// Method count returns the number of non-zero nibbles.
+func (s trailState) count() int {
+	n := 0
+	for _, x := range s {
+		x = (x | x>>1 | x>>2 | x>>3) & 0x1111111111111111
+		n += bits.OnesCount64(x)
+	}
+	return n
+}
+
+func (s trailState) xor(t trailState) trailState {
+	return trailState{s[0] ^ t[0], s[1] ^ t[1]}
+}
+
+func (s trailState) or(t trailState) trailState {
+	return trailState{s[0] | t[0], s[1] | t[1]}
+}
+
+func (s trailState) andNot(t trailState) trailState {
+	return trailState{s[0] &^ t[0], s[1] &^ t[1]}
+}
+
+// Method spans sets every non-zero nibble of 's' to 0xf.
+func (s trailState) spans() trailState {
+	for i, x := range s {
+		x = (x | x>>1 | x>>2 | x>>3) & 0x1111111111111111
+		s[i] = x * 0xf
+	}
+	return s
+}
+
+func (s trailState) and(t trailState) trailState {
+	return trailState{s[0] & t[0], s[1] & t[1]}
+}
+
+func (s trailState) hex() Hexstring {
+	const digits = "0123456789abcdef"
+	h := make([]byte, 32)
+	for i := 0; i < 32; i++ {
+		h[31-i] = digits[s.nibble(i)]
+	}
+	return Hexstring(h)
+}
+
+// A transition is an input/output pair of an S-Box with its weight.
+type transition struct {
+	in, out int
+	w       float64
+}
+
+// trailBox holds the transitions of one S-Box ordered for the search.
+type trailBox struct {
+	index int
+	// byIn and byOut list, for each input and each output, the
+	// transitions through it by weight.
+	byIn, byOut [16][]transition
+	// minIn and minOut are the weights of the lightest transitions from
+	// each input and to each output.
+	minIn, minOut [16]float64
+	// inputs lists the non-zero inputs by the weight of their lightest
+	// transition; freeIn lists the lightest transition to each output,
+	// for rounds whose input is unconstrained.
+	inputs []int
+	freeIn []transition
+}
+
+// trailSearcher carries the state of one search.
+type trailSearcher struct {
+	TrailSearch
+	boxes []*trailBox
+	// forward[p][v] is the next round input caused by value v at
+	// position p of an S-Box layer output, backward[p][v] the previous
+	// round output accounting for value v at position p of an input.
+	forward, backward [32][16]trailState
+	// reach[0][p] and reach[1][p] have the S-Boxes of the next and of
+	// the previous round that position p can reach set to 0xf.
+	reach [2][32]trailState
+	// bestOver[a][k] is the weight of the best trail over the k rounds
+	// starting at round offset a.
+	bestOver [][]float64
+	// minWeight is the weight of the lightest transition of any S-Box.
+	minWeight float64
+
+	offset, rounds, light int
+	// lightWeight is the weight of the lightest round, the first round
+	// of the least weight. The rounds before it weigh more, the rounds
+	// after it at least as much.
+	lightWeight float64
+	// lightRest is the best weight of the rounds other than the
+	// lightest.
+	lightRest float64
+	bound     float64
+	// limit bounds the weight of the rounds on one side of the lightest
+	// round, which are searched apart from those on the other side.
+	limit      float64
+	sideFound  bool
+	saved      []savedRound
+	levels     []trailLevel
+	indexes    map[imageIndexKey]*imageIndex
+	found      bool
+	ins, outs  []trailState
+	actives    [][]TrailSBox
+	best       []TrailRound
+	bestWeight float64
+}
+
+// A candidate is a transition of an S-Box together with the weight it
+// fixes in the adjacent round, see chooseRound.
+type candidate struct {
+	t      transition
+	charge float64
+}
+
+func (c candidate) cost() float64 {
+	return c.t.w + c.charge
+}
+
+// A trailLevel holds the transitions chooseRound lists for the active
+// S-Boxes of a round, with the least weights of the S-Boxes from the k-th
+// on, without and with the charges, and the bounds on the rounds after it,
+// with and without the adjacent one.
+type trailLevel struct {
+	candidates          [32][]candidate
+	least, leastCharged [33]float64
+	rest, restAfter     float64
+}
+
+// A savedRound is a round of the lightest side found so far.
+type savedRound struct {
+	in, out trailState
+	active  []TrailSBox
+}
+
+const trailEpsilon = 1e-9
+
+// Method Best returns the lowest weight trail, or ErrNoTrail if none is
+// within MaxWeight.
+//
+// The search starts from the lightest round of the trail: as the trail
+// weight is at most the bound, some round weighs at most bound/Rounds. For
+// each choice of that round the trail is extended backwards and forwards,
+// pruning with the best weights over every shorter run of rounds, which
+// are computed first.
+//
+// The cost grows quickly with the weight of the optimum: three
+// differential and four linear rounds take about a second, but the optima
+// of four differential and five or more rounds are out of reach, so the
+// published best trails for four to seven rounds are not reproduced. A
+// MaxWeight below the optimum bounds the work and proves that no lighter
+// trail exists: from round 0, no four round differential trail weighs 30
+// or less and no five round linear trail 17 or less, which take a few
+// minutes each.
+func (s TrailSearch) Best() (*Trail, error) {
+	if err := s.Params.Validate(); err != nil {
+		return nil, err
+	}
+	if s.MaxWeight == 0 {
+		s.MaxWeight = 128
+	}
+	ts := newTrailSearcher(s)
+	n := s.Params.Rounds
+	ts.bestOver = make([][]float64, n+1)
+	for a := range ts.bestOver {
+		ts.bestOver[a] = make([]float64, n+1-a)
+	}
+	for k := 1; k <= n; k++ {
+		for a := 0; a+k <= n; a++ {
+			// Raise the bound from a lower estimate until a trail is
+			// found.
+			bound := math.Max(
+				ts.bestOver[a][k-1]+ts.boxes[a+k-1].freeIn[0].w,
+				ts.bestOver[a+1][k-1]+ts.boxes[a].freeIn[0].w)
+			for {
+				if bound > s.MaxWeight {
+					bound = s.MaxWeight
+				}
+				if ts.search(a, k, bound) {
+					break
+				}
+				if bound >= s.MaxWeight {
+					return nil, ErrNoTrail
+				}
+				bound += 1
+			}
+			ts.bestOver[a][k] = ts.bestWeight
+		}
+	}
+
+	t := &Trail{Kind: s.Kind, Rounds: ts.best}
+	for _, r := range t.Rounds {
+		t.Weight += r.Weight
+	}
+	return t, nil
+}
+
+// Function newTrailSearcher prepares the transition lists and the
+// propagation of values through the linear transformation.
+func newTrailSearcher(s TrailSearch) *trailSearcher {
+	ts := &trailSearcher{TrailSearch: s}
+	p := s.Params
+	for r := 0; r < p.Rounds; r++ {
+		i := p.StartRound + r
+		n := len(p.SBoxes)
+		box := newTrailBox(p.SBoxes[(i%n+n)%n], i, s.Kind)
+		if r == 0 || box.freeIn[0].w < ts.minWeight {
+			ts.minWeight = box.freeIn[0].w
+		}
+		ts.boxes = append(ts.boxes, box)
+	}
+
+	// Differences propagate through the tables themselves; masks
+	// through their transposes, swapping the roles of the two tables.
+	var forward, backward [128][]int
+	if s.Kind == DifferentialTrail {
+		for i, positions := range p.LTTable {
+			for _, j := range positions {
+				forward[j] = append(forward[j], i)
+			}
+		}
+		for i, positions := range p.LTTableInverse {
+			for _, j := range positions {
+				backward[j] = append(backward[j], i)
+			}
+		}
+	} else {
+		for i := 0; i < 128; i++ {
+			forward[i] = p.LTTableInverse[i]
+			backward[i] = p.LTTable[i]
+		}
+	}
+	for pos := 0; pos < 32; pos++ {
+		for v := 0; v < 16; v++ {
+			for b := 0; b < 4; b++ {
+				if v&(1<<uint(b)) == 0 {
+					continue
+				}
+				for _, j := range forward[4*pos+b] {
+					ts.forward[pos][v][j/64] ^= 1 << uint(j%64)
+				}
+				for _, j := range backward[4*pos+b] {
+					ts.backward[pos][v][j/64] ^= 1 << uint(j%64)
+				}
+			}
+			ts.reach[0][pos] = ts.reach[0][pos].or(
+				ts.forward[pos][v].spans())
+			ts.reach[1][pos] = ts.reach[1][pos].or(
+				ts.backward[pos][v].spans())
+		}
+	}
+	return ts
+}
+
+// Function newTrailBox orders the transitions of 'sbox' by weight.
+func newTrailBox(sbox SBox, index int, kind TrailKind) *trailBox {
+	var table SBoxTable
+	if kind == DifferentialTrail {
+		table = sbox.DDT()
+	} else {
+		table = sbox.LAT()
+	}
+	var all []transition
+	for a := 1; a < 16; a++ {
+		for b := 1; b < 16; b++ {
+			v := abs(table[a][b])
+			if v == 0 {
+				continue
+			}
+			var w float64
+			if kind == DifferentialTrail {
+				w = 4 - math.Log2(float64(v))
+			} else {
+				w = 3 - math.Log2(float64(v))
+			}
+			all = append(all, transition{in: a, out: b, w: w})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].w < all[j].w
+	})
+	tb := &trailBox{index: index}
+	for _, t := range all {
+		if len(tb.byIn[t.in]) == 0 {
+			tb.minIn[t.in] = t.w
+			tb.inputs = append(tb.inputs, t.in)
+		}
+		if len(tb.byOut[t.out]) == 0 {
+			tb.minOut[t.out] = t.w
+			tb.freeIn = append(tb.freeIn, t)
+		}
+		tb.byIn[t.in] = append(tb.byIn[t.in], t)
+		tb.byOut[t.out] = append(tb.byOut[t.out], t)
+	}
+	return tb
+}
+
+// Method over returns the best weight over the 'k' rounds of the current
+// segment starting at segment round 'r'.
+func (ts *trailSearcher) over(r, k int) float64 {
+	if k <= 0 {
+		return 0
+	}
+	return ts.bestOver[ts.offset+r][k]
+}
+
+// Method least returns the lowest weight round 'r' can have given its
+// input (forwards) or its output (backwards). Rounds outside the segment
+// weigh nothing.
+func (ts *trailSearcher) least(r int, s trailState, forwards bool) float64 {
+	if r < 0 || r >= ts.rounds {
+		return 0
+	}
+	box := ts.boxes[ts.offset+r]
+	min := &box.minOut
+	if forwards {
+		min = &box.minIn
+	}
+	w := 0.0
+	for _, x := range s {
+		for ; x != 0; x &^= 0xf << lowNibble(x) {
+			w += min[x>>lowNibble(x)&0xf]
+		}
+	}
+	return w
+}
+
+// Method search looks for the best trail over 'rounds' rounds starting at
+// round offset 'offset', of weight at most 'bound'. On success the trail
+// is kept in best and its weight in bestWeight.
+func (ts *trailSearcher) search(offset, rounds int, bound float64) bool {
+	ts.offset, ts.rounds, ts.bound = offset, rounds, bound
+	ts.found = false
+	ts.ins = make([]trailState, rounds)
+	ts.outs = make([]trailState, rounds)
+	ts.actives = make([][]TrailSBox, rounds)
+	ts.levels = make([]trailLevel, rounds)
+	ts.saved = make([]savedRound, rounds)
+	for ts.light = 0; ts.light < rounds; ts.light++ {
+		ts.lightWeight = 0
+		ts.lightRest = ts.over(0, ts.light) +
+			ts.over(ts.light+1, ts.rounds-ts.light-1)
+		if ts.light == 0 {
+			ts.lightFree(0, trailState{}, trailState{}, trailState{},
+				make([]TrailSBox, 0, 32), 0)
+		} else {
+			ts.lightInputs(0, trailState{}, trailState{},
+				make([]int, 0, 32), 0)
+		}
+	}
+	return ts.found
+}
+
+// Method budget returns the most the lightest round may weigh.
+func (ts *trailSearcher) budget() float64 {
+	return math.Min(ts.bound/float64(ts.rounds), ts.bound-ts.lightRest)
+}
+
+// Method neighbour returns the most active S-Boxes the round next to the
+// lightest one, before it when 'before' is set, may have if the lightest
+// round weighs 'w'. It is negative when there is no such round.
+func (ts *trailSearcher) neighbour(w float64, before bool) int {
+	l, n := ts.light, ts.rounds
+	var rest float64
+	if before {
+		if l == 0 {
+			return -1
+		}
+		rest = ts.atLeastWith(0, l-1, w) + ts.atLeastWith(l+1, n-l-1, w)
+	} else {
+		if l == n-1 {
+			return -1
+		}
+		rest = ts.atLeastWith(0, l, w) + ts.atLeastWith(l+2, n-l-2, w)
+	}
+	return int((ts.bound + trailEpsilon - w - rest) / ts.minWeight)
+}
+
+// Method atLeastWith bounds the weight of the 'k' rounds of the current
+// segment starting at segment round 'r' from below when the lightest round
+// weighs 'w'.
+func (ts *trailSearcher) atLeastWith(r, k int, w float64) float64 {
+	return math.Max(ts.over(r, k), float64(k)*w)
+}
+
+// Method lightFree chooses the outputs of the S-Boxes at position 'pos' and
+// onwards when the lightest round is the first one, each with its lightest
+// input. 'next' is the input of the next round caused by the outputs
+// chosen so far. Once at most one more S-Box fits in the budget, it is
+// looked up among those that leave few S-Boxes active in the next round.
+func (ts *trailSearcher) lightFree(pos int, in, out, next trailState,
+	active []TrailSBox, w float64) {
+	box := ts.boxes[ts.offset]
+	lightest := box.freeIn[0].w
+	if pos < 32 && w+2*lightest <= ts.budget()+trailEpsilon {
+		ts.lightFree(pos+1, in, out, next, active, w)
+		if ts.MaxActive > 0 && len(active) >= ts.MaxActive {
+			return
+		}
+		for _, t := range box.freeIn {
+			if w+t.w > ts.budget()+trailEpsilon {
+				break
+			}
+			nin, nout := in, out
+			nin.setNibble(pos, t.in)
+			nout.setNibble(pos, t.out)
+			ts.lightFree(pos+1, nin, nout,
+				next.xor(ts.forward[pos][t.out]),
+				append(active, TrailSBox{pos, t.in, t.out, t.w}), w+t.w)
+		}
+		return
+	}
+
+	if len(active) > 0 {
+		ts.lightDone(in, out, next, active, w, 0)
+	}
+	if pos == 32 || (ts.MaxActive > 0 && len(active) >= ts.MaxActive) {
+		return
+	}
+	ts.lastNibble(&ts.forward, pos, next, ts.neighbour(w+lightest, false),
+		func(p, v int) {
+			t := box.byOut[v][0]
+			if w+t.w > ts.budget()+trailEpsilon {
+				return
+			}
+			nin, nout := in, out
+			nin.setNibble(p, t.in)
+			nout.setNibble(p, t.out)
+			ts.lightDone(nin, nout, next.xor(ts.forward[p][v]),
+				append(active, TrailSBox{p, t.in, t.out, t.w}), w+t.w, 0)
+		})
+}
+
+// Method lightInputs chooses the inputs of the S-Boxes at position 'pos'
+// and onwards in the lightest round. 'w' is the least weight of the round
+// given these inputs and 'prev' the output of the previous round they
+// require. Once at most one more S-Box fits in the budget, it is looked up
+// among those that leave few S-Boxes active in the previous round.
+func (ts *trailSearcher) lightInputs(pos int, in, prev trailState,
+	positions []int, w float64) {
+	box := ts.boxes[ts.offset+ts.light]
+	lightest := box.minIn[box.inputs[0]]
+	if pos < 32 && w+2*lightest <= ts.budget()+trailEpsilon {
+		ts.lightInputs(pos+1, in, prev, positions, w)
+		if ts.MaxActive > 0 && len(positions) >= ts.MaxActive {
+			return
+		}
+		for _, v := range box.inputs {
+			if w+box.minIn[v] > ts.budget()+trailEpsilon {
+				break
+			}
+			nin := in
+			nin.setNibble(pos, v)
+			ts.lightInputs(pos+1, nin, prev.xor(ts.backward[pos][v]),
+				append(positions, pos), w+box.minIn[v])
+		}
+		return
+	}
+
+	if len(positions) > 0 {
+		ts.inputsDone(in, prev, positions, w)
+	}
+	if pos == 32 || (ts.MaxActive > 0 && len(positions) >= ts.MaxActive) {
+		return
+	}
+	ts.lastNibble(&ts.backward, pos, prev, ts.neighbour(w+lightest, true),
+		func(p, v int) {
+			if w+box.minIn[v] > ts.budget()+trailEpsilon {
+				return
+			}
+			nin := in
+			nin.setNibble(p, v)
+			ts.inputsDone(nin, prev.xor(ts.backward[p][v]),
+				append(positions, p), w+box.minIn[v])
+		})
+}
+
+// Method inputsDone continues with a complete choice of inputs of the
+// lightest round, unless the rounds before cannot be light enough.
+func (ts *trailSearcher) inputsDone(in, prev trailState, positions []int,
+	w float64) {
+	after := ts.atLeastWith(ts.light+1, ts.rounds-ts.light-1, w)
+	rest := ts.atLeastWith(0, ts.light-1, w)
+	// Counting the active S-Boxes of the previous round is cheaper than
+	// weighing them and rules out most inputs.
+	low := math.Max(float64(prev.count())*ts.minWeight, w)
+	if w+low+rest+after > ts.bound+trailEpsilon {
+		return
+	}
+	low = math.Max(ts.least(ts.light-1, prev, false), w)
+	before := math.Max(ts.over(0, ts.light), low+rest)
+	if w+before+after > ts.bound+trailEpsilon {
+		return
+	}
+	ts.lightOutputs(in, positions, 0, trailState{}, trailState{},
+		make([]TrailSBox, 0, 32), 0, w, before)
+}
+
+// Method lastNibble calls 'f' with the position p >= 'pos' and value v of
+// every nibble whose image in 'images' turns 'partial' into a state with
+// at most 'most' active S-Boxes. If at most 'most' are active, one of
+// most+1 runs of positions is all zero, so that the image agrees with
+// 'partial' on it: the candidates are looked up by their image on each
+// run, counting each under the first run it agrees on only.
+func (ts *trailSearcher) lastNibble(images *[32][16]trailState, pos int,
+	partial trailState, most int, f func(p, v int)) {
+	if most < 0 {
+		// There is no neighbouring round to constrain the nibble.
+		for p := pos; p < 32; p++ {
+			for v := 1; v < 16; v++ {
+				f(p, v)
+			}
+		}
+		return
+	}
+	index := ts.imageIndex(images, most+1)
+	for run, mask := range index.masks {
+		for _, c := range index.byRun[run][partial.and(mask)] {
+			if c.pos < pos {
+				continue
+			}
+			s := partial.xor(images[c.pos][c.v])
+			first := true
+			for _, m := range index.masks[:run] {
+				if s.and(m) == (trailState{}) {
+					first = false
+					break
+				}
+			}
+			if first && s.count() <= most {
+				f(c.pos, c.v)
+			}
+		}
+	}
+}
+
+// A nibbleValue is a value at a nibble position.
+type nibbleValue struct {
+	pos, v int
+}
+
+// An imageIndex lists the nibble values by their images on each of a
+// number of runs of nibble positions.
+type imageIndex struct {
+	masks []trailState
+	byRun []map[trailState][]nibbleValue
+}
+
+// Method imageIndex returns the index of 'images' over 'runs' runs,
+// building it on first use.
+func (ts *trailSearcher) imageIndex(images *[32][16]trailState,
+	runs int) *imageIndex {
+	if runs > 32 {
+		runs = 32
+	}
+	key := imageIndexKey{images == &ts.backward, runs}
+	if index, ok := ts.indexes[key]; ok {
+		return index
+	}
+	index := &imageIndex{}
+	for run := 0; run < runs; run++ {
+		var mask trailState
+		for p := 32 * run / runs; p < 32*(run+1)/runs; p++ {
+			mask.setNibble(p, 0xf)
+		}
+		byRun := make(map[trailState][]nibbleValue)
+		for p := 0; p < 32; p++ {
+			for v := 1; v < 16; v++ {
+				k := images[p][v].and(mask)
+				byRun[k] = append(byRun[k], nibbleValue{p, v})
+			}
+		}
+		index.masks = append(index.masks, mask)
+		index.byRun = append(index.byRun, byRun)
+	}
+	if ts.indexes == nil {
+		ts.indexes = make(map[imageIndexKey]*imageIndex)
+	}
+	ts.indexes[key] = index
+	return index
+}
+
+type imageIndexKey struct {
+	backward bool
+	runs     int
+}
+
+// Method lightOutputs chooses the output of the k-th active S-Box of the
+// lightest round. 'lower' is the least weight the S-Boxes not yet chosen
+// can add and 'before' the least weight of the rounds before.
+func (ts *trailSearcher) lightOutputs(in trailState, positions []int,
+	k int, out, next trailState, active []TrailSBox, w, lower,
+	before float64) {
+	if k == len(positions) {
+		ts.lightDone(in, out, next, active, w, before)
+		return
+	}
+	box := ts.boxes[ts.offset+ts.light]
+	pos := positions[k]
+	v := in.nibble(pos)
+	lower -= box.minIn[v]
+	for _, t := range box.byIn[v] {
+		if w+t.w+lower > ts.budget()+trailEpsilon {
+			break
+		}
+		nout := out
+		nout.setNibble(pos, t.out)
+		ts.lightOutputs(in, positions, k+1, nout,
+			next.xor(ts.forward[pos][t.out]),
+			append(active, TrailSBox{pos, t.in, t.out, t.w}), w+t.w,
+			lower, before)
+		if ts.light == ts.rounds-1 {
+			// The output of the last round is free.
+			break
+		}
+	}
+}
+
+// Method lightDone extends a complete choice of the lightest round
+// backwards and forwards, unless the rounds after it cannot be light
+// enough. 'next' is the input of the next round. Given the lightest round
+// the two sides are independent, so the lightest extension of each is
+// searched on its own.
+func (ts *trailSearcher) lightDone(in, out, next trailState,
+	active []TrailSBox, w, before float64) {
+	r := ts.light
+	before = math.Max(before, ts.atLeastWith(0, r, w))
+	after := 0.0
+	if n := ts.rounds - r - 1; n > 0 {
+		low := math.Max(ts.least(r+1, next, true), w)
+		after = math.Max(ts.over(r+1, n),
+			low+ts.atLeastWith(r+2, n-1, w))
+	}
+	if w+before+after > ts.bound+trailEpsilon {
+		return
+	}
+	ts.lightWeight = w
+	ts.setRound(r, in, out, active)
+	var back, forth float64
+	if r > 0 {
+		ts.limit, ts.sideFound = ts.bound-w-after, false
+		ts.prevRound(r-1, ts.spread(&ts.backward, in), 0)
+		if !ts.sideFound {
+			return
+		}
+		back = ts.limit + 2*trailEpsilon
+	}
+	if r < ts.rounds-1 {
+		ts.limit, ts.sideFound = ts.bound-w-back, false
+		ts.nextRound(r+1, next, 0)
+		if !ts.sideFound {
+			return
+		}
+		forth = ts.limit + 2*trailEpsilon
+	}
+	// Restore the lightest round, which the sides may have overwritten
+	// while the search backtracked.
+	ts.setRound(r, in, out, active)
+	for i := range ts.saved {
+		if i != r {
+			sv := ts.saved[i]
+			ts.setRound(i, sv.in, sv.out, sv.active)
+		}
+	}
+	// Keep the trail and only look for strictly lighter ones.
+	ts.found = true
+	ts.keep(back + w + forth)
+	ts.bound = back + w + forth - 2*trailEpsilon
+}
+
+// Method prevRound extends the rounds before the lightest one backwards
+// into round 'r' whose output is 'out'. 'w' is the weight of the rounds
+// after 'r' and before the lightest one.
+func (ts *trailSearcher) prevRound(r int, out trailState, w float64) {
+	if r < 0 {
+		ts.side(0, ts.light, w)
+		return
+	}
+	ts.chooseRound(r, out, false, w, ts.atLeast(0, r))
+}
+
+// Method nextRound extends the rounds after the lightest one forwards
+// into round 'r' whose input is 'in'. 'w' is the weight of the rounds
+// after the lightest one and before 'r'.
+func (ts *trailSearcher) nextRound(r int, in trailState, w float64) {
+	if r == ts.rounds {
+		ts.side(ts.light+1, ts.rounds, w)
+		return
+	}
+	ts.chooseRound(r, in, true, w, ts.atLeast(r+1, ts.rounds-r-1))
+}
+
+// Method side saves rounds 'from' to 'to' of the trail being built, a
+// complete side of weight 'w', and looks for strictly lighter sides only.
+func (ts *trailSearcher) side(from, to int, w float64) {
+	for i := from; i < to; i++ {
+		ts.saved[i] = savedRound{ts.ins[i], ts.outs[i],
+			append([]TrailSBox(nil), ts.actives[i]...)}
+	}
+	ts.sideFound = true
+	ts.limit = w - 2*trailEpsilon
+}
+
+// Method atLeast bounds the weight of the 'k' rounds of the current
+// segment starting at segment round 'r' from below, none of which is the
+// lightest round.
+func (ts *trailSearcher) atLeast(r, k int) float64 {
+	return math.Max(ts.over(r, k), float64(k)*ts.lightWeight)
+}
+
+// Method chooseRound enumerates the transitions of round 'r' given its
+// input (forwards) or its output (backwards). 'rest' bounds the weight of
+// the rounds still to be chosen after this one.
+//
+// Some S-Boxes of the adjacent round still to be chosen may be reached
+// from a single active S-Box of this round only. Their values then follow
+// from the transition of that S-Box alone, so their least weight is
+// charged to it, which bounds the two rounds together much more closely.
+func (ts *trailSearcher) chooseRound(r int, fixed trailState,
+	forwards bool, w, rest float64) {
+	lower := ts.least(r, fixed, forwards)
+	if w+math.Max(lower, ts.lightWeight)+rest > ts.limit+trailEpsilon {
+		return
+	}
+	var buf [32]int
+	positions := fixed.active(buf[:0])
+	if ts.MaxActive > 0 && len(positions) > ts.MaxActive {
+		return
+	}
+	// The input of the first round and the output of the last are
+	// free, so only the lightest transition matters there.
+	free := (forwards && r == ts.rounds-1) || (!forwards && r == 0)
+	lv := &ts.levels[r]
+	lv.rest, lv.restAfter = rest, rest
+	dir, next := 1, r+1
+	if forwards {
+		dir = 0
+	} else {
+		next = r - 1
+	}
+	if !free {
+		if forwards {
+			lv.restAfter = ts.atLeast(r+2, ts.rounds-r-2)
+		} else {
+			lv.restAfter = ts.atLeast(0, r-1)
+		}
+	}
+	// others[k] is the reach of the active S-Boxes after the k-th.
+	var others [33]trailState
+	for k := len(positions) - 1; k >= 0; k-- {
+		others[k] = others[k+1].or(ts.reach[dir][positions[k]])
+	}
+	var before trailState
+	box := ts.boxes[ts.offset+r]
+	lv.least[len(positions)], lv.leastCharged[len(positions)] = 0, 0
+	for k, pos := range positions {
+		v := fixed.nibble(pos)
+		list := box.byOut[v]
+		if forwards {
+			list = box.byIn[v]
+		}
+		cs := lv.candidates[k][:0]
+		own := ts.reach[dir][pos].andNot(before.or(others[k+1]))
+		before = before.or(ts.reach[dir][pos])
+		for _, t := range list {
+			c := candidate{t: t}
+			if !free && (own[0] != 0 || own[1] != 0) {
+				o := t.in
+				if forwards {
+					o = t.out
+				}
+				c.charge = ts.least(next,
+					ts.image(forwards, pos, o).and(own), forwards)
+			}
+			cs = append(cs, c)
+		}
+		sort.SliceStable(cs, func(i, j int) bool {
+			return cs[i].cost() < cs[j].cost()
+		})
+		lv.candidates[k] = cs
+	}
+	for k := len(positions) - 1; k >= 0; k-- {
+		cs := lv.candidates[k]
+		minW := cs[0].t.w
+		for _, c := range cs {
+			minW = math.Min(minW, c.t.w)
+		}
+		lv.least[k] = lv.least[k+1] + minW
+		lv.leastCharged[k] = lv.leastCharged[k+1] + cs[0].cost()
+	}
+	if w+lv.leastCharged[0]+lv.restAfter > ts.limit+trailEpsilon {
+		return
+	}
+	ts.chooseSBox(r, fixed, forwards, free, positions, 0, trailState{},
+		make([]TrailSBox, 0, len(positions)), w, 0)
+}
+
+// Method image returns the state of the adjacent round that value 'v' at
+// position 'pos' of this round leads to.
+func (ts *trailSearcher) image(forwards bool, pos, v int) trailState {
+	if forwards {
+		return ts.forward[pos][v]
+	}
+	return ts.backward[pos][v]
+}
+
+// Method chooseSBox picks the transition of the k-th active S-Box of
+// round 'r'. 'charged' is the weight charged so far to the adjacent round.
+func (ts *trailSearcher) chooseSBox(r int, fixed trailState, forwards,
+	free bool, positions []int, k int, other trailState,
+	active []TrailSBox, w, charged float64) {
+	if k == len(positions) {
+		// Trails with an earlier round as light as the lightest one
+		// are found from that round.
+		var rw float64
+		for _, a := range active {
+			rw += a.Weight
+		}
+		if rw < ts.lightWeight-trailEpsilon ||
+			(r < ts.light && rw <= ts.lightWeight+trailEpsilon) {
+			return
+		}
+		in, out := other, fixed
+		if forwards {
+			in, out = fixed, other
+		}
+		ts.setRound(r, in, out, active)
+		if forwards {
+			ts.nextRound(r+1, ts.spread(&ts.forward, out), w)
+		} else {
+			ts.prevRound(r-1, ts.spread(&ts.backward, in), w)
+		}
+		return
+	}
+	pos := positions[k]
+	lv := &ts.levels[r]
+	for _, c := range lv.candidates[k] {
+		t := c.t
+		if w+charged+c.cost()+lv.leastCharged[k+1]+lv.restAfter >
+			ts.limit+trailEpsilon {
+			break
+		}
+		if w+t.w+lv.least[k+1]+lv.rest > ts.limit+trailEpsilon {
+			continue
+		}
+		nother := other
+		if forwards {
+			nother.setNibble(pos, t.out)
+		} else {
+			nother.setNibble(pos, t.in)
+		}
+		ts.chooseSBox(r, fixed, forwards, free, positions, k+1, nother,
+			append(active, TrailSBox{pos, t.in, t.out, t.w}), w+t.w,
+			charged+c.charge)
+		if free {
+			break
+		}
+	}
+}
+
+// Method setRound records round 'r' of the trail being built.
+func (ts *trailSearcher) setRound(r int, in, out trailState,
+	active []TrailSBox) {
+	ts.ins[r], ts.outs[r], ts.actives[r] = in, out, active
+}
+
+// Method keep copies the trail being built into best.
+func (ts *trailSearcher) keep(w float64) {
+	ts.best = make([]TrailRound, ts.rounds)
+	for r := range ts.best {
+		box := ts.boxes[ts.offset+r]
+		tr := TrailRound{
+			Round:  box.index,
+			SBox:   box.index % len(ts.Params.SBoxes),
+			Input:  ts.ins[r].hex(),
+			Output: ts.outs[r].hex(),
+			Active: append([]TrailSBox(nil), ts.actives[r]...),
+		}
+		sort.Slice(tr.Active, func(i, j int) bool {
+			return tr.Active[i].Position < tr.Active[j].Position
+		})
+		for _, a := range tr.Active {
+			tr.Weight += a.Weight
+		}
+		ts.best[r] = tr
+	}
+	ts.bestWeight = w
+}
+
+// Method spread maps a state across the linear transformation using the
+// per-nibble images in 'table'.
+func (ts *trailSearcher) spread(table *[32][16]trailState,
+	s trailState) (result trailState) {
+	for h, x := range s {
+		for ; x != 0; x &^= 0xf << lowNibble(x) {
+			shift := lowNibble(x)
+			t := table[16*h+int(shift/4)][x>>shift&0xf]
+			result[0] ^= t[0]
+			result[1] ^= t[1]
+		}
+	}
+	return
+}
+
+// Function lowNibble returns the shift of the lowest non-zero nibble of
+// 'x', which must not be zero.
+func lowNibble(x uint64) uint {
+	return uint(bits.TrailingZeros64(x)) &^ 3
+}
@@ -0,0 +1,183 @@
+package serpent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function searchTrail runs a trail search over 'rounds' rounds of Serpent
+// starting at round 'start'.
+func searchTrail(t *testing.T, kind TrailKind, start, rounds int,
+	maxWeight float64) (*Trail, error) {
+	p := Serpent1
+	p.StartRound = start
+	p.Rounds = rounds
+	return TrailSearch{Params: p, Kind: kind, MaxWeight: maxWeight}.Best()
+}
+
+// Function TestTrailOneRound checks the single round optimum: probability
+// 2^-2 for differentials and correlation 2^-1 for linear approximations.
+func TestTrailOneRound(t *testing.T) {
+	for start := 0; start < 8; start++ {
+		d, err := searchTrail(t, DifferentialTrail, start, 1, 0)
+		if err != nil || d.Weight != 2 {
+			t.Errorf("round %d: differential weight %v, %v\n", start,
+				d, err)
+		}
+		l, err := searchTrail(t, LinearTrail, start, 1, 0)
+		if err != nil || l.Weight != 1 {
+			t.Errorf("round %d: linear weight %v, %v\n", start, l, err)
+		}
+	}
+}
+
+// Function TestTrailTwoRounds checks the best two round trails and that
+// they are consistent with the S-Boxes and the linear transformation.
+func TestTrailTwoRounds(t *testing.T) {
+	d, err := searchTrail(t, DifferentialTrail, 0, 2, 0)
+	if err != nil {
+		t.Fatalf("differential search failed: %v\n", err)
+	}
+	if d.Weight != 6 {
+		t.Errorf("differential weight %v, want 6\n", d.Weight)
+	}
+	checkTrail(t, d)
+	if LT(d.Rounds[0].Output.ToBitstring()) !=
+		d.Rounds[1].Input.ToBitstring() {
+		t.Errorf("differences do not follow LT\n")
+	}
+
+	l, err := searchTrail(t, LinearTrail, 0, 2, 0)
+	if err != nil {
+		t.Fatalf("linear search failed: %v\n", err)
+	}
+	if l.Weight != 3 {
+		t.Errorf("linear weight %v, want 3\n", l.Weight)
+	}
+	checkTrail(t, l)
+	// The output mask of round 0 and the input mask of round 1 must
+	// select the same parity across LT.
+	alpha := l.Rounds[0].Output.ToBitstring()
+	gamma := l.Rounds[1].Input.ToBitstring()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		y := BitstringFromBytes(randomBytes(r, 16))
+		if maskParity(alpha, y) != maskParity(gamma, LT(y)) {
+			t.Errorf("masks do not follow LT\n")
+			break
+		}
+	}
+
+	if _, err := searchTrail(t, DifferentialTrail, 0, 2, 5); err != ErrNoTrail {
+		t.Errorf("bound below the optimum gave %v\n", err)
+	}
+}
+
+// Function TestTrailThreeRounds checks the best three round trails, whose
+// differential weights depend on the round the trail starts at.
+func TestTrailThreeRounds(t *testing.T) {
+	for start, want := range []float64{19, 16, 15, 16} {
+		d, err := searchTrail(t, DifferentialTrail, start, 3, 0)
+		if err != nil {
+			t.Fatalf("round %d: differential search failed: %v\n", start,
+				err)
+		}
+		if d.Weight != want {
+			t.Errorf("round %d: differential weight %v, want %v\n", start,
+				d.Weight, want)
+		}
+		checkTrail(t, d)
+		for r := 1; r < len(d.Rounds); r++ {
+			if LT(d.Rounds[r-1].Output.ToBitstring()) !=
+				d.Rounds[r].Input.ToBitstring() {
+				t.Errorf("round %d: differences do not follow LT\n",
+					d.Rounds[r].Round)
+			}
+		}
+
+		l, err := searchTrail(t, LinearTrail, start, 3, 0)
+		if err != nil {
+			t.Fatalf("round %d: linear search failed: %v\n", start, err)
+		}
+		if l.Weight != 7 {
+			t.Errorf("round %d: linear weight %v, want 7\n", start,
+				l.Weight)
+		}
+		checkTrail(t, l)
+	}
+}
+
+// Function TestTrailFourRounds checks the best four round linear trail.
+func TestTrailFourRounds(t *testing.T) {
+	l, err := searchTrail(t, LinearTrail, 0, 4, 0)
+	if err != nil {
+		t.Fatalf("linear search failed: %v\n", err)
+	}
+	if l.Weight != 12 {
+		t.Errorf("linear weight %v, want 12\n", l.Weight)
+	}
+	checkTrail(t, l)
+}
+
+// Function TestTrailBounds checks that MaxWeight bounds the search: an
+// optimum at the bound is still found, and longer trails below a bound
+// are ruled out. The bounds lie above what the shorter optima imply, 21
+// for four differential rounds (19 + 2) and 13 for five linear rounds
+// (12 + 1).
+func TestTrailBounds(t *testing.T) {
+	d, err := searchTrail(t, DifferentialTrail, 0, 3, 19)
+	if err != nil || d.Weight != 19 {
+		t.Errorf("bound at the optimum gave %v, %v\n", d, err)
+	}
+	if _, err := searchTrail(t, DifferentialTrail, 0, 4,
+		24); err != ErrNoTrail {
+		t.Errorf("4 differential rounds: bound of 24 gave %v\n", err)
+	}
+	if testing.Short() {
+		t.Skip("skipping the five round linear bound in short mode")
+	}
+	if _, err := searchTrail(t, LinearTrail, 0, 5, 15); err != ErrNoTrail {
+		t.Errorf("5 linear rounds: bound of 15 gave %v\n", err)
+	}
+}
+
+// Function checkTrail checks every active S-Box of 'trail' against the
+// DDT or LAT and the recorded weights.
+func checkTrail(t *testing.T, trail *Trail) {
+	var total float64
+	for _, r := range trail.Rounds {
+		sbox := SBoxDecimalTable[r.SBox]
+		table := sbox.DDT()
+		if trail.Kind == LinearTrail {
+			table = sbox.LAT()
+		}
+		in := r.Input.ToBitstring()
+		for _, a := range r.Active {
+			if table[a.In][a.Out] == 0 {
+				t.Errorf("round %d: impossible transition %d -> %d\n",
+					r.Round, a.In, a.Out)
+			}
+			var bs Bitstring
+			if in[4*a.Position:4*a.Position+4] != bs.FromInt(a.In, 4) {
+				t.Errorf("round %d: input does not match S-Box %d\n",
+					r.Round, a.Position)
+			}
+		}
+		total += r.Weight
+	}
+	if total != trail.Weight {
+		t.Errorf("round weights sum to %v, trail weight %v\n", total,
+			trail.Weight)
+	}
+}
+
+// Function maskParity returns the parity of the bits of 'x' selected by
+// 'mask'.
+func maskParity(mask, x Bitstring) (p int) {
+	for i := range mask {
+		if mask[i] == '1' && x[i] == '1' {
+			p ^= 1
+		}
+	}
+	return
+}
@@ -0,0 +1,362 @@
+package serpent
+
+import (
+	"fmt"
+)
+
+// A variant holds a validated Params together with the lookup tables
+// derived from it. The package level functions use the variant built from
+// Serpent1.
+type variant struct {
+	Params
+	sbox        []map[Bitstring]Bitstring
+	sboxInverse []map[Bitstring]Bitstring
+	// standardLT is set when the tables are those of Serpent1, in which
+	// case the equations-based LTBitslice can be used.
+	standardLT bool
+	// standardSBoxes is set when the S-Boxes are those of Serpent1, in
+	// which case the word core uses the generated S-Box circuits.
+	standardSBoxes bool
+	// unrolled is set for the full Serpent-1 when the package is built
+	// with rounds_words.go, in which case the word core uses the generated
+	// unrolled rounds.
+	unrolled bool
+}
+
+var serpent1 *variant = newVariant(Serpent1)
+
+// Function newVariant builds the S-Box lookup tables for 'p'. The
+// parameters must already have been validated.
+func newVariant(p Params) *variant {
+	var bs Bitstring
+	v := &variant{Params: p}
+	for _, sbox := range p.SBoxes {
+		var dict map[Bitstring]Bitstring = make(
+			map[Bitstring]Bitstring, len(sbox))
+		var inverseDict map[Bitstring]Bitstring = make(
+			map[Bitstring]Bitstring, len(sbox))
+
+		for boxindex, box := range sbox {
+			index := bs.FromInt(boxindex, 4)
+			value := bs.FromInt(box, 4)
+			dict[index] = value
+			inverseDict[value] = index
+		}
+		v.sbox = append(v.sbox, dict)
+		v.sboxInverse = append(v.sboxInverse, inverseDict)
+	}
+	v.standardLT = ttablesEqual(p.LTTable, LTTable) &&
+		ttablesEqual(p.LTTableInverse, LTTableInverse)
+	v.standardSBoxes = sboxesEqual(p.SBoxes, SBoxDecimalTable)
+	v.unrolled = haveUnrolled && v.standardSBoxes && v.standardLT &&
+		p.StartRound == 0 && p.Rounds == round
+
+	return v
+}
+
+// Function sboxesEqual reports whether 'a' and 'b' hold the same S-Boxes.
+func sboxesEqual(a, b []SBox) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for x := range a[i] {
+			if a[i][x] != b[i][x] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Method lastRound returns the index of the final round, the one in which
+// the linear transformation is replaced by key mixing.
+func (v *variant) lastRound() int {
+	return v.StartRound + v.Rounds - 1
+}
+
+// Method subkeyCount returns the number of subkeys the key schedule has to
+// produce: one for each round up to the last and one for the final key
+// mixing.
+func (v *variant) subkeyCount() int {
+	return v.lastRound() + 2
+}
+
+// Method box maps a round or subkey index onto the index of an S-Box.
+func (v *variant) box(i int) int {
+	n := len(v.sbox)
+	return (i%n + n) % n
+}
+
+// Method keyScheduleBox returns the S-Box index used to derive subkey 'i'.
+func (v *variant) keyScheduleBox(i int) int {
+	if v.KeyScheduleSBox != nil {
+		return v.KeyScheduleSBox(i)
+	}
+	return 3 - i
+}
+
+func (v *variant) s(box int, input Bitstring) Bitstring {
+	return v.sbox[v.box(box)][input]
+}
+
+func (v *variant) sInverse(box int, output Bitstring) Bitstring {
+	return v.sboxInverse[v.box(box)][output]
+}
+
+func (v *variant) sHat(box int, input Bitstring) Bitstring {
+	var bs Bitstring
+	for i := 0; i < 32; i++ {
+		bs = bs + v.s(box, input[4*i:4*(i+1)])
+	}
+	return bs
+}
+
+func (v *variant) sHatInverse(box int, output Bitstring) Bitstring {
+	var bs Bitstring
+	for i := 0; i < 32; i++ {
+		bs = bs + v.sInverse(box, output[4*i:4*(i+1)])
+	}
+	return bs
+}
+
+func (v *variant) sBitslice(box int, words Bitslice) Bitslice {
+	return v.applyBitslice(v.s, box, words)
+}
+
+func (v *variant) sBitsliceInverse(box int, words Bitslice) Bitslice {
+	return v.applyBitslice(v.sInverse, box, words)
+}
+
+// Method applyBitslice applies 'sbox' to the 4 bits found at each of the
+// 32 positions of 'words' and collects the output bits in the same
+// positions of the result.
+func (v *variant) applyBitslice(sbox func(int, Bitstring) Bitstring,
+	box int, words Bitslice) Bitslice {
+	result := make(Bitslice, 4)
+	for i := 0; i < 32; i++ {
+		var c0 Bitstring = Bitstring(words[0][i])
+		var c1 Bitstring = Bitstring(words[1][i])
+		var c2 Bitstring = Bitstring(words[2][i])
+		var c3 Bitstring = Bitstring(words[3][i])
+		quad := sbox(box, Bitstring(c0+c1+c2+c3))
+		for j := 0; j < 4; j++ {
+			result[j] = result[j] + Bitstring(quad[j])
+		}
+	}
+	return result
+}
+
+func (v *variant) lt(input Bitstring) Bitstring {
+	return applyLTTable(v.LTTable, input)
+}
+
+func (v *variant) ltInverse(output Bitstring) Bitstring {
+	return applyLTTable(v.LTTableInverse, output)
+}
+
+// Method ltBitslice applies the linear transformation in the bitslice
+// domain. Tables other than the standard ones have no equations-based
+// version, so they are applied between IP and FP instead.
+func (v *variant) ltBitslice(x Bitslice) Bitslice {
+	if v.standardLT {
+		return LTBitslice(x)
+	}
+	var bs Bitstring
+	return FP(v.lt(IP(bs.QuadJoin(x)))).QuadSplit()
+}
+
+func (v *variant) ltBitsliceInverse(x Bitslice) Bitslice {
+	if v.standardLT {
+		return LTBitsliceInverse(x)
+	}
+	var bs Bitstring
+	return FP(v.ltInverse(IP(bs.QuadJoin(x)))).QuadSplit()
+}
+
+func (v *variant) r(i int, BHati Bitstring, KHat keyMixer) Bitstring {
+	var xored Bitstring
+	var BHatiPlus1 Bitstring
+	last := v.lastRound()
+	xored = KHat.mixKey(i, BHati)
+	SHati := v.sHat(i, xored)
+
+	if 0 <= i && i < last {
+		BHatiPlus1 = v.lt(SHati)
+	} else if i == last {
+		BHatiPlus1 = KHat.mixKey(last+1, SHati)
+	} else {
+		fmt.Printf("Round is out of range\n")
+	}
+
+	return BHatiPlus1
+}
+
+func (v *variant) rInverse(i int, BHatiPlus1 Bitstring,
+	KHat keyMixer) Bitstring {
+	var xored Bitstring
+	var BHati Bitstring
+	var SHati Bitstring
+	last := v.lastRound()
+
+	if 0 <= i && i < last {
+		SHati = v.ltInverse(BHatiPlus1)
+	} else if i == last {
+		SHati = KHat.mixKey(last+1, BHatiPlus1)
+	} else {
+		fmt.Printf("Round is out of range\n")
+	}
+
+	xored = v.sHatInverse(i, SHati)
+	BHati = KHat.mixKey(i, xored)
+
+	return BHati
+}
+
+func (v *variant) rBitslice(i int, Bi Bitstring, K keyMixer) Bitstring {
+	var xored Bitstring
+	var BiPlus1 Bitstring
+	last := v.lastRound()
+
+	// 1. Key mixing
+	xored = K.mixKey(i, Bi)
+
+	// 2. S Boxes
+	Si := v.sBitslice(i, xored.QuadSplit())
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		BiPlus1 = K.mixKey(last+1, xored.QuadJoin(Si))
+	} else {
+		BiPlus1 = xored.QuadJoin(v.ltBitslice(Si))
+	}
+
+	return BiPlus1
+}
+
+func (v *variant) rBitsliceInverse(i int, BiPlus1 Bitstring,
+	K keyMixer) Bitstring {
+	var xoredbitslice Bitslice
+	var Bi Bitstring
+	var SiTemp Bitstring
+	var Si Bitslice
+	last := v.lastRound()
+
+	// 3. Linear Transformation
+	if i == last {
+		// In the last round, replaced by an additional key mixing
+		SiTemp = K.mixKey(last+1, BiPlus1)
+		Si = SiTemp.QuadSplit()
+	} else {
+		Si = v.ltBitsliceInverse(BiPlus1.QuadSplit())
+	}
+
+	// 2. S Boxes
+	xoredbitslice = v.sBitsliceInverse(i, Si)
+
+	// 1. Key mixing
+	Bi = K.mixKey(i, Bi.QuadJoin(xoredbitslice))
+
+	return Bi
+}
+
+// Method makeSubkeys expands the 256-bit Bitstring 'userkey' into the
+// subkeys K and KHat, producing as many as the rounds of 'v' require.
+func (v *variant) makeSubkeys(userkey Bitstring) (Bitslice, Bitslice) {
+	n := v.subkeyCount()
+
+	// Convert the userkey to 8 32-bit words.
+	w := make(Bitmap, 4*n+8)
+	for i := -8; i < 0; i++ {
+		w[i] = userkey[(i+8)*32 : (i+9)*32]
+	}
+
+	// Expand the 8 words to a prekey w0 ... w(4n-1) with the affine
+	// recurrence.
+	var tempbs Bitstring
+	for i := 0; i < 4*n; i++ {
+		tempbsl := Bitslice{w[i-8], w[i-5], w[i-3], w[i-1],
+			tempbs.FromInt(v.Phi, 32),
+			tempbs.FromInt(i, 32)}
+		tempbs = tempbs.Xor(tempbsl)
+		w[i] = tempbs.RotateLeft(11)
+	}
+
+	// The round keys are now calculated from the prekeys using the
+	// S-Boxes in bitslice mode. Each k[i] is a 32-bit Bitstring.
+	k := make(Bitslice, 4*n)
+	for i := 0; i < n; i++ {
+		whichS := v.keyScheduleBox(i)
+		var input Bitstring
+		for j := 0; j < 32; j++ {
+			input = Bitstring(w[0+4*i][j]) +
+				Bitstring(w[1+4*i][j]) +
+				Bitstring(w[2+4*i][j]) +
+				Bitstring(w[3+4*i][j])
+			output := v.s(whichS, input)
+			for l := 0; l < 4; l++ {
+				k[l+4*i] = k[l+4*i] + Bitstring(output[l])
+			}
+		}
+	}
+
+	// We then renumber the 32-bit values k_j as 128-bit subkeys K_i
+	K := Bitslice{}
+	for i := 0; i < n; i++ {
+		K = append(K, k[4*i]+k[4*i+1]+k[4*i+2]+k[4*i+3])
+	}
+
+	// We now apply IP to the round key in order to place the key bits
+	// in the correct column.
+	KHat := Bitslice{}
+	for i := 0; i < n; i++ {
+		KHat = append(KHat, IP(K[i]))
+	}
+
+	return K, KHat
+}
+
+// Method encrypt runs the rounds of 'v' over 'plainText' by the normal
+// algorithm using the subkeys 'KHat'.
+func (v *variant) encrypt(plainText Bitstring, KHat keyMixer) Bitstring {
+	BHat := IP(plainText)
+	for i := v.StartRound; i <= v.lastRound(); i++ {
+		BHat = v.r(i, BHat, KHat)
+	}
+	return FP(BHat)
+}
+
+// Method encryptBitslice runs the rounds of 'v' over 'plainText' by the
+// bitslice algorithm using the subkeys 'K'.
+func (v *variant) encryptBitslice(plainText Bitstring, K keyMixer) Bitstring {
+	B := plainText
+	for i := v.StartRound; i <= v.lastRound(); i++ {
+		B = v.rBitslice(i, B, K)
+	}
+	return B
+}
+
+// Method decrypt runs the rounds of 'v' in reverse over 'cipherText' by
+// the normal algorithm using the subkeys 'KHat'.
+func (v *variant) decrypt(cipherText Bitstring, KHat keyMixer) Bitstring {
+	BHat := FPInverse(cipherText)
+	for i := v.lastRound(); i >= v.StartRound; i-- {
+		BHat = v.rInverse(i, BHat, KHat)
+	}
+	return IPInverse(BHat)
+}
+
+// Method decryptBitslice runs the rounds of 'v' in reverse over
+// 'cipherText' by the bitslice algorithm using the subkeys 'K'.
+func (v *variant) decryptBitslice(cipherText Bitstring,
+	K keyMixer) Bitstring {
+	B := cipherText
+	for i := v.lastRound(); i >= v.StartRound; i-- {
+		B = v.rBitsliceInverse(i, B, K)
+	}
+	return B
+}
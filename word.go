@@ -0,0 +1,201 @@
+package serpent
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+//go:generate go run ./cmd/serpent sboxcircuit -o sbox_words.go
+//go:generate go run -tags nounrolled ./internal/gen -o rounds_words.go
+
+// The word core runs the bitslice algorithm on 32-bit words: a block is
+// held as 4 words, bit j of word k being bit 32*k+j of the Bitstring, the
+// layout of QuadSplit. The S-Boxes of Serpent1 are applied by the circuits
+// in sbox_words.go, generated by "serpent sboxcircuit"; other S-Boxes and
+// linear transformations fall back to table lookups. The full Serpent-1
+// runs the rounds of rounds_words.go instead, unrolled by internal/gen.
+
+// Function loadWords reads a 16-byte block into words.
+func loadWords(b []byte) (x [4]uint32) {
+	for k := range x {
+		x[k] = binary.LittleEndian.Uint32(b[4*k:])
+	}
+	return
+}
+
+// Function storeWords writes the words of a block into 16 bytes.
+func storeWords(b []byte, x *[4]uint32) {
+	for k := range x {
+		binary.LittleEndian.PutUint32(b[4*k:], x[k])
+	}
+}
+
+// Function longKeyWords lengthens a byte key of up to 32 bytes as
+// makeLongkey does and returns it as 8 words.
+func longKeyWords(key []byte) (w [8]uint32) {
+	var long [32]byte
+	copy(long[:], key)
+	if len(key) < 32 {
+		long[len(key)] = 1
+	}
+	for k := range w {
+		w[k] = binary.LittleEndian.Uint32(long[4*k:])
+	}
+	wipeBytes(long[:])
+	return
+}
+
+// Function wordsFromBitstring converts a 128-bit Bitstring into words.
+func wordsFromBitstring(s Bitstring) [4]uint32 {
+	return loadWords(s.Bytes())
+}
+
+// Function wordsToBitstring converts words into a 128-bit Bitstring.
+func wordsToBitstring(x *[4]uint32) Bitstring {
+	var b [16]byte
+	storeWords(b[:], x)
+	return BitstringFromBytes(b[:])
+}
+
+// Method sWords applies S-Box 'box' to the 32 nibbles of 'x'.
+func (v *variant) sWords(box int, x *[4]uint32) {
+	if v.standardSBoxes {
+		wordSBoxes[v.box(box)](x)
+		return
+	}
+	applyWords(v.SBoxes[v.box(box)], x)
+}
+
+// Method sWordsInverse applies the inverse of S-Box 'box' to the 32
+// nibbles of 'x'.
+func (v *variant) sWordsInverse(box int, x *[4]uint32) {
+	if v.standardSBoxes {
+		wordSBoxesInverse[v.box(box)](x)
+		return
+	}
+	applyWords(v.SBoxes[v.box(box)].Inverse(), x)
+}
+
+// Function applyWords applies 'sbox' to each nibble of 'x' by table
+// lookup.
+func applyWords(sbox SBox, x *[4]uint32) {
+	var y [4]uint32
+	for j := uint(0); j < 32; j++ {
+		in := 0
+		for l := uint(0); l < 4; l++ {
+			in |= int(x[l]>>j&1) << l
+		}
+		out := sbox[in]
+		for l := uint(0); l < 4; l++ {
+			y[l] |= uint32(out>>l&1) << j
+		}
+	}
+	*x = y
+}
+
+// Method ltWords applies the linear transformation to 'x'.
+func (v *variant) ltWords(x *[4]uint32) {
+	if !v.standardLT {
+		*x = wordsFromBitstring(FP(v.lt(IP(wordsToBitstring(x)))))
+		return
+	}
+	x0 := bits.RotateLeft32(x[0], 13)
+	x2 := bits.RotateLeft32(x[2], 3)
+	x1 := x[1] ^ x0 ^ x2
+	x3 := x[3] ^ x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x[0] = bits.RotateLeft32(x0, 5)
+	x[1] = x1
+	x[2] = bits.RotateLeft32(x2, 22)
+	x[3] = x3
+}
+
+// Method ltWordsInverse applies the inverse linear transformation to 'x'.
+func (v *variant) ltWordsInverse(x *[4]uint32) {
+	if !v.standardLT {
+		*x = wordsFromBitstring(FP(v.ltInverse(IP(wordsToBitstring(x)))))
+		return
+	}
+	x2 := bits.RotateLeft32(x[2], -22)
+	x0 := bits.RotateLeft32(x[0], -5)
+	x1, x3 := x[1], x[3]
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x[0] = bits.RotateLeft32(x0, -13)
+	x[1] = x1
+	x[2] = bits.RotateLeft32(x2, -3)
+	x[3] = x3
+}
+
+// Method wordSubkeys expands the 256-bit user key 'key' into the subkeys
+// K in word form, as makeSubkeys does. The prekeys are wiped.
+func (v *variant) wordSubkeys(key *[8]uint32) [][4]uint32 {
+	n := v.subkeyCount()
+	w := make([]uint32, 4*n+8)
+	copy(w, key[:])
+	for i := 0; i < 4*n; i++ {
+		w[i+8] = bits.RotateLeft32(w[i]^w[i+3]^w[i+5]^w[i+7]^
+			uint32(v.Phi)^uint32(i), 11)
+	}
+	K := make([][4]uint32, n)
+	for i := range K {
+		copy(K[i][:], w[4*i+8:4*i+12])
+		v.sWords(v.keyScheduleBox(i), &K[i])
+	}
+	for i := range w {
+		w[i] = 0
+	}
+	return K
+}
+
+// Method encryptWords runs the rounds of 'v' over the block 'x' using the
+// subkeys 'K'.
+func (v *variant) encryptWords(x *[4]uint32, K [][4]uint32) {
+	if v.unrolled {
+		encryptWordsUnrolled(x, K)
+		return
+	}
+	last := v.lastRound()
+	for i := v.StartRound; i <= last; i++ {
+		xorWords(x, &K[i])
+		v.sWords(i, x)
+		if i == last {
+			xorWords(x, &K[last+1])
+		} else {
+			v.ltWords(x)
+		}
+	}
+}
+
+// Method decryptWords runs the rounds of 'v' in reverse over the block
+// 'x' using the subkeys 'K'.
+func (v *variant) decryptWords(x *[4]uint32, K [][4]uint32) {
+	if v.unrolled {
+		decryptWordsUnrolled(x, K)
+		return
+	}
+	last := v.lastRound()
+	for i := last; i >= v.StartRound; i-- {
+		if i == last {
+			xorWords(x, &K[last+1])
+		} else {
+			v.ltWordsInverse(x)
+		}
+		v.sWordsInverse(i, x)
+		xorWords(x, &K[i])
+	}
+}
+
+func xorWords(x, k *[4]uint32) {
+	x[0] ^= k[0]
+	x[1] ^= k[1]
+	x[2] ^= k[2]
+	x[3] ^= k[3]
+}
@@ -0,0 +1,78 @@
+package serpent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Function TestWordCore checks the word core against the bitslice
+// algorithm, including parameter sets that need the table fallbacks.
+func TestWordCore(t *testing.T) {
+	shuffled := Serpent1
+	shuffled.SBoxes = []SBox{SBoxDecimalTable[5], SBoxDecimalTable[2],
+		SBoxDecimalTable[7]}
+	swapped := Serpent1
+	swapped.LTTable, swapped.LTTableInverse = LTTableInverse, LTTable
+	reduced := Serpent1
+	reduced.StartRound, reduced.Rounds = 6, 4
+
+	r := rand.New(rand.NewSource(1))
+	for _, p := range []Params{Serpent1, shuffled, swapped, reduced} {
+		key := randomBytes(r, 16)
+		c, err := New(p, BitstringFromBytes(key))
+		if err != nil {
+			t.Fatalf("New failed: %v\n", err)
+		}
+		v := newVariant(p)
+		w := longKeyWords(key)
+		K := v.wordSubkeys(&w)
+		Kc, _ := c.Subkeys()
+		for i := range K {
+			if wordsToBitstring(&K[i]) != Kc[i] {
+				t.Errorf("subkey %d differs\n", i)
+			}
+		}
+
+		plain := randomBytes(r, 16)
+		x := loadWords(plain)
+		v.encryptWords(&x, K)
+		want := c.EncryptBitslice(BitstringFromBytes(plain))
+		if wordsToBitstring(&x) != want {
+			t.Errorf("word core gives %s, want %s\n",
+				wordsToBitstring(&x).ToHexstring(), want.ToHexstring())
+		}
+		v.decryptWords(&x, K)
+		if x != loadWords(plain) {
+			t.Errorf("decryptWords does not invert encryptWords\n")
+		}
+	}
+}
+
+// Function TestUnrolledRounds checks the generated unrolled rounds against
+// the loop over the rounds.
+func TestUnrolledRounds(t *testing.T) {
+	if !haveUnrolled {
+		t.Skip("built without the unrolled rounds")
+	}
+	loop := newVariant(Serpent1)
+	if !loop.unrolled {
+		t.Fatalf("Serpent1 does not use the unrolled rounds")
+	}
+	loop.unrolled = false
+	r := rand.New(rand.NewSource(2))
+	for n := 0; n < 100; n++ {
+		w := longKeyWords(randomBytes(r, 32))
+		K := loop.wordSubkeys(&w)
+		x := loadWords(randomBytes(r, 16))
+		want, got := x, x
+		loop.encryptWords(&want, K)
+		encryptWordsUnrolled(&got, K)
+		if got != want {
+			t.Fatalf("encryptWordsUnrolled gives %x, want %x", got, want)
+		}
+		decryptWordsUnrolled(&got, K)
+		if got != x {
+			t.Fatalf("decryptWordsUnrolled gives %x, want %x", got, x)
+		}
+	}
+}